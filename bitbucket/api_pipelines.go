@@ -2288,10 +2288,17 @@ Retrieve the log file for a given step of a pipeline.  This endpoint supports (a
  * @param repoSlug The repository.
  * @param pipelineUuid The UUID of the pipeline.
  * @param stepUuid The UUID of the step.
+ * @param optional nil or *PipelinesApiGetPipelineStepLogForRepositoryOpts - Optional Parameters:
+     * @param "Range_" (optional.String) -  The range of bytes to retrieve, e.g. bytes=1024-
 
-
+@return []byte
 */
-func (a *PipelinesApiService) GetPipelineStepLogForRepository(ctx context.Context, workspace string, repoSlug string, pipelineUuid string, stepUuid string) (*http.Response, error) {
+
+type PipelinesApiGetPipelineStepLogForRepositoryOpts struct {
+	Range_ optional.String `json:"Range"`
+}
+
+func (a *PipelinesApiService) GetPipelineStepLogForRepository(ctx context.Context, workspace string, repoSlug string, pipelineUuid string, stepUuid string, localVarOptionals *PipelinesApiGetPipelineStepLogForRepositoryOpts) ([]byte, *http.Response, error) {
 	var (
 		localVarHttpMethod = strings.ToUpper("Get")
 		localVarPostBody   interface{}
@@ -2327,20 +2334,23 @@ func (a *PipelinesApiService) GetPipelineStepLogForRepository(ctx context.Contex
 	if localVarHttpHeaderAccept != "" {
 		localVarHeaderParams["Accept"] = localVarHttpHeaderAccept
 	}
+	if localVarOptionals != nil && localVarOptionals.Range_.IsSet() {
+		localVarHeaderParams["Range"] = parameterToString(localVarOptionals.Range_.Value(), "")
+	}
 	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	localVarHttpResponse, err := a.client.callAPI(r)
 	if err != nil || localVarHttpResponse == nil {
-		return localVarHttpResponse, err
+		return nil, localVarHttpResponse, err
 	}
 
 	localVarBody, err := ioutil.ReadAll(localVarHttpResponse.Body)
 	localVarHttpResponse.Body.Close()
 	if err != nil {
-		return localVarHttpResponse, err
+		return nil, localVarHttpResponse, err
 	}
 
 	if localVarHttpResponse.StatusCode >= 300 {
@@ -2354,10 +2364,10 @@ func (a *PipelinesApiService) GetPipelineStepLogForRepository(ctx context.Contex
 			err = a.client.decode(&v, localVarBody, localVarHttpResponse.Header.Get("Content-Type"))
 			if err != nil {
 				newErr.error = err.Error()
-				return localVarHttpResponse, newErr
+				return nil, localVarHttpResponse, newErr
 			}
 			newErr.model = v
-			return localVarHttpResponse, newErr
+			return nil, localVarHttpResponse, newErr
 		}
 
 		if localVarHttpResponse.StatusCode == 404 {
@@ -2365,10 +2375,10 @@ func (a *PipelinesApiService) GetPipelineStepLogForRepository(ctx context.Contex
 			err = a.client.decode(&v, localVarBody, localVarHttpResponse.Header.Get("Content-Type"))
 			if err != nil {
 				newErr.error = err.Error()
-				return localVarHttpResponse, newErr
+				return nil, localVarHttpResponse, newErr
 			}
 			newErr.model = v
-			return localVarHttpResponse, newErr
+			return nil, localVarHttpResponse, newErr
 		}
 
 		if localVarHttpResponse.StatusCode == 416 {
@@ -2376,16 +2386,16 @@ func (a *PipelinesApiService) GetPipelineStepLogForRepository(ctx context.Contex
 			err = a.client.decode(&v, localVarBody, localVarHttpResponse.Header.Get("Content-Type"))
 			if err != nil {
 				newErr.error = err.Error()
-				return localVarHttpResponse, newErr
+				return nil, localVarHttpResponse, newErr
 			}
 			newErr.model = v
-			return localVarHttpResponse, newErr
+			return nil, localVarHttpResponse, newErr
 		}
 
-		return localVarHttpResponse, newErr
+		return nil, localVarHttpResponse, newErr
 	}
 
-	return localVarHttpResponse, nil
+	return localVarBody, localVarHttpResponse, nil
 }
 
 /*
@@ -3303,10 +3313,21 @@ Find pipelines
  * @param ctx context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
  * @param workspace This can either be the workspace ID (slug) or the workspace UUID surrounded by curly-braces, for example &#x60;{workspace UUID}&#x60;.
  * @param repoSlug The repository.
+ * @param optional nil or *PipelinesApiGetPipelinesForRepositoryOpts - Optional Parameters:
+     * @param "Sort" (optional.String) -  The field to sort on, prefixed with - for a descending order.
+     * @param "Page" (optional.Int32) -  The page number.
+     * @param "Pagelen" (optional.Int32) -  The page length.
 
 @return PaginatedPipelines
 */
-func (a *PipelinesApiService) GetPipelinesForRepository(ctx context.Context, workspace string, repoSlug string) (PaginatedPipelines, *http.Response, error) {
+
+type PipelinesApiGetPipelinesForRepositoryOpts struct {
+	Sort    optional.String `json:"sort"`
+	Page    optional.Int32  `json:"page"`
+	Pagelen optional.Int32  `json:"pagelen"`
+}
+
+func (a *PipelinesApiService) GetPipelinesForRepository(ctx context.Context, workspace string, repoSlug string, localVarOptionals *PipelinesApiGetPipelinesForRepositoryOpts) (PaginatedPipelines, *http.Response, error) {
 	var (
 		localVarHttpMethod  = strings.ToUpper("Get")
 		localVarPostBody    interface{}
@@ -3324,6 +3345,15 @@ func (a *PipelinesApiService) GetPipelinesForRepository(ctx context.Context, wor
 	localVarQueryParams := url.Values{}
 	localVarFormParams := url.Values{}
 
+	if localVarOptionals != nil && localVarOptionals.Sort.IsSet() {
+		localVarQueryParams.Add("sort", parameterToString(localVarOptionals.Sort.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.Page.IsSet() {
+		localVarQueryParams.Add("page", parameterToString(localVarOptionals.Page.Value(), ""))
+	}
+	if localVarOptionals != nil && localVarOptionals.Pagelen.IsSet() {
+		localVarQueryParams.Add("pagelen", parameterToString(localVarOptionals.Pagelen.Value(), ""))
+	}
 	// to determine the Content-Type header
 	localVarHttpContentTypes := []string{"application/json"}
 
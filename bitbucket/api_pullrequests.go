@@ -17,6 +17,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 )
 
@@ -162,12 +163,14 @@ Returns all pull requests authored by the specified user.  By default only open
  * @param selectedUser This can either be the username of the pull request author, the author&#39;s UUID surrounded by curly-braces, for example: &#x60;{account UUID}&#x60;, or the author&#39;s Atlassian ID.
  * @param optional nil or *PullrequestsApiPullrequestsSelectedUserGetOpts - Optional Parameters:
      * @param "State" (optional.String) -  Only return pull requests that are in this state. This parameter can be repeated.
+     * @param "Fields" (optional.String) -  Adds or removes fields of the response, for example &#x60;+values.participants&#x60; to get the participants of each pull request. See [partial responses](/cloud/bitbucket/rest/intro/#partial-response) for more details.
 
 @return PaginatedPullrequests
 */
 
 type PullrequestsApiPullrequestsSelectedUserGetOpts struct {
-	State optional.String
+	State  optional.String
+	Fields optional.String
 }
 
 func (a *PullrequestsApiService) PullrequestsSelectedUserGet(ctx context.Context, selectedUser string, localVarOptionals *PullrequestsApiPullrequestsSelectedUserGetOpts) (PaginatedPullrequests, *http.Response, error) {
@@ -190,6 +193,9 @@ func (a *PullrequestsApiService) PullrequestsSelectedUserGet(ctx context.Context
 	if localVarOptionals != nil && localVarOptionals.State.IsSet() {
 		localVarQueryParams.Add("state", parameterToString(localVarOptionals.State.Value(), ""))
 	}
+	if localVarOptionals != nil && localVarOptionals.Fields.IsSet() {
+		localVarQueryParams.Add("fields", parameterToString(localVarOptionals.Fields.Value(), ""))
+	}
 	// to determine the Content-Type header
 	localVarHttpContentTypes := []string{"application/json"}
 
@@ -283,14 +289,15 @@ Returns the repository&#39;s default reviewers.  These are the users that are au
  * @param repoSlug This can either be the repository slug or the UUID of the repository, surrounded by curly-braces, for example: &#x60;{repository UUID}&#x60;.
  * @param workspace This can either be the workspace ID (slug) or the workspace UUID surrounded by curly-braces, for example: &#x60;{workspace UUID}&#x60;.
 
-
+@return PaginatedUsers
 */
-func (a *PullrequestsApiService) RepositoriesWorkspaceRepoSlugDefaultReviewersGet(ctx context.Context, repoSlug string, workspace string) (*http.Response, error) {
+func (a *PullrequestsApiService) RepositoriesWorkspaceRepoSlugDefaultReviewersGet(ctx context.Context, repoSlug string, workspace string) (PaginatedUsers, *http.Response, error) {
 	var (
-		localVarHttpMethod = strings.ToUpper("Get")
-		localVarPostBody   interface{}
-		localVarFileName   string
-		localVarFileBytes  []byte
+		localVarHttpMethod  = strings.ToUpper("Get")
+		localVarPostBody    interface{}
+		localVarFileName    string
+		localVarFileBytes   []byte
+		localVarReturnValue PaginatedUsers
 	)
 
 	// create path and map variables
@@ -334,18 +341,24 @@ func (a *PullrequestsApiService) RepositoriesWorkspaceRepoSlugDefaultReviewersGe
 	}
 	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
-		return nil, err
+		return localVarReturnValue, nil, err
 	}
 
 	localVarHttpResponse, err := a.client.callAPI(r)
 	if err != nil || localVarHttpResponse == nil {
-		return localVarHttpResponse, err
+		return localVarReturnValue, localVarHttpResponse, err
 	}
 
 	localVarBody, err := ioutil.ReadAll(localVarHttpResponse.Body)
 	localVarHttpResponse.Body.Close()
 	if err != nil {
-		return localVarHttpResponse, err
+		return localVarReturnValue, localVarHttpResponse, err
+	}
+
+	if localVarHttpResponse.StatusCode < 300 {
+		// If we succeed, return the data, otherwise pass on to decode error.
+		err = a.client.decode(&localVarReturnValue, localVarBody, localVarHttpResponse.Header.Get("Content-Type"))
+		return localVarReturnValue, localVarHttpResponse, err
 	}
 
 	if localVarHttpResponse.StatusCode >= 300 {
@@ -359,16 +372,16 @@ func (a *PullrequestsApiService) RepositoriesWorkspaceRepoSlugDefaultReviewersGe
 			err = a.client.decode(&v, localVarBody, localVarHttpResponse.Header.Get("Content-Type"))
 			if err != nil {
 				newErr.error = err.Error()
-				return localVarHttpResponse, newErr
+				return localVarReturnValue, localVarHttpResponse, newErr
 			}
 			newErr.model = v
-			return localVarHttpResponse, newErr
+			return localVarReturnValue, localVarHttpResponse, newErr
 		}
 
-		return localVarHttpResponse, newErr
+		return localVarReturnValue, localVarHttpResponse, newErr
 	}
 
-	return localVarHttpResponse, nil
+	return localVarReturnValue, localVarHttpResponse, nil
 }
 
 /*
@@ -808,15 +821,19 @@ Returns all pull requests on the specified repository.  By default only open pul
  * @param repoSlug This can either be the repository slug or the UUID of the repository, surrounded by curly-braces, for example: &#x60;{repository UUID}&#x60;.
  * @param workspace This can either be the workspace ID (slug) or the workspace UUID surrounded by curly-braces, for example: &#x60;{workspace UUID}&#x60;.
  * @param optional nil or *PullrequestsApiRepositoriesWorkspaceRepoSlugPullrequestsGetOpts - Optional Parameters:
-     * @param "State" (optional.String) -  Only return pull requests that are in this state. This parameter can be repeated.
+     * @param "State" (optional.Interface of []string) -  Only return pull requests that are in this state. This parameter can be repeated.
+     * @param "Pagelen" (optional.Int32) -  Number of pull requests per page.
+     * @param "Q" (optional.String) -  Query string to narrow down the response, see [filtering and sorting](/cloud/bitbucket/rest/intro/#filtering).
+     * @param "Fields" (optional.String) -  Adds or removes fields of the response, for example &#x60;+values.participants&#x60; to get the participants of each pull request. See [partial responses](/cloud/bitbucket/rest/intro/#partial-response) for more details.
 
 @return PaginatedPullrequests
 */
 
 type PullrequestsApiRepositoriesWorkspaceRepoSlugPullrequestsGetOpts struct {
-	State   optional.String `json:"state"`
-	Pagelen optional.Int32  `json:"pagelen"`
-	Q       optional.String `json:"q"`
+	State   optional.Interface `json:"state"`
+	Pagelen optional.Int32     `json:"pagelen"`
+	Q       optional.String    `json:"q"`
+	Fields  optional.String    `json:"fields"`
 }
 
 func (a *PullrequestsApiService) RepositoriesWorkspaceRepoSlugPullrequestsGet(ctx context.Context, repoSlug string, workspace string, localVarOptionals *PullrequestsApiRepositoriesWorkspaceRepoSlugPullrequestsGetOpts) (PaginatedPullrequests, *http.Response, error) {
@@ -838,15 +855,25 @@ func (a *PullrequestsApiService) RepositoriesWorkspaceRepoSlugPullrequestsGet(ct
 	localVarFormParams := url.Values{}
 
 	if localVarOptionals != nil && localVarOptionals.State.IsSet() {
-		localVarQueryParams.Add("state", parameterToString(localVarOptionals.State.Value(), ""))
+		t := localVarOptionals.State.Value()
+		if reflect.TypeOf(t).Kind() == reflect.Slice {
+			s := reflect.ValueOf(t)
+			for i := 0; i < s.Len(); i++ {
+				localVarQueryParams.Add("state", parameterToString(s.Index(i), "multi"))
+			}
+		} else {
+			localVarQueryParams.Add("state", parameterToString(t, "multi"))
+		}
 	}
 	if localVarOptionals != nil && localVarOptionals.Pagelen.IsSet() {
 		localVarQueryParams.Add("pagelen", parameterToString(localVarOptionals.Pagelen.Value(), ""))
 	}
-
 	if localVarOptionals != nil && localVarOptionals.Q.IsSet() {
 		localVarQueryParams.Add("q", parameterToString(localVarOptionals.Q.Value(), ""))
 	}
+	if localVarOptionals != nil && localVarOptionals.Fields.IsSet() {
+		localVarQueryParams.Add("fields", parameterToString(localVarOptionals.Fields.Value(), ""))
+	}
 	// to determine the Content-Type header
 	localVarHttpContentTypes := []string{"application/json"}
 
@@ -2684,13 +2711,15 @@ When merging a pull request takes too long, the client receives a task ID along
  * @param workspace This can either be the workspace ID (slug) or the workspace UUID surrounded by curly-braces, for example: &#x60;{workspace UUID}&#x60;.
 
 
+@return PullrequestMergeTaskStatus
 */
-func (a *PullrequestsApiService) RepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdMergeTaskStatusTaskIdGet(ctx context.Context, pullRequestId int32, repoSlug string, taskId string, workspace string) (*http.Response, error) {
+func (a *PullrequestsApiService) RepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdMergeTaskStatusTaskIdGet(ctx context.Context, pullRequestId int32, repoSlug string, taskId string, workspace string) (PullrequestMergeTaskStatus, *http.Response, error) {
 	var (
-		localVarHttpMethod = strings.ToUpper("Get")
-		localVarPostBody   interface{}
-		localVarFileName   string
-		localVarFileBytes  []byte
+		localVarHttpMethod  = strings.ToUpper("Get")
+		localVarPostBody    interface{}
+		localVarFileName    string
+		localVarFileBytes   []byte
+		localVarReturnValue PullrequestMergeTaskStatus
 	)
 
 	// create path and map variables
@@ -2736,18 +2765,24 @@ func (a *PullrequestsApiService) RepositoriesWorkspaceRepoSlugPullrequestsPullRe
 	}
 	r, err := a.client.prepareRequest(ctx, localVarPath, localVarHttpMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, localVarFileName, localVarFileBytes)
 	if err != nil {
-		return nil, err
+		return localVarReturnValue, nil, err
 	}
 
 	localVarHttpResponse, err := a.client.callAPI(r)
 	if err != nil || localVarHttpResponse == nil {
-		return localVarHttpResponse, err
+		return localVarReturnValue, localVarHttpResponse, err
 	}
 
 	localVarBody, err := ioutil.ReadAll(localVarHttpResponse.Body)
 	localVarHttpResponse.Body.Close()
 	if err != nil {
-		return localVarHttpResponse, err
+		return localVarReturnValue, localVarHttpResponse, err
+	}
+
+	if localVarHttpResponse.StatusCode < 300 {
+		// If we succeed, return the data, otherwise pass on to decode error.
+		err = a.client.decode(&localVarReturnValue, localVarBody, localVarHttpResponse.Header.Get("Content-Type"))
+		return localVarReturnValue, localVarHttpResponse, err
 	}
 
 	if localVarHttpResponse.StatusCode >= 300 {
@@ -2756,10 +2791,10 @@ func (a *PullrequestsApiService) RepositoriesWorkspaceRepoSlugPullrequestsPullRe
 			error: localVarHttpResponse.Status,
 		}
 
-		return localVarHttpResponse, newErr
+		return localVarReturnValue, localVarHttpResponse, newErr
 	}
 
-	return localVarHttpResponse, nil
+	return localVarReturnValue, localVarHttpResponse, nil
 }
 
 /*
@@ -12,4 +12,10 @@ package bitbucket
 
 type PipelineState struct {
 	Type_ string `json:"type"`
+	// The name of pipeline state (PENDING, IN_PROGRESS, COMPLETED).
+	Name string `json:"name,omitempty"`
+	// A result of a completed state of a pipeline.
+	Result *PipelineStateCompletedResult `json:"result,omitempty"`
+	// A stage of an in progress state of a pipeline.
+	Stage *PipelineStateInProgressStage `json:"stage,omitempty"`
 }
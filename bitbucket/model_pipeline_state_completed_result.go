@@ -12,4 +12,6 @@ package bitbucket
 
 type PipelineStateCompletedResult struct {
 	Type_ string `json:"type"`
+	// The name of the result (SUCCESSFUL, FAILED, ERROR, STOPPED, EXPIRED).
+	Name string `json:"name,omitempty"`
 }
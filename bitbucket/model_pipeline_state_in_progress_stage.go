@@ -12,4 +12,6 @@ package bitbucket
 
 type PipelineStateInProgressStage struct {
 	Type_ string `json:"type"`
+	// The name of the stage (RUNNING, PAUSED).
+	Name string `json:"name,omitempty"`
 }
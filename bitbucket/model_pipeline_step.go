@@ -18,6 +18,8 @@ type PipelineStep struct {
 	Type_ string `json:"type"`
 	// The UUID identifying the step.
 	Uuid string `json:"uuid,omitempty"`
+	// The name of the step.
+	Name string `json:"name,omitempty"`
 	// The timestamp when the step execution was started. This is not set when the step hasn't executed yet.
 	StartedOn time.Time `json:"started_on,omitempty"`
 	// The timestamp when the step execution was completed. This is not set if the step is still in progress.
@@ -12,4 +12,8 @@ package bitbucket
 
 type PipelineStepState struct {
 	Type_ string `json:"type"`
+	// The name of pipeline step state (PENDING, READY, IN_PROGRESS, COMPLETED).
+	Name string `json:"name,omitempty"`
+	// A result of a completed state of a pipeline step.
+	Result *PipelineStepStateCompletedResult `json:"result,omitempty"`
 }
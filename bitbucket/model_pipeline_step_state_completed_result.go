@@ -12,4 +12,6 @@ package bitbucket
 
 type PipelineStepStateCompletedResult struct {
 	Type_ string `json:"type"`
+	// The name of the result (SUCCESSFUL, FAILED, ERROR, STOPPED, NOT_RUN, EXPIRED).
+	Name string `json:"name,omitempty"`
 }
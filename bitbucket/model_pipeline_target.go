@@ -12,4 +12,10 @@ package bitbucket
 
 type PipelineTarget struct {
 	Type_ string `json:"type"`
+	// The type of reference (branch/tag), for reference targets.
+	RefType string `json:"ref_type,omitempty"`
+	// The name of the reference, for reference targets.
+	RefName  string            `json:"ref_name,omitempty"`
+	Commit   *Commit           `json:"commit,omitempty"`
+	Selector *PipelineSelector `json:"selector,omitempty"`
 }
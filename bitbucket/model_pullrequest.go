@@ -35,8 +35,10 @@ type Pullrequest struct {
 	// The number of open tasks for a specific pull request.
 	TaskCount int32 `json:"task_count,omitempty"`
 	// A boolean flag indicating if merging the pull request closes the source branch.
-	CloseSourceBranch bool     `json:"close_source_branch,omitempty"`
-	ClosedBy          *Account `json:"closed_by,omitempty"`
+	CloseSourceBranch bool `json:"close_source_branch,omitempty"`
+	// A boolean flag indicating whether the pull request is a draft.
+	Draft    bool     `json:"draft,omitempty"`
+	ClosedBy *Account `json:"closed_by,omitempty"`
 	// Explains why a pull request was declined. This field is only applicable to pull requests in rejected state.
 	Reason string `json:"reason,omitempty"`
 	// The ISO8601 timestamp the request was created.
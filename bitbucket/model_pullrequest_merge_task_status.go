@@ -0,0 +1,19 @@
+/*
+ * Bitbucket API
+ *
+ * Code against the Bitbucket API to automate simple tasks, embed Bitbucket data into your own site, build mobile or desktop apps, or even add custom UI add-ons into Bitbucket itself using the Connect framework.
+ *
+ * API version: 2.0
+ * Contact: support@bitbucket.org
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+
+package bitbucket
+
+// The status of an asynchronous pull request merge.
+type PullrequestMergeTaskStatus struct {
+	// PENDING until the merge is done, then SUCCESS.
+	TaskStatus string `json:"task_status,omitempty"`
+	// The merged pull request, once the task succeeded.
+	MergeResult *Pullrequest `json:"merge_result,omitempty"`
+}
@@ -1,49 +1,237 @@
 /*
 Copyright © 2022 NAME HERE <EMAIL ADDRESS>
-
 */
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"github.com/erikgeiser/promptkit/textinput"
+	"github.com/go-git/go-git/v5"
+	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+	"github.com/vballestra/sv/config"
+	"github.com/vballestra/sv/sv"
+	"io"
+	"os"
+	"sort"
+	"strings"
 )
 
 var authCmd = &cobra.Command{
 	Use:   "auth",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
+	Short: "Manage the credentials",
+	Long: `Log in and out of the providers. The secrets (tokens or Bitbucket app passwords) are kept by the git
+credential helper, so a helper must be configured (git config --global credential.helper ...). They are stored for
+https://<host>/sv, apart from the credentials git uses for the remotes, and only read when no token is given by a
+flag, an environment variable or the profile.`,
+	// The local repository is optional here, it only gives the default provider and host
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		loadConfig()
+		if repo, err := git.PlainOpen(localRepo); err == nil {
+			if remote, err := repo.Remote(defaultOrigin); err == nil && len(remote.Config().URLs) > 0 {
+				detectOrigin(remote.Config().URLs[0])
+			}
+		}
+	},
+}
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Validate and store a token or an app password",
+	Long: `Validate a token (GitHub, GitLab, Gitea) or an app password (Bitbucket) and store it with the git credential helper.
+The secret is prompted for, or read from the standard input with --with-token.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		c, ctx := GetClient()
+		tp, host := authTarget()
+
+		username := *_username
+		if tp == BitbucketOriginType && username == "" {
+			input := textinput.New("Bitbucket username:")
+			if u, err := input.RunPrompt(); err != nil {
+				pterm.Fatal.Println(err)
+			} else {
+				username = u
+			}
+		}
 
-		repos, resp, err := c.RepositoriesApi.RepositoriesWorkspaceGet(ctx, account, nil)
-		if err != nil || resp.StatusCode != 200 {
-			fmt.Printf("Error occurred : %s\n", err)
-			return
+		secret, err := readSecret(tp)
+		if err != nil {
+			pterm.Fatal.Println(err)
 		}
 
-		fmt.Printf("Found : %d\n", repos.Size)
-		for _, repo := range repos.Values {
-			fmt.Printf(" - %s\n", repo.Name)
+		login, err := validateSecret(tp, host, username, secret)
+		if err != nil {
+			pterm.Fatal.Printfln("Cannot log in to %s: %v", host, err)
 		}
+
+		if err := config.StoreCredential(config.Credential{Host: host, Username: login, Secret: secret}); err != nil {
+			pterm.Fatal.Println(err)
+		}
+		pterm.Success.Printfln("Logged in to %s (%s) as %s", host, tp, login)
 	},
 }
 
-func init() {
-	rootCmd.AddCommand(authCmd)
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored credential of a host",
+	Long:  `Remove the credential of a host from the git credential helper`,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, host := authTarget()
+
+		if err := config.EraseCredential(host, *_username); err != nil {
+			pterm.Fatal.Printfln("Cannot log out of %s: %v", host, err)
+		}
+		pterm.Success.Printfln("Logged out of %s", host)
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the identity of each configured host",
+	Long: `Show the identity each host resolves to: the one of the remote, the ones of the profiles, github.com and
+bitbucket.org`,
+	Run: func(cmd *cobra.Command, args []string) {
+		targets := map[string]OriginType{
+			originHost(GitHubOriginType):    GitHubOriginType,
+			originHost(BitbucketOriginType): BitbucketOriginType,
+		}
+		if originType != UnknownOriginType {
+			targets[originHost(originType)] = originType
+		}
+		for _, name := range svConfig.ProfileNames() {
+			if host := svConfig.Profiles[name].Host; host != "" {
+				if _, ok := targets[host]; !ok {
+					targets[host] = hostOriginType(host)
+				}
+			}
+		}
+
+		hostNames := make([]string, 0, len(targets))
+		for host := range targets {
+			hostNames = append(hostNames, host)
+		}
+		sort.Strings(hostNames)
+
+		data := pterm.TableData{{"Host", "Provider", "Source", "Identity"}}
+		for _, host := range hostNames {
+			tp := targets[host]
+			source, username, secret := resolveSecret(tp, host)
+
+			identity := ""
+			if secret == "" {
+				identity = pterm.Gray("not logged in")
+			} else if tp == UnknownOriginType {
+				identity = pterm.Gray("unknown provider")
+			} else if login, err := validateSecret(tp, host, username, secret); err != nil {
+				identity = pterm.Red(fmt.Sprintf("invalid: %v", err))
+			} else {
+				identity = pterm.Green(login)
+			}
+			data = append(data, []string{host, tp.String(), source, identity})
+		}
+
+		if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+			pterm.Fatal.Println(err)
+		}
+	},
+}
+
+var authProvider, authHost string
+
+var authWithToken = false
+
+// authTarget is the provider and the host given on the command line, or the ones of the remote
+func authTarget() (OriginType, string) {
+	tp := originType
+	if authProvider != "" {
+		if t, err := parseOriginType(authProvider); err != nil {
+			pterm.Fatal.Println(err)
+		} else {
+			tp = t
+		}
+	} else if authHost != "" {
+		tp = hostOriginType(authHost)
+	}
+	if tp == UnknownOriginType {
+		tp = GitHubOriginType
+	}
+
+	host := authHost
+	if host == "" {
+		host = originHost(tp)
+	}
+	if host == "" {
+		pterm.Fatal.Printfln("The %s host is required", tp)
+	}
+	return tp, host
+}
+
+// hostOriginType finds the provider of a host with the remote patterns
+func hostOriginType(host string) OriginType {
+	for _, tp := range originTypes {
+		if re := origins[tp]; re.MatchString(fmt.Sprintf("https://%s/account/repository", host)) {
+			return tp
+		}
+	}
+	return UnknownOriginType
+}
+
+func readSecret(tp OriginType) (string, error) {
+	if authWithToken {
+		data, err := io.ReadAll(os.Stdin)
+		return strings.TrimSpace(string(data)), err
+	}
+
+	label := "Token:"
+	if tp == BitbucketOriginType {
+		label = "App password:"
+	}
+	input := textinput.New(label)
+	input.Hidden = true
+	return input.RunPrompt()
+}
+
+// resolveSecret finds the credentials of a host the way the other commands do: profile first, then credential helper
+func resolveSecret(tp OriginType, host string) (string, string, string) {
+	if name, p := svConfig.ForHost(host); p != nil {
+		if tp == BitbucketOriginType && p.AppPassword != "" {
+			return fmt.Sprintf("profile %s", name), p.Username, p.AppPassword
+		} else if tp != BitbucketOriginType && p.Token != "" {
+			return fmt.Sprintf("profile %s", name), p.Username, p.Token
+		}
+	}
+
+	if c, err := config.LoadCredential(host, ""); err == nil {
+		return "credential helper", c.Username, c.Secret
+	} else if !errors.Is(err, config.ErrNoCredential) {
+		pterm.Debug.Printfln("Cannot read the credential of %s: %v", host, err)
+	}
+	return "", "", ""
+}
 
-	// Here you will define your flags and configuration settings.
+// validateSecret returns the identity the secret authenticates on host
+func validateSecret(tp OriginType, host string, username string, secret string) (string, error) {
+	switch tp {
+	case GitHubOriginType:
+		apiUrl, graphQLUrl := githubApiUrls(host)
+		return sv.GitHubLogin(secret, apiUrl, graphQLUrl)
+	case BitbucketOriginType:
+		return sv.BitbucketLogin(username, secret)
+	case GitLabOriginType:
+		return sv.GitLabLogin(secret, sv.GitLabApiUrl(host))
+	case GiteaOriginType:
+		return sv.GiteaLogin(secret, sv.GiteaApiUrl(host))
+	}
+	return "", fmt.Errorf("unsupported provider for %s", host)
+}
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// authCmd.PersistentFlags().String("foo", "", "A help for foo")
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authLoginCmd)
+	authCmd.AddCommand(authLogoutCmd)
+	authCmd.AddCommand(authStatusCmd)
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// authCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	authCmd.PersistentFlags().StringVar(&authProvider, "provider", "", "Provider: github, bitbucket, gitlab or gitea (default value will be deduced from the host or the local repo)")
+	authCmd.PersistentFlags().StringVar(&authHost, "hostname", "", "Host to log in (default value will be deduced from the provider or the local repo)")
+	authLoginCmd.Flags().BoolVar(&authWithToken, "with-token", false, "Read the secret from the standard input")
 }
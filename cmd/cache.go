@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/vballestra/sv/sv"
+	"path/filepath"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the api cache",
+	Long: `The api responses are cached under the user cache directory ($XDG_CACHE_HOME/sv on Linux). They're served
+until they expire, then revalidated with the provider, which is cheap. With --offline only the cached responses are
+shown, --no-cache disables the cache.`,
+	// No need for a local repository here
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the cached api responses",
+	Run: func(cmd *cobra.Command, args []string) {
+		if dir, err := httpCacheDir(); err != nil {
+			pterm.Fatal.Println(err)
+		} else if err := sv.NewCache(dir).Clear(); err != nil {
+			pterm.Fatal.Println(err)
+		} else {
+			pterm.Success.Printfln("Cache %s cleared", dir)
+		}
+	},
+}
+
+// httpCacheDir is where the api responses are cached
+func httpCacheDir() (string, error) {
+	if dir, err := sv.DefaultCacheDir(); err != nil {
+		return "", err
+	} else {
+		return filepath.Join(dir, "http"), nil
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
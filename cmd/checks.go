@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/vballestra/sv/sv"
+	"os"
+	"time"
+)
+
+// checksCmd represents the checks command
+var checksCmd = &cobra.Command{
+	Use:   "checks [pr]",
+	Short: "List the checks of a PR",
+	Long: `List the checks of the last commit of a PR, the one of the current branch by default, with their conclusion
+and duration. The failed checks can be rerun with --rerun-failed, and their logs printed with --logs.
+The exit code is 1 when a check failed.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pr := pullRequestArg(GetSv(), args)
+
+		checks, err := pr.GetCheckRuns()
+		if err != nil {
+			pterm.Fatal.Printfln("cannot read the checks: %v", err)
+		}
+
+		failed := make([]sv.CheckRun, 0)
+		data := pterm.TableData{{"Name", "Status", "Duration", "Url"}}
+		for _, c := range checks {
+			if sv.IsFailedCheck(c) {
+				failed = append(failed, c)
+			}
+			data = append(data, []string{c.GetName(), renderCheckStatus(c), checkDuration(c), c.GetUrl()})
+		}
+		if isStructuredOutput() {
+			if err := printOutput(toCheckOutputs(checks)); err != nil {
+				pterm.Fatal.Println(err)
+			}
+		} else if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+			pterm.Fatal.Println(err)
+		}
+
+		if checksLogs {
+			for _, c := range failed {
+				if !c.HasLog() {
+					continue
+				}
+				pterm.DefaultSection.Println(c.GetName())
+				if log, err := pr.GetCheckLog(c); err != nil {
+					pterm.Error.Printfln("cannot read the log of %s: %v", c.GetName(), err)
+				} else {
+					_, _ = os.Stdout.Write(log)
+				}
+			}
+		}
+
+		if checksRerun {
+			rerun := make([]sv.CheckRun, 0, len(failed))
+			for _, c := range failed {
+				if c.CanRerun() {
+					rerun = append(rerun, c)
+				} else {
+					pterm.Warning.Printfln("%s cannot be rerun from here", c.GetName())
+				}
+			}
+			if len(rerun) == 0 {
+				pterm.Info.Println("No check to rerun")
+			} else if err := pr.RerunChecks(rerun); errors.Is(err, sv.ErrNotSupported) {
+				pterm.Warning.Println(err)
+			} else if err != nil {
+				pterm.Fatal.Printfln("cannot rerun the checks: %v", err)
+			} else {
+				pterm.Success.Printfln("%d checks rerun", len(rerun))
+				return
+			}
+		}
+
+		if len(failed) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+var checksRerun, checksLogs bool
+
+// pullRequestArg is the PR given on the command line, the one of the current branch otherwise
+func pullRequestArg(s sv.Sv, args []string) sv.PullRequest {
+	if len(args) > 0 {
+		pr, err := s.GetPullRequest(args[0])
+		if err != nil {
+			pterm.Fatal.Println(err)
+		}
+		return pr
+	}
+
+	branch, err := s.GetCurrentBranch()
+	if err != nil {
+		pterm.Fatal.Printfln("cannot read the current branch: %v", err)
+	}
+	prs, err := s.ListPullRequests(sv.PullRequestFilter{Head: branch})
+	if err != nil {
+		pterm.Fatal.Println(err)
+	}
+	var found sv.PullRequest
+	for pr := range prs {
+		// The channel is drained to let the producer end, the head filter being loose on some providers
+		if found == nil && pr.GetBranch().GetName() == branch {
+			found = pr
+		}
+	}
+	if found == nil {
+		pterm.Fatal.Printfln("No PR for the branch %s", branch)
+	}
+	return found
+}
+
+var checkStatusStyles = map[string]pterm.Color{
+	"SUCCESS":   pterm.FgGreen,
+	"FAILURE":   pterm.FgRed,
+	"CANCELLED": pterm.FgYellow,
+	"SKIPPED":   pterm.FgGray,
+	"NEUTRAL":   pterm.FgGray,
+}
+
+// renderCheckStatus is the conclusion of a completed check, the status otherwise
+func renderCheckStatus(c sv.CheckRun) string {
+	if conclusion := c.GetConclusion(); conclusion != "" {
+		return checkStatusStyles[conclusion].Sprint(conclusion)
+	}
+	return pterm.FgCyan.Sprint(c.GetStatus())
+}
+
+func checkDuration(c sv.CheckRun) string {
+	started, completed := c.GetStartedAt(), c.GetCompletedAt()
+	if started.IsZero() {
+		return ""
+	} else if completed.IsZero() {
+		completed = time.Now()
+	}
+	return completed.Sub(started).Round(time.Second).String()
+}
+
+func init() {
+	rootCmd.AddCommand(checksCmd)
+
+	checksCmd.Flags().BoolVar(&checksRerun, "rerun-failed", false, "Rerun the failed checks")
+	checksCmd.Flags().BoolVar(&checksLogs, "logs", false, "Print the logs of the failed checks")
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/vballestra/sv/config"
+	"strings"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the configuration file",
+	Long: `Manage the profiles of the configuration file (` + config.DefaultPath() + `).
+
+A profile is selected with --profile, otherwise by the workspace of the local copy, then by the host of its remote.
+The "default" profile is used when none matches. Command line flags override the profile values.`,
+	// No need for a local repository here
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		loadConfig()
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a value of the profile",
+	Long:  `Print a value of the profile, the keys are ` + strings.Join(config.Keys, ", "),
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if value, err := svConfig.Profile(configProfileName()).Get(args[0]); err != nil {
+			pterm.Fatal.Println(err)
+		} else {
+			pterm.Println(value)
+		}
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a value of the profile",
+	Long:  `Set a value of the profile, the keys are ` + strings.Join(config.Keys, ", ") + `. An empty value unsets the key.`,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := svConfig.Profile(configProfileName()).Set(args[0], args[1]); err != nil {
+			pterm.Fatal.Println(err)
+		} else if err := svConfig.Save(); err != nil {
+			pterm.Fatal.Printfln("cannot save %s: %v", svConfig.Path(), err)
+		}
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the profiles and their values",
+	Long:    `List the profiles and their values, all of them unless --profile is given`,
+	Aliases: []string{"ls"},
+	Run: func(cmd *cobra.Command, args []string) {
+		data := pterm.TableData{{"Profile", "Key", "Value"}}
+
+		for _, name := range svConfig.ProfileNames() {
+			if profileName != "" && name != profileName {
+				continue
+			}
+			p := svConfig.Profiles[name]
+			for _, key := range config.Keys {
+				value, _ := p.Get(key)
+				if value == "" {
+					continue
+				} else if config.SecretKeys[key] && !configShowSecrets {
+					value = "********"
+				}
+				data = append(data, []string{name, key, value})
+			}
+		}
+
+		if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+			pterm.Fatal.Println(err)
+		}
+	},
+}
+
+var configShowSecrets = false
+
+// configProfileName is the profile edited by the config commands, the default one unless --profile is given
+func configProfileName() string {
+	if profileName != "" {
+		return profileName
+	}
+	return config.DefaultProfile
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+
+	configListCmd.Flags().BoolVar(&configShowSecrets, "show-secrets", false, "Display tokens and passwords")
+}
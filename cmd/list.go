@@ -6,9 +6,14 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/antihax/optional"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+	"github.com/vballestra/sv/sv"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // listCmd represents the list command
@@ -21,8 +26,40 @@ var listCmd = &cobra.Command{
 
 		c := GetSv()
 
-		if prs, err := c.ListPullRequests(prsQuery); err != nil {
+		filter := sv.PullRequestFilter{
+			Query:    prsQuery,
+			Author:   prsAuthor,
+			Reviewer: prsReviewer,
+			Label:    prsLabel,
+			State:    prsState,
+			Base:     prsBase,
+			Head:     prsHead,
+			MaxPages: int(maxPages),
+		}
+		if cmd.Flags().Changed("draft") {
+			filter.Draft = optional.NewBool(prsDraft)
+		}
+		if prsUpdatedSince != "" {
+			if since, err := parseSince(prsUpdatedSince); err != nil {
+				pterm.Fatal.Println(err)
+			} else {
+				filter.UpdatedSince = since
+			}
+		}
+		if !isPullRequestState(prsState) {
+			pterm.Fatal.Printfln("unknown state '%s', expected one of %s", prsState, strings.Join(sv.PullRequestStates, ", "))
+		}
+
+		if prs, err := c.ListPullRequests(filter); err != nil {
 			log.Fatalf("Something has occurred : %s", err)
+		} else if isStructuredOutput() {
+			result := make([]PullRequestOutput, 0)
+			for pr := range prs {
+				result = append(result, toPullRequestOutput(pr))
+			}
+			if err := printOutput(result); err != nil {
+				pterm.Fatal.Println(err)
+			}
 		} else {
 
 			data := pterm.TableData{{"ID", "Title", "Branch", "Author", "State", "Created At"}}
@@ -45,6 +82,31 @@ var listCmd = &cobra.Command{
 
 var maxPages int32
 var prsQuery string
+var prsAuthor, prsReviewer, prsLabel, prsState, prsBase, prsHead, prsUpdatedSince string
+var prsDraft bool
+
+func isPullRequestState(state string) bool {
+	for _, s := range sv.PullRequestStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSince reads a date, a RFC 3339 time or a duration before now like 36h or 7d
+func parseSince(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	} else if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	} else if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && strings.HasSuffix(value, "d") {
+		return time.Now().AddDate(0, 0, -days), nil
+	} else if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time '%s', expected a date (2006-01-02), a RFC 3339 time or a duration (36h, 7d)", value)
+}
 
 func init() {
 	prCmd.AddCommand(listCmd)
@@ -58,6 +120,14 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// listCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
-	listCmd.Flags().Int32Var(&maxPages, "max-pages", 5, "Max pages to retrieve")
-	listCmd.Flags().StringVarP(&prsQuery, "query", "q", "", "Query")
+	listCmd.Flags().Int32Var(&maxPages, "max-pages", 5, "Max pages to retrieve, 0 for no limit")
+	listCmd.Flags().StringVarP(&prsQuery, "query", "q", "", "Query in the provider syntax (BBQL on Bitbucket, search syntax on GitHub)")
+	listCmd.Flags().StringVar(&prsAuthor, "author", "", "Only the PRs of this author, @me for yours")
+	listCmd.Flags().StringVar(&prsReviewer, "reviewer", "", "Only the PRs this reviewer is requested on, @me for yours")
+	listCmd.Flags().StringVar(&prsLabel, "label", "", "Only the PRs with this label")
+	listCmd.Flags().StringVar(&prsState, "state", "open", "State: open, closed, merged or all")
+	listCmd.Flags().StringVar(&prsBase, "base", "", "Only the PRs targeting this branch")
+	listCmd.Flags().StringVar(&prsHead, "head", "", "Only the PRs of this branch")
+	listCmd.Flags().BoolVar(&prsDraft, "draft", false, "Only the drafts, or only the ready PRs with --draft=false")
+	listCmd.Flags().StringVar(&prsUpdatedSince, "updated-since", "", "Only the PRs updated since this date (2006-01-02), time or duration (36h, 7d)")
 }
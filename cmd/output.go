@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
+	"github.com/pterm/pterm"
+	"github.com/vballestra/sv/sv"
+	"gopkg.in/yaml.v3"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// The output schema is the same for every provider, the ids are strings since their type depends on the provider
+
+type PullRequestOutput struct {
+	Id        string          `json:"id" yaml:"id"`
+	Title     string          `json:"title" yaml:"title"`
+	Author    string          `json:"author" yaml:"author"`
+	State     string          `json:"state" yaml:"state"`
+	Branch    string          `json:"branch" yaml:"branch"`
+	Base      string          `json:"base" yaml:"base"`
+	CreatedOn time.Time       `json:"createdOn" yaml:"createdOn"`
+	Reviews   []ReviewOutput  `json:"reviews,omitempty" yaml:"reviews,omitempty"`
+	Checks    []CheckOutput   `json:"checks,omitempty" yaml:"checks,omitempty"`
+	DiffStats *DiffStatOutput `json:"diffStats,omitempty" yaml:"diffStats,omitempty"`
+	Comments  []CommentOutput `json:"comments,omitempty" yaml:"comments,omitempty"`
+	Threads   []ThreadOutput  `json:"threads,omitempty" yaml:"threads,omitempty"`
+}
+
+type PullRequestStatusOutput struct {
+	Id         string         `json:"id" yaml:"id"`
+	Title      string         `json:"title" yaml:"title"`
+	Author     string         `json:"author" yaml:"author"`
+	Repository string         `json:"repository" yaml:"repository"`
+	Branch     string         `json:"branch" yaml:"branch"`
+	Base       string         `json:"base" yaml:"base"`
+	State      string         `json:"state" yaml:"state"`
+	Mine       bool           `json:"mine" yaml:"mine"`
+	Reviews    []ReviewOutput `json:"reviews" yaml:"reviews"`
+	Checks     map[string]int `json:"checks" yaml:"checks"`
+	Contexts   map[string]int `json:"contexts" yaml:"contexts"`
+}
+
+type ReviewOutput struct {
+	Author      string    `json:"author" yaml:"author"`
+	State       string    `json:"state" yaml:"state"`
+	SubmittedAt time.Time `json:"submittedAt" yaml:"submittedAt"`
+}
+
+type CheckOutput struct {
+	Name        string     `json:"name" yaml:"name"`
+	Status      string     `json:"status" yaml:"status"`
+	Conclusion  string     `json:"conclusion" yaml:"conclusion"`
+	Url         string     `json:"url" yaml:"url"`
+	StartedAt   *time.Time `json:"startedAt,omitempty" yaml:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty" yaml:"completedAt,omitempty"`
+}
+
+type DiffStatOutput struct {
+	Additions int              `json:"additions" yaml:"additions"`
+	Deletions int              `json:"deletions" yaml:"deletions"`
+	Files     []FileStatOutput `json:"files" yaml:"files"`
+}
+
+type FileStatOutput struct {
+	Path      string `json:"path" yaml:"path"`
+	OldPath   string `json:"oldPath,omitempty" yaml:"oldPath,omitempty"`
+	Status    string `json:"status" yaml:"status"`
+	Additions int    `json:"additions" yaml:"additions"`
+	Deletions int    `json:"deletions" yaml:"deletions"`
+	Binary    bool   `json:"binary,omitempty" yaml:"binary,omitempty"`
+}
+
+type CommentOutput struct {
+	Id        string         `json:"id" yaml:"id"`
+	ParentId  string         `json:"parentId,omitempty" yaml:"parentId,omitempty"`
+	Author    string         `json:"author" yaml:"author"`
+	Body      string         `json:"body" yaml:"body"`
+	CreatedOn time.Time      `json:"createdOn" yaml:"createdOn"`
+	Reactions map[string]int `json:"reactions,omitempty" yaml:"reactions,omitempty"`
+}
+
+// ThreadOutput is an inline comment and its replies. Side is "new" or "old", the side of the diff the line is on.
+type ThreadOutput struct {
+	Path     string          `json:"path" yaml:"path"`
+	Line     int64           `json:"line" yaml:"line"`
+	Side     string          `json:"side" yaml:"side"`
+	Comments []CommentOutput `json:"comments" yaml:"comments"`
+}
+
+var outputFormat string
+
+const goTemplatePrefix = "go-template="
+
+// isStructuredOutput tells whether the result should be printed by printOutput instead of the tables
+func isStructuredOutput() bool {
+	return outputFormat != "" && outputFormat != "table"
+}
+
+// setMessagesOutput sends the messages and the spinners to stderr when the result is structured, so that stdout only
+// holds the document
+func setMessagesOutput() {
+	if isStructuredOutput() {
+		pterm.SetDefaultOutput(os.Stderr)
+	} else {
+		pterm.SetDefaultOutput(os.Stdout)
+	}
+}
+
+func checkOutputFormat() error {
+	switch {
+	case outputFormat == "", outputFormat == "table", outputFormat == "json", outputFormat == "yaml":
+		return nil
+	case strings.HasPrefix(outputFormat, goTemplatePrefix):
+		_, err := template.New("output").Parse(strings.TrimPrefix(outputFormat, goTemplatePrefix))
+		return err
+	}
+	return fmt.Errorf("unknown output format '%s', expected table, json, yaml or go-template=...", outputFormat)
+}
+
+func printOutput(v interface{}) error {
+	switch {
+	case outputFormat == "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case outputFormat == "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		defer encoder.Close()
+		return encoder.Encode(v)
+	case strings.HasPrefix(outputFormat, goTemplatePrefix):
+		tmpl, err := template.New("output").Parse(strings.TrimPrefix(outputFormat, goTemplatePrefix))
+		if err != nil {
+			return err
+		}
+		// The template sees the json document, so that the names are the same in both
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		var doc interface{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&doc); err != nil {
+			return err
+		}
+		return tmpl.Execute(os.Stdout, doc)
+	}
+	return fmt.Errorf("unknown output format '%s'", outputFormat)
+}
+
+func toPullRequestOutput(pr sv.PullRequest) PullRequestOutput {
+	return PullRequestOutput{
+		Id:        fmt.Sprint(pr.GetId()),
+		Title:     pr.GetTitle(),
+		Author:    pr.GetAuthor().GetDisplayName(),
+		State:     pr.GetState(),
+		Branch:    pr.GetBranch().GetName(),
+		Base:      pr.GetBase().GetName(),
+		CreatedOn: pr.GetCreatedOn(),
+	}
+}
+
+func toPullRequestStatusOutput(pr sv.PullRequestStatus) PullRequestStatusOutput {
+	return PullRequestStatusOutput{
+		Id:         fmt.Sprint(pr.GetId()),
+		Title:      pr.GetTitle(),
+		Author:     pr.GetAuthor(),
+		Repository: pr.GetRepository(),
+		Branch:     pr.GetBranchName(),
+		Base:       pr.GetBaseName(),
+		State:      pr.GetStatus(),
+		Mine:       pr.IsMine(),
+		Reviews:    toReviewOutputs(pr.GetReviews()),
+		Checks:     nonZeroCounts(pr.GetChecksByStatus()),
+		Contexts:   nonZeroCounts(pr.GetContextByStatus()),
+	}
+}
+
+func nonZeroCounts(counts map[string]int) map[string]int {
+	result := make(map[string]int)
+	for k, v := range counts {
+		if v > 0 {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func toReviewOutputs(reviews []sv.Review) []ReviewOutput {
+	result := make([]ReviewOutput, 0, len(reviews))
+	for _, r := range reviews {
+		result = append(result, ReviewOutput{Author: r.GetAuthor(), State: r.GetState(), SubmittedAt: r.GetSubmitedAt()})
+	}
+	return result
+}
+
+func toCheckOutputs(checks []sv.CheckRun) []CheckOutput {
+	result := make([]CheckOutput, 0, len(checks))
+	for _, c := range checks {
+		out := CheckOutput{Name: c.GetName(), Status: c.GetStatus(), Conclusion: c.GetConclusion(), Url: c.GetUrl()}
+		if t := c.GetStartedAt(); !t.IsZero() {
+			out.StartedAt = &t
+		}
+		if t := c.GetCompletedAt(); !t.IsZero() {
+			out.CompletedAt = &t
+		}
+		result = append(result, out)
+	}
+	return result
+}
+
+func toDiffStatOutput(files []*gitdiff.File) *DiffStatOutput {
+	stats := &DiffStatOutput{Files: make([]FileStatOutput, 0, len(files))}
+	for _, f := range files {
+		fs := FileStatOutput{Path: f.NewName, Status: "modified", Binary: f.IsBinary}
+		switch {
+		case f.IsNew:
+			fs.Status = "added"
+		case f.IsDelete:
+			fs.Path, fs.Status = f.OldName, "deleted"
+		case f.IsRename:
+			fs.OldPath, fs.Status = f.OldName, "renamed"
+		case f.IsCopy:
+			fs.OldPath, fs.Status = f.OldName, "copied"
+		}
+		for _, frag := range f.TextFragments {
+			fs.Additions += int(frag.LinesAdded)
+			fs.Deletions += int(frag.LinesDeleted)
+		}
+		stats.Additions += fs.Additions
+		stats.Deletions += fs.Deletions
+		stats.Files = append(stats.Files, fs)
+	}
+	return stats
+}
+
+func toCommentOutput(c sv.Comment) CommentOutput {
+	out := CommentOutput{
+		Id:        fmt.Sprint(c.GetId()),
+		Author:    c.GetUser().GetDisplayName(),
+		Body:      c.GetContent().GetRaw(),
+		CreatedOn: c.GetCreatedOn(),
+	}
+	if parent := c.GetParentId(); parent != nil {
+		out.ParentId = fmt.Sprint(parent)
+	}
+	if reactions := c.GetReactions(); len(reactions) > 0 {
+		out.Reactions = make(map[string]int)
+		for name, r := range reactions {
+			out.Reactions[name] = len(r)
+		}
+	}
+	return out
+}
+
+func toCommentOutputs(comments []sv.Comment) []CommentOutput {
+	result := make([]CommentOutput, 0, len(comments))
+	for _, c := range comments {
+		result = append(result, toCommentOutput(c))
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].CreatedOn.Before(result[j].CreatedOn) })
+	return result
+}
+
+// toThreadOutputs splits the comments of each line in threads, a reply joins the thread of its parent
+func toThreadOutputs(byLine map[string]map[int64][]sv.Comment) []ThreadOutput {
+	result := make([]ThreadOutput, 0)
+
+	paths := make([]string, 0, len(byLine))
+	for path := range byLine {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		keys := make([]int64, 0, len(byLine[path]))
+		for key := range byLine[path] {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return abs64(keys[i]) < abs64(keys[j]) })
+
+		for _, key := range keys {
+			// Lines of the new side are negative
+			line, side := key, "old"
+			if key < 0 {
+				line, side = -key, "new"
+			}
+
+			threadOf := make(map[string]int)
+			for _, c := range toCommentOutputs(byLine[path][key]) {
+				if idx, ok := threadOf[c.ParentId]; ok && c.ParentId != "" {
+					result[idx].Comments = append(result[idx].Comments, c)
+					threadOf[c.Id] = idx
+				} else {
+					threadOf[c.Id] = len(result)
+					result = append(result, ThreadOutput{Path: path, Line: line, Side: side, Comments: []CommentOutput{c}})
+				}
+			}
+		}
+	}
+	return result
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "Output format: table, json, yaml or go-template=<template>")
+}
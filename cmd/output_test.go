@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/json"
+	"github.com/pterm/pterm"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureOutput runs f with stdout and stderr redirected, and returns what was written to each
+func captureOutput(t *testing.T, f func()) (string, string) {
+	stdout, stderr := os.Stdout, os.Stderr
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout, os.Stderr = outW, errW
+	defer func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		setMessagesOutput()
+	}()
+
+	outC, errC := make(chan string), make(chan string)
+	read := func(r io.Reader, c chan<- string) {
+		b, _ := io.ReadAll(r)
+		c <- string(b)
+	}
+	go read(outR, outC)
+	go read(errR, errC)
+
+	f()
+	outW.Close()
+	errW.Close()
+	return <-outC, <-errC
+}
+
+func TestStructuredOutputKeepsTheMessagesOnStderr(t *testing.T) {
+	outputFormat = "json"
+	defer func() { outputFormat = "" }()
+
+	out, messages := captureOutput(t, func() {
+		setMessagesOutput()
+		pterm.Warning.Printfln("Remote '%s' mismatches with origin url : %s", "origin", "git@example.com:me/repo.git")
+		spinner, _ := pterm.DefaultSpinner.WithRemoveWhenDone(true).Start()
+		spinner.Success("Finished")
+		if err := printOutput(PullRequestOutput{Id: "1", Title: "A title", State: "OPEN"}); err != nil {
+			t.Error(err)
+		}
+	})
+
+	var pr PullRequestOutput
+	if err := json.Unmarshal([]byte(out), &pr); err != nil {
+		t.Fatalf("stdout isn't the json document: %v\n%s", err, out)
+	}
+	if pr.Id != "1" || pr.Title != "A title" || pr.State != "OPEN" {
+		t.Errorf("pr = %+v", pr)
+	}
+	if !strings.Contains(messages, "mismatches") || !strings.Contains(messages, "Finished") {
+		t.Errorf("the messages are missing from stderr: %q", messages)
+	}
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/vballestra/sv/bitbucket"
+	"github.com/vballestra/sv/sv"
+	"os"
+	"time"
+)
+
+// pipelinesCmd represents the pipelines command
+var pipelinesCmd = &cobra.Command{
+	Use:     "pipelines",
+	Short:   "Bitbucket pipelines",
+	Long:    `List, run, stop and follow the Bitbucket pipelines of the repository. Pipelines are given by build number or uuid.`,
+	Aliases: []string{"pl"},
+}
+
+var pipelinesListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the last pipelines",
+	Long:    `List the last pipelines, the most recent first`,
+	Aliases: []string{"ls"},
+	Run: func(cmd *cobra.Command, args []string) {
+		b := getBitbucketSv()
+
+		pipelines, err := b.ListPipelines(pipelinesLimit)
+		if err != nil {
+			pterm.Fatal.Printfln("cannot list pipelines: %v", err)
+		}
+
+		data := pterm.TableData{{"#", "State", "Ref", "Selector", "Creator", "Created On", "Duration"}}
+		for _, p := range pipelines {
+			ref, selector := pipelineTarget(p)
+			creator := ""
+			if p.Creator != nil {
+				creator = p.Creator.DisplayName
+			}
+			data = append(data, []string{
+				fmt.Sprintf("%5d", p.BuildNumber), renderPipelineStatus(sv.BitbucketPipelineStatus(p.State)), ref, selector, creator,
+				p.CreatedOn.Format(time.RFC822), (time.Duration(p.BuildSecondsUsed) * time.Second).String(),
+			})
+		}
+
+		if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+			pterm.Fatal.Println(err)
+		}
+	},
+}
+
+var pipelinesRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run a pipeline",
+	Long:  `Run the pipeline of the current branch, or of another branch with --branch. A custom pipeline is run with --custom.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		b := getBitbucketSv()
+
+		branch := pipelinesBranch
+		if branch == "" {
+			if current, err := b.GetCurrentBranch(); err != nil {
+				pterm.Fatal.Printfln("cannot read the current branch: %v", err)
+			} else {
+				branch = current
+			}
+		}
+
+		pipeline, err := b.RunPipeline(branch, pipelinesCustom)
+		if err != nil {
+			pterm.Fatal.Printfln("cannot run the pipeline: %v", err)
+		}
+		pterm.Info.Printfln("Pipeline #%d started on %s", pipeline.BuildNumber, branch)
+
+		if pipelinesWatch {
+			watchPipeline(b, fmt.Sprintf("%d", pipeline.BuildNumber))
+		}
+	},
+}
+
+var pipelinesStopCmd = &cobra.Command{
+	Use:   "stop <pipeline>",
+	Short: "Stop a pipeline",
+	Long:  `Stop a running pipeline`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := getBitbucketSv().StopPipeline(args[0]); err != nil {
+			pterm.Fatal.Printfln("cannot stop pipeline %s: %v", args[0], err)
+		}
+		pterm.Success.Printfln("Pipeline %s stopped", args[0])
+	},
+}
+
+var pipelinesLogsCmd = &cobra.Command{
+	Use:   "logs [pipeline]",
+	Short: "Print the logs of a pipeline",
+	Long:  `Print the logs of the steps of a pipeline, the last one by default. A single step is printed with --step.`,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		b := getBitbucketSv()
+		id := pipelineArg(b, args)
+
+		steps, err := b.GetPipelineSteps(id)
+		if err != nil {
+			pterm.Fatal.Println(err)
+		}
+		for _, step := range steps {
+			if pipelinesStep != "" && step.Name != pipelinesStep {
+				continue
+			}
+			pterm.DefaultSection.Printfln("%s (%s)", step.Name, sv.BitbucketPipelineStepStatus(step.State))
+			if log, err := b.GetPipelineStepLog(id, step.Uuid, 0); err != nil {
+				pterm.Error.Printfln("cannot read the log of %s: %v", step.Name, err)
+			} else {
+				_, _ = os.Stdout.Write(log)
+			}
+		}
+	},
+}
+
+var pipelinesWatchCmd = &cobra.Command{
+	Use:   "watch [pipeline]",
+	Short: "Follow a pipeline until its completion",
+	Long: `Follow a pipeline, the last one by default: print the step states and tail their logs until the pipeline is completed.
+The exit code is 1 when the pipeline isn't successful.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		b := getBitbucketSv()
+		watchPipeline(b, pipelineArg(b, args))
+	},
+}
+
+var pipelinesLimit int32
+var pipelinesBranch, pipelinesCustom, pipelinesStep string
+var pipelinesWatch bool
+var pipelinesInterval time.Duration
+
+func getBitbucketSv() *sv.BitBucketSv {
+	if b, ok := sv.Unwrap(GetSv()).(*sv.BitBucketSv); ok {
+		return b
+	}
+	pterm.Fatal.Println("Pipelines are only available on Bitbucket")
+	return nil
+}
+
+// pipelineArg is the pipeline given on the command line, the last one otherwise
+func pipelineArg(b *sv.BitBucketSv, args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	if pipelines, err := b.ListPipelines(1); err != nil {
+		pterm.Fatal.Printfln("cannot list pipelines: %v", err)
+	} else if len(pipelines) == 0 {
+		pterm.Fatal.Println("No pipelines yet")
+	} else {
+		return fmt.Sprintf("%d", pipelines[0].BuildNumber)
+	}
+	return ""
+}
+
+func pipelineTarget(p bitbucket.Pipeline) (string, string) {
+	if p.Target == nil {
+		return "", ""
+	}
+	ref := p.Target.RefName
+	if ref == "" && p.Target.Commit != nil {
+		ref = p.Target.Commit.Hash
+	}
+	selector := ""
+	if p.Target.Selector != nil {
+		selector = p.Target.Selector.Type_
+		if p.Target.Selector.Pattern != "" {
+			selector = fmt.Sprintf("%s: %s", selector, p.Target.Selector.Pattern)
+		}
+	}
+	return ref, selector
+}
+
+var pipelineStatusStyles = map[string]pterm.Color{
+	"SUCCESSFUL":  pterm.FgGreen,
+	"FAILED":      pterm.FgRed,
+	"ERROR":       pterm.FgRed,
+	"STOPPED":     pterm.FgYellow,
+	"EXPIRED":     pterm.FgYellow,
+	"RUNNING":     pterm.FgCyan,
+	"IN_PROGRESS": pterm.FgCyan,
+	"PAUSED":      pterm.FgYellow,
+	"PENDING":     pterm.FgGray,
+	"READY":       pterm.FgGray,
+	"NOT_RUN":     pterm.FgGray,
+}
+
+func renderPipelineStatus(status string) string {
+	if color, ok := pipelineStatusStyles[status]; ok {
+		return color.Sprint(status)
+	}
+	return status
+}
+
+// watchPipeline polls the pipeline and its steps, printing the state changes and the new log lines
+func watchPipeline(b *sv.BitBucketSv, id string) {
+	states := make(map[string]string)
+	offsets := make(map[string]int)
+
+	for {
+		pipeline, err := b.GetPipeline(id)
+		if err != nil {
+			pterm.Fatal.Println(err)
+		}
+		steps, err := b.GetPipelineSteps(id)
+		if err != nil {
+			pterm.Fatal.Println(err)
+		}
+
+		for _, step := range steps {
+			status := sv.BitbucketPipelineStepStatus(step.State)
+			if states[step.Uuid] != status {
+				states[step.Uuid] = status
+				pterm.Info.Printfln("%s: %s", step.Name, renderPipelineStatus(status))
+			}
+
+			if step.State == nil || step.State.Name == "PENDING" || step.State.Name == "READY" {
+				continue
+			}
+			if log, err := b.GetPipelineStepLog(id, step.Uuid, offsets[step.Uuid]); err != nil {
+				pterm.Debug.Printfln("cannot read the log of %s: %v", step.Name, err)
+			} else if len(log) > 0 {
+				offsets[step.Uuid] += len(log)
+				_, _ = os.Stdout.Write(log)
+			}
+		}
+
+		if pipeline.State != nil && pipeline.State.Name == "COMPLETED" {
+			status := sv.BitbucketPipelineStatus(pipeline.State)
+			if status == "SUCCESSFUL" {
+				pterm.Success.Printfln("Pipeline #%d %s", pipeline.BuildNumber, status)
+				return
+			}
+			pterm.Error.Printfln("Pipeline #%d %s", pipeline.BuildNumber, status)
+			os.Exit(1)
+		}
+
+		time.Sleep(pipelinesInterval)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(pipelinesCmd)
+	pipelinesCmd.AddCommand(pipelinesListCmd)
+	pipelinesCmd.AddCommand(pipelinesRunCmd)
+	pipelinesCmd.AddCommand(pipelinesStopCmd)
+	pipelinesCmd.AddCommand(pipelinesLogsCmd)
+	pipelinesCmd.AddCommand(pipelinesWatchCmd)
+
+	pipelinesListCmd.Flags().Int32VarP(&pipelinesLimit, "limit", "L", 20, "Number of pipelines to list")
+	pipelinesRunCmd.Flags().StringVarP(&pipelinesBranch, "branch", "B", "", "Branch to build (default value is the current branch)")
+	pipelinesRunCmd.Flags().StringVar(&pipelinesCustom, "custom", "", "Name of the custom pipeline to run")
+	pipelinesRunCmd.Flags().BoolVar(&pipelinesWatch, "watch", false, "Follow the pipeline once started")
+	pipelinesLogsCmd.Flags().StringVar(&pipelinesStep, "step", "", "Only print the log of this step")
+	pipelinesCmd.PersistentFlags().DurationVar(&pipelinesInterval, "interval", 5*time.Second, "Polling interval of the watchers")
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/vballestra/sv/sv"
+)
+
+// prCheckoutCmd represents the prCheckout command
+var prCheckoutCmd = &cobra.Command{
+	Use:     "checkout <id>",
+	Short:   "Checks out the branch of a PR",
+	Aliases: []string{"co"},
+	Long: `Fetches the head of a PR, forks included, then creates or fast-forwards the local branch and switches to it.
+The branch of a PR coming from a fork is named pr-<id>-<branch>. The checkout is refused when the worktree has local
+changes or when the local branch has commits that aren't in the PR, unless --force is given.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if branch, err := GetSv().CheckoutPullRequest(defaultOrigin, args[0], forceCheckout); errors.Is(err, sv.ErrDirtyWorktree) {
+			pterm.Fatal.Printfln("%v, commit or stash them first, or use --force to discard them", err)
+		} else if err != nil {
+			pterm.Fatal.Printfln("Cannot checkout PR %s: %v", args[0], err)
+		} else {
+			pterm.Success.Printfln("Switched to branch %s", branch)
+		}
+	},
+}
+
+var forceCheckout bool
+
+func init() {
+	prCmd.AddCommand(prCheckoutCmd)
+
+	prCheckoutCmd.Flags().BoolVar(&forceCheckout, "force", false, "Discard the local changes and reset the local branch")
+}
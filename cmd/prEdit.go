@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/vballestra/sv/cmd/ui"
+	"github.com/vballestra/sv/sv"
+	"os"
+	"strings"
+)
+
+// prEditCmd represents the prEdit command
+var prEditCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Edits a PR",
+	Long: `Changes the title, the description, the base branch, the labels, the reviewers and the assignees of a PR.
+Without any change given, or with --edit, the title and the description are written in the editor: the title on the
+first line, then the description. The editor is the one of --editor, $EDITOR otherwise.
+Bitbucket has neither labels nor assignees.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pr, err := GetSv().GetPullRequest(args[0])
+		if err != nil {
+			pterm.Fatal.Println(err)
+		}
+
+		a := sv.EditPullRequestArgs{
+			Title:               optionalString(editPrTitle),
+			Description:         optionalString(editPrDescription),
+			BaseBranch:          optionalString(editPrBaseBranch),
+			AddLabels:           editPrAddLabels,
+			RemoveLabels:        editPrRemoveLabels,
+			AddReviewers:        editPrAddReviewers,
+			RemoveReviewers:     editPrRemoveReviewers,
+			AddAssignees:        editPrAddAssignees,
+			RemoveAssignees:     editPrRemoveAssignees,
+			CreateMissingLabels: true,
+		}
+
+		if editPrInEditor || !hasChanges(a) {
+			if ui.Editor == "" {
+				ui.Editor = os.Getenv("EDITOR")
+			}
+			title, description := pr.GetTitle(), pr.GetDescription()
+			if a.Title.IsSet() {
+				title = a.Title.Value()
+			}
+			if a.Description.IsSet() {
+				description = a.Description.Value()
+			}
+			if text, err := ui.EditText("Title and description", title+"\n\n"+description); err != nil {
+				pterm.Fatal.Println(err)
+			} else if title, description := splitTitle(text); title == "" {
+				pterm.Fatal.Println("The title can't be empty")
+			} else {
+				a.Title, a.Description = optionalString(title), optionalString(description)
+			}
+		}
+
+		if err := pr.Edit(a); err != nil {
+			pterm.Fatal.Printfln("Cannot edit PR %s: %v", args[0], err)
+		} else {
+			pterm.Success.Printfln("PR %s updated", args[0])
+		}
+	},
+}
+
+var editPrTitle, editPrDescription, editPrBaseBranch string
+var editPrAddLabels, editPrRemoveLabels, editPrAddReviewers, editPrRemoveReviewers, editPrAddAssignees, editPrRemoveAssignees []string
+var editPrInEditor bool
+
+func hasChanges(a sv.EditPullRequestArgs) bool {
+	return a.Title.IsSet() || a.Description.IsSet() || a.BaseBranch.IsSet() ||
+		len(a.AddLabels)+len(a.RemoveLabels)+len(a.AddReviewers)+len(a.RemoveReviewers)+len(a.AddAssignees)+len(a.RemoveAssignees) > 0
+}
+
+// splitTitle splits an edited text on its first line
+func splitTitle(text string) (string, string) {
+	title, description, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(title), strings.TrimSpace(description)
+}
+
+func init() {
+	prCmd.AddCommand(prEditCmd)
+
+	prEditCmd.Flags().StringVarP(&editPrTitle, "title", "T", "", "The new title")
+	prEditCmd.Flags().StringVarP(&editPrDescription, "description", "d", "", "The new description")
+	prEditCmd.Flags().StringVarP(&editPrBaseBranch, "base", "b", "", "The new base branch")
+	prEditCmd.Flags().StringSliceVar(&editPrAddLabels, "add-label", []string{}, "Labels to add, created when missing")
+	prEditCmd.Flags().StringSliceVar(&editPrRemoveLabels, "remove-label", []string{}, "Labels to remove")
+	prEditCmd.Flags().StringSliceVar(&editPrAddReviewers, "add-reviewer", []string{}, "Reviewers to request, on Bitbucket by username, nickname, account id or {uuid}")
+	prEditCmd.Flags().StringSliceVar(&editPrRemoveReviewers, "remove-reviewer", []string{}, "Reviewers to remove, on Bitbucket by username, nickname, account id or {uuid}")
+	prEditCmd.Flags().StringSliceVar(&editPrAddAssignees, "add-assignee", []string{}, "Assignees to add")
+	prEditCmd.Flags().StringSliceVar(&editPrRemoveAssignees, "remove-assignee", []string{}, "Assignees to remove")
+	prEditCmd.Flags().BoolVarP(&editPrInEditor, "edit", "e", false, "Write the title and the description in the editor")
+}
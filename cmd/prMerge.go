@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/vballestra/sv/cmd/ui"
+	"github.com/vballestra/sv/sv"
+)
+
+// prMergeCmd represents the prMerge command
+var prMergeCmd = &cobra.Command{
+	Use:   "merge <id>",
+	Short: "Merges a PR",
+	Long: `Merges a PR with the given method, the default one of the provider otherwise. The commit message can be given
+with --title and --body, or written in the editor with --edit. With --auto the PR is merged once its checks pass.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts, err := mergeOptions(cmd)
+		if err != nil {
+			pterm.Fatal.Println(err)
+		}
+
+		pr, err := GetSv().GetPullRequest(args[0])
+		if err != nil {
+			pterm.Fatal.Println(err)
+		}
+
+		if mergeEdit {
+			initial := opts.Title
+			if initial == "" {
+				initial = pr.GetTitle()
+			}
+			if opts.Body != "" {
+				initial += "\n\n" + opts.Body
+			}
+			if message, err := ui.EditText("Commit message", initial); err != nil {
+				pterm.Fatal.Println(err)
+			} else {
+				opts.SetMessage(message)
+			}
+		}
+
+		if err := pr.Merge(opts); errors.Is(err, sv.ErrNotSupported) {
+			pterm.Fatal.Printfln("Cannot merge PR %s that way: %v", args[0], err)
+		} else if err != nil {
+			pterm.Fatal.Printfln("Cannot merge PR %s: %v", args[0], err)
+		} else if opts.Auto {
+			pterm.Success.Printfln("PR %s will be merged once its checks pass", args[0])
+		} else {
+			pterm.Success.Printfln("PR %s merged", args[0])
+		}
+	},
+}
+
+var mergeTitle, mergeBody string
+var mergeEdit, mergeDeleteBranch, mergeAuto bool
+
+// mergeOptions reads the flags, the method flags exclude each other
+func mergeOptions(cmd *cobra.Command) (sv.MergeOptions, error) {
+	opts := sv.MergeOptions{Title: mergeTitle, Body: mergeBody, DeleteBranch: mergeDeleteBranch, Auto: mergeAuto}
+	for _, method := range sv.MergeMethods {
+		if set, _ := cmd.Flags().GetBool(method); !set {
+			continue
+		} else if opts.Method != "" {
+			return opts, fmt.Errorf("--%s and --%s can't be used together", opts.Method, method)
+		}
+		opts.Method = method
+	}
+	return opts, nil
+}
+
+func init() {
+	prCmd.AddCommand(prMergeCmd)
+
+	prMergeCmd.Flags().Bool("merge", false, "Merge with a merge commit")
+	prMergeCmd.Flags().Bool("squash", false, "Squash the commits into one")
+	prMergeCmd.Flags().Bool("rebase", false, "Rebase the commits onto the base (fast-forward on Bitbucket)")
+	prMergeCmd.Flags().StringVarP(&mergeTitle, "title", "T", "", "Title of the commit")
+	prMergeCmd.Flags().StringVarP(&mergeBody, "body", "b", "", "Body of the commit")
+	prMergeCmd.Flags().BoolVarP(&mergeEdit, "edit", "e", false, "Write the commit message in the editor")
+	prMergeCmd.Flags().BoolVarP(&mergeDeleteBranch, "delete-branch", "d", false, "Delete the branch once merged")
+	prMergeCmd.Flags().BoolVar(&mergeAuto, "auto", false, "Merge once the checks pass, not available on Bitbucket")
+}
@@ -4,10 +4,16 @@ Copyright © 2022 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
 	"github.com/antihax/optional"
+	"github.com/erikgeiser/promptkit"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+	"github.com/vballestra/sv/cmd/ui"
 	"github.com/vballestra/sv/sv"
+	"sort"
+	"strings"
 )
 
 func optionalString(s string) optional.String {
@@ -22,10 +28,20 @@ func optionalString(s string) optional.String {
 var prNewCmd = &cobra.Command{
 	Use:   "new",
 	Short: "Create a new PR",
-	Long:  `Create a new PR`,
+	Long: `Create a new PR. The title defaults to the subject of the first commit of the head, the description to the
+pull request template of the repository (.github/pull_request_template.md, .bitbucket/, .gitlab/merge_request_templates/
+or .gitea/ equivalents), to the subjects of the other commits otherwise.
+The head branch is pushed to the remote of --remote when it lacks some of its commits, and the remote branch
+becomes its upstream, unless --no-push is given. With --interactive it's pushed once the preview is confirmed.
+With --interactive the base branch, the title and the description, the reviewers, the labels and the draft status
+are asked for, the flags giving the initial values, and the PR is previewed before being created.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		sv2 := GetSv()
 
+		if newPrBaseBranch == "" {
+			newPrBaseBranch = defaultBaseBranch
+		}
+
 		a := sv.CreatePullRequestArgs{
 			BaseBranch:          optionalString(newPrBaseBranch),
 			HeadBranch:          optionalString(newPrDestBranch),
@@ -34,6 +50,36 @@ var prNewCmd = &cobra.Command{
 			Labels:              newPrLabels,
 			Reviewers:           newPrReviwers,
 			CreateMissingLabels: true,
+			DefaultReviewers:    newPrDefaultReviewers,
+			Draft:               newPrDraft,
+			Remote:              defaultOrigin,
+		}
+
+		if newPrInteractive {
+			var err error
+			if a, err = ui.PromptNewPullRequest(sv2, remoteBranches(), a); errors.Is(err, promptkit.ErrAborted) {
+				pterm.Info.Println("No PR created")
+				return
+			} else if err != nil {
+				pterm.Fatal.Println(err)
+			}
+		}
+
+		// The head is only pushed once the PR is confirmed
+		if !newPrNoPush {
+			head := a.HeadBranch.Default("")
+			if head == "" {
+				if current, err := sv2.GetCurrentBranch(); err != nil {
+					pterm.Fatal.Println(err)
+				} else {
+					head = current
+				}
+			}
+			if pushed, err := sv2.PushBranch(defaultOrigin, head, false); err != nil {
+				pterm.Fatal.Printfln("couldn't push %s : %v", head, err)
+			} else if pushed {
+				pterm.Info.Printfln("Pushed %s", head)
+			}
 		}
 
 		if pr, err := sv2.CreatePullRequest(a); err != nil {
@@ -55,6 +101,32 @@ var newPrReviwers = make([]string, 0)
 
 var newPrLabels = make([]string, 0)
 
+var newPrDefaultReviewers = false
+
+var newPrDraft = false
+
+var newPrInteractive = false
+
+var newPrNoPush = false
+
+// remoteBranches lists the branches of the origin known to the local repository
+func remoteBranches() []string {
+	branches := make([]string, 0)
+	prefix := "refs/remotes/" + defaultOrigin + "/"
+	if refs, err := localRepository.References(); err != nil {
+		pterm.Debug.Printfln("cannot list the branches: %v", err)
+	} else {
+		_ = refs.ForEach(func(ref *plumbing.Reference) error {
+			if name := ref.Name().String(); ref.Type() == plumbing.HashReference && strings.HasPrefix(name, prefix) {
+				branches = append(branches, strings.TrimPrefix(name, prefix))
+			}
+			return nil
+		})
+	}
+	sort.Strings(branches)
+	return branches
+}
+
 func init() {
 	prCmd.AddCommand(prNewCmd)
 
@@ -70,6 +142,10 @@ func init() {
 	prNewCmd.Flags().StringVarP(&newPrDescription, "description", "d", "", "New PR optional description")
 	prNewCmd.Flags().StringVarP(&newPrBaseBranch, "base", "b", "", "The base branch (required)")
 	prNewCmd.Flags().StringVarP(&newPrDestBranch, "head", "H", "", "The (optional) head branch")
-	prNewCmd.Flags().StringSliceVarP(&newPrReviwers, "reviewer", "R", []string{}, "Optional list of reviewers requested")
+	prNewCmd.Flags().StringSliceVarP(&newPrReviwers, "reviewer", "R", []string{}, "Optional list of reviewers requested, on Bitbucket by username, nickname, account id or {uuid}")
 	prNewCmd.Flags().StringSliceVarP(&newPrLabels, "label", "l", []string{}, "Optional list of labels")
+	prNewCmd.Flags().BoolVar(&newPrDefaultReviewers, "default-reviewers", false, "Also add the repository default reviewers (Bitbucket only)")
+	prNewCmd.Flags().BoolVar(&newPrDraft, "draft", false, "Open the PR as a draft")
+	prNewCmd.Flags().BoolVar(&newPrNoPush, "no-push", false, "Don't push the head branch")
+	prNewCmd.Flags().BoolVarP(&newPrInteractive, "interactive", "i", false, "Ask for the PR details and preview it before creating it")
 }
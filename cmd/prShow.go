@@ -5,9 +5,11 @@ Copyright © 2022 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"github.com/vballestra/sv/cmd/ui"
+	"github.com/vballestra/sv/sv"
 	"log"
 )
 
@@ -15,7 +17,8 @@ import (
 var prShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Shows one PR details",
-	Long:  `Shows one PR with all details`,
+	Long: `Shows one PR with all details. With --output, the PR is printed with its reviews, checks, diff stats and
+comment threads instead of being opened in the viewer.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
 			log.Fatalln("No ID supplied")
@@ -24,11 +27,33 @@ var prShowCmd = &cobra.Command{
 		sv := GetSv()
 
 		if forcePrCheck {
-			if err := sv.Fetch(); err != nil {
+			if err := sv.Fetch(defaultOrigin); err != nil {
 				pterm.Warning.Println("An issue occurred while fetching the repository: ", err)
 			}
 		}
 
+		if isStructuredOutput() {
+			result := make([]PullRequestOutput, 0, len(args))
+			for _, id := range args {
+				if pr, err := sv.GetPullRequest(id); err != nil {
+					pterm.Fatal.Println(err)
+				} else {
+					result = append(result, pullRequestDetails(sv, pr))
+				}
+			}
+			// A single PR is printed as an object
+			var err error
+			if len(result) == 1 {
+				err = printOutput(result[0])
+			} else {
+				err = printOutput(result)
+			}
+			if err != nil {
+				pterm.Fatal.Println(err)
+			}
+			return
+		}
+
 		for _, id := range args {
 			if pr, err := sv.GetPullRequest(id); err != nil {
 				pterm.Error.Println(err)
@@ -41,6 +66,46 @@ var prShowCmd = &cobra.Command{
 
 var forcePrCheck bool
 
+// pullRequestDetails gathers everything the PR viewer shows, fetching the repository when the commits of the PR
+// aren't known yet
+func pullRequestDetails(repo sv.Sv, pr sv.PullRequest) PullRequestOutput {
+	out := toPullRequestOutput(pr)
+
+	if reviews, err := pr.GetReviews(); err != nil {
+		pterm.Fatal.Printfln("cannot read the reviews of %v: %v", pr.GetId(), err)
+	} else {
+		out.Reviews = toReviewOutputs(reviews)
+	}
+
+	if checks, err := pr.GetCheckRuns(); err != nil {
+		pterm.Fatal.Printfln("cannot read the checks of %v: %v", pr.GetId(), err)
+	} else {
+		out.Checks = toCheckOutputs(checks)
+	}
+
+	files, err := pr.GetDiff()
+	var missing *sv.MissingCommitError
+	if errors.As(err, &missing) {
+		if err = sv.ForceFetch(repo, defaultOrigin); err == nil {
+			files, err = pr.GetDiff()
+		}
+	}
+	if err != nil {
+		pterm.Fatal.Printfln("cannot read the diff of %v: %v", pr.GetId(), err)
+	} else {
+		out.DiffStats = toDiffStatOutput(files)
+	}
+
+	if comments, byLine, err := pr.GetCommentsByLine(); err != nil {
+		pterm.Fatal.Printfln("cannot read the comments of %v: %v", pr.GetId(), err)
+	} else {
+		out.Comments = toCommentOutputs(comments)
+		out.Threads = toThreadOutputs(byLine)
+	}
+
+	return out
+}
+
 func init() {
 	prCmd.AddCommand(prShowCmd)
 
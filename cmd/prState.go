@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/vballestra/sv/sv"
+)
+
+// prCloseCmd represents the prClose command
+var prCloseCmd = &cobra.Command{
+	Use:     "close <id>",
+	Short:   "Closes a PR without merging it",
+	Long:    `Closes a PR without merging it, Bitbucket declines it.`,
+	Aliases: []string{"decline"},
+	Args:    cobra.ExactArgs(1),
+	Run: changePullRequest("closed", func(pr sv.PullRequest) error {
+		return pr.Close()
+	}),
+}
+
+// prReopenCmd represents the prReopen command
+var prReopenCmd = &cobra.Command{
+	Use:   "reopen <id>",
+	Short: "Reopens a closed PR",
+	Long:  `Reopens a PR closed without being merged. A declined PR can't be reopened on Bitbucket.`,
+	Args:  cobra.ExactArgs(1),
+	Run: changePullRequest("reopened", func(pr sv.PullRequest) error {
+		return pr.Reopen()
+	}),
+}
+
+// prReadyCmd represents the prReady command
+var prReadyCmd = &cobra.Command{
+	Use:   "ready <id>",
+	Short: "Marks a draft PR ready for review",
+	Long: `Marks a draft PR ready for review. GitLab and Gitea tell the drafts by their title, its Draft: or WIP: prefix
+is removed.`,
+	Args: cobra.ExactArgs(1),
+	Run: changePullRequest("ready for review", func(pr sv.PullRequest) error {
+		return pr.SetDraft(false)
+	}),
+}
+
+// prDraftCmd represents the prDraft command
+var prDraftCmd = &cobra.Command{
+	Use:   "draft <id>",
+	Short: "Converts a PR to a draft",
+	Long:  `Converts a PR to a draft. GitLab and Gitea tell the drafts by their title, it gets a Draft: or WIP: prefix.`,
+	Args:  cobra.ExactArgs(1),
+	Run: changePullRequest("converted to a draft", func(pr sv.PullRequest) error {
+		return pr.SetDraft(true)
+	}),
+}
+
+// changePullRequest is the run of the lifecycle commands, applying the change to the PR given on the command line
+func changePullRequest(done string, change func(pr sv.PullRequest) error) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		if pr, err := GetSv().GetPullRequest(args[0]); err != nil {
+			pterm.Fatal.Println(err)
+		} else if err := change(pr); err != nil {
+			pterm.Fatal.Printfln("Cannot change PR %s: %v", args[0], err)
+		} else {
+			pterm.Success.Printfln("PR %s %s", args[0], done)
+		}
+	}
+}
+
+func init() {
+	prCmd.AddCommand(prCloseCmd)
+	prCmd.AddCommand(prReopenCmd)
+	prCmd.AddCommand(prReadyCmd)
+	prCmd.AddCommand(prDraftCmd)
+}
@@ -26,7 +26,18 @@ var prStatusCmd = &cobra.Command{
 			return
 		} else {
 			if interactive {
-				if err := statusView.RunPrStatusView(sv); err != nil {
+				if err := statusView.RunPrStatusView(sv, defaultOrigin); err != nil {
+					pterm.Fatal.Println(err)
+				}
+				return
+			}
+
+			if isStructuredOutput() {
+				result := make([]PullRequestStatusOutput, 0)
+				for pr := range c {
+					result = append(result, toPullRequestStatusOutput(pr))
+				}
+				if err := printOutput(result); err != nil {
 					pterm.Fatal.Println(err)
 				}
 				return
@@ -9,6 +9,8 @@ import (
 	"github.com/go-git/go-git/v5"
 	"github.com/pterm/pterm"
 	"github.com/vballestra/sv/bitbucket"
+	"github.com/vballestra/sv/cmd/ui"
+	"github.com/vballestra/sv/config"
 	"github.com/vballestra/sv/sv"
 	"net/http"
 	"os"
@@ -31,31 +33,150 @@ Since I'm a developer I thought it would have been funny to create one. Here it
 
 var defaultOrigin = "origin"
 
+// remoteRegexp matches the SSH and HTTPS urls of a remote hosted on hostPattern. Groups can only be nested
+// for providers supporting it, the org is a single path segment otherwise.
+func remoteRegexp(hostPattern string, nestedGroups bool) *regexp.Regexp {
+	org := "[^/]+"
+	if nestedGroups {
+		org = ".+"
+	}
+	return regexp.MustCompile(fmt.Sprintf("^(?:git@|ssh://git@|https://(?:[^@/]+@)?)(?P<host>%s)(?::\\d+)?[:/](?P<org>%s)/(?P<repo>[^/]+?)(\\.git)?/?$", hostPattern, org))
+}
+
 var origins = map[OriginType]*regexp.Regexp{
-	GitHubOriginType:    regexp.MustCompile("^git@github.com:(?P<org>[^/]+)/(?P<repo>.+)(\\.git)$?"),
-	BitbucketOriginType: regexp.MustCompile("^git@bitbucket.org:(?P<org>[^/]+)/(?P<repo>.+)(\\.git)$"),
+	// GitHub Enterprise Server hosts usually have "github" in their name
+	GitHubOriginType:    remoteRegexp("[^/:@]*github[^/:]*", false),
+	BitbucketOriginType: remoteRegexp("bitbucket\\.org", false),
+	// GitLab projects can live in nested groups, and self-hosted instances usually have "gitlab" in their host name
+	GitLabOriginType: remoteRegexp("[^/:@]*gitlab[^/:]*", true),
+	// Same for Gitea and its Forgejo fork, codeberg.org being the main public instance
+	GiteaOriginType: remoteRegexp("[^/:@]*(?:gitea|forgejo|codeberg\\.org)[^/:]*", false),
+}
+
+// originTypes is the order in which the remote url is matched, the patterns of the host names may overlap
+var originTypes = []OriginType{GitHubOriginType, BitbucketOriginType, GitLabOriginType, GiteaOriginType}
+
+// hosts are the host flags of the self-hostable providers, they're deduced from the remote when not set
+var hosts = map[OriginType]*string{
+	GitHubOriginType: &githubHost,
+	GitLabOriginType: &gitlabHost,
+	GiteaOriginType:  &giteaHost,
 }
 
 var localRepository *git.Repository
 var origin *git.Remote
 
+// anyRemote extracts the host of any remote url
+var anyRemote = remoteRegexp("[^/:@]+", true)
+
+var svConfig *config.Config
+
+func loadConfig() {
+	var err error
+	if svConfig, err = config.Load(config.DefaultPath()); err != nil {
+		pterm.Fatal.Println(err)
+	}
+}
+
+// selectProfile returns the profile given on the command line or the one matching the local repository
+func selectProfile() *config.Profile {
+	if profileName != "" {
+		if p, ok := svConfig.Profiles[profileName]; ok {
+			return p
+		}
+		pterm.Fatal.Printfln("Unknown profile '%s'", profileName)
+	}
+
+	host := ""
+	if r, err := localRepository.Remote(defaultOrigin); err == nil && len(r.Config().URLs) > 0 {
+		if m := anyRemote.FindStringSubmatch(r.Config().URLs[0]); m != nil {
+			host = m[anyRemote.SubexpIndex("host")]
+		}
+	}
+
+	var p *config.Profile
+	profileName, p = svConfig.Match(localRepo, host)
+	pterm.Debug.Printfln("Using profile '%s'", profileName)
+	return p
+}
+
+// flagEnvs are the environment variables used as flag defaults, they have precedence over the profile too
+var flagEnvs = map[string]string{
+	"token":        "GITHUB_TOKEN",
+	"gitlab-token": "GITLAB_TOKEN",
+	"gitea-token":  "GITEA_TOKEN",
+}
+
+// setFromProfile sets a flag to the profile value, unless it's given on the command line
+func setFromProfile(cmd *cobra.Command, name string, value string) {
+	if f := cmd.Flag(name); f == nil || f.Changed || value == "" {
+		return
+	} else if env, ok := flagEnvs[name]; ok && os.Getenv(env) != "" {
+		return
+	} else if err := f.Value.Set(value); err != nil {
+		pterm.Fatal.Printfln("Invalid profile value for %s: %v", name, err)
+	}
+}
+
+// applyProfile sets the flags not given on the command line from the profile, the credentials aside
+func applyProfile(cmd *cobra.Command, profile *config.Profile) {
+	setFromProfile(cmd, "remote", profile.Remote)
+	setFromProfile(cmd, "ssh-key-comment", profile.SshKeyComment)
+	setFromProfile(cmd, "editor", profile.Editor)
+	// The viewer and the commands writing texts launch the editor of the flag, or of the profile
+	ui.Editor = editor
+	defaultBaseBranch = profile.BaseBranch
+}
+
 func setupRepo(cmd *cobra.Command, args []string) {
+	setMessagesOutput()
+	loadConfig()
+	if err := checkOutputFormat(); err != nil {
+		pterm.Fatal.Println(err)
+	}
+
 	var err error
 	localRepository, err = git.PlainOpen(localRepo)
 	if err != nil {
 		pterm.Fatal.Println("Cannot open local repo", localRepository)
 	}
 
+	profile := selectProfile()
+	if profile == nil {
+		profile = &config.Profile{}
+	}
+	applyProfile(cmd, profile)
+
 	// analyze remote
 	origin, err = localRepository.Remote(defaultOrigin)
 	if err != nil {
 		pterm.Warning.Println(fmt.Sprintf("Cannot read %s remote url", defaultOrigin), err)
 	}
 
-	url := origin.Config().URLs[0]
-	// Check if local repo is github
+	detectOrigin(origin.Config().URLs[0])
+	applyCredentials(cmd, profile)
+}
+
+// detectOrigin finds the provider, the account and the repository of a remote url
+func detectOrigin(url string) {
+	// A self-hosted instance can have any host name, the hosts given explicitly are matched first, and the usual
+	// host names of every provider after them
+	type candidate struct {
+		tp OriginType
+		re *regexp.Regexp
+	}
+	candidates := make([]candidate, 0)
+	for _, tp := range originTypes {
+		if host, ok := hosts[tp]; ok && *host != "" {
+			candidates = append(candidates, candidate{tp, remoteRegexp(regexp.QuoteMeta(*host), tp == GitLabOriginType)})
+		}
+	}
+	for _, tp := range originTypes {
+		candidates = append(candidates, candidate{tp, origins[tp]})
+	}
 
-	for tp, re := range origins {
+	for _, c := range candidates {
+		tp, re := c.tp, c.re
 		if m := re.FindStringSubmatch(url); m != nil {
 			subexp := make(map[string]string)
 			for i, name := range re.SubexpNames() {
@@ -70,22 +191,112 @@ func setupRepo(cmd *cobra.Command, args []string) {
 			if repoSlug == "" {
 				repoSlug = subexp["repo"]
 			}
+			if host, ok := hosts[tp]; ok && *host == "" {
+				*host = subexp["host"]
+			}
 			originType = tp
 			break
 		}
 	}
 }
 
+// originHost is the host of a provider, either deduced from the remote or the public instance
+func originHost(tp OriginType) string {
+	switch tp {
+	case GitHubOriginType:
+		if githubHost != "" {
+			return githubHost
+		}
+		return "github.com"
+	case BitbucketOriginType:
+		return "bitbucket.org"
+	case GitLabOriginType:
+		if gitlabHost != "" {
+			return gitlabHost
+		}
+		return "gitlab.com"
+	case GiteaOriginType:
+		return giteaHost
+	}
+	return ""
+}
+
+// applyCredentials sets the credentials of the remote provider from the profile, then from the ones stored by
+// sv auth login
+func applyCredentials(cmd *cobra.Command, profile *config.Profile) {
+	switch originType {
+	case GitHubOriginType:
+		setFromProfile(cmd, "token", profile.Token)
+		setFromCredentials(cmd, originHost(originType), "", "token")
+	case GitLabOriginType:
+		setFromProfile(cmd, "gitlab-token", profile.Token)
+		setFromCredentials(cmd, originHost(originType), "", "gitlab-token")
+	case GiteaOriginType:
+		setFromProfile(cmd, "gitea-token", profile.Token)
+		setFromCredentials(cmd, originHost(originType), "", "gitea-token")
+	case BitbucketOriginType:
+		setFromProfile(cmd, "username", profile.Username)
+		setFromProfile(cmd, "password", profile.AppPassword)
+		setFromCredentials(cmd, originHost(originType), "username", "password")
+	}
+}
+
+// setFromCredentials reads the secret flag, and the user one if any, from the credential helper when it's not set yet.
+// The helper is only asked for the credentials of sv, not for the ones git uses for the remote.
+func setFromCredentials(cmd *cobra.Command, host string, userFlag string, secretFlag string) {
+	secret := cmd.Flag(secretFlag)
+	if secret == nil || secret.Value.String() != "" {
+		return
+	}
+
+	username := ""
+	if userFlag != "" {
+		username = cmd.Flag(userFlag).Value.String()
+	}
+
+	if c, err := config.LoadCredential(host, username); err != nil {
+		pterm.Debug.Printfln("No credential for %s: %v", host, err)
+	} else {
+		if userFlag != "" && username == "" {
+			_ = cmd.Flag(userFlag).Value.Set(c.Username)
+		}
+		_ = secret.Value.Set(c.Secret)
+	}
+}
+
 type OriginType int
 
 const (
 	GitHubOriginType OriginType = iota
 	BitbucketOriginType
+	GitLabOriginType
+	GiteaOriginType
 	UnknownOriginType
 )
 
 var originType = UnknownOriginType
 
+var originTypeNames = map[OriginType]string{
+	GitHubOriginType:    "github",
+	BitbucketOriginType: "bitbucket",
+	GitLabOriginType:    "gitlab",
+	GiteaOriginType:     "gitea",
+	UnknownOriginType:   "unknown",
+}
+
+func (t OriginType) String() string {
+	return originTypeNames[t]
+}
+
+func parseOriginType(name string) (OriginType, error) {
+	for tp, n := range originTypeNames {
+		if n == name && tp != UnknownOriginType {
+			return tp, nil
+		}
+	}
+	return UnknownOriginType, fmt.Errorf("unknown provider '%s', expected github, bitbucket, gitlab or gitea", name)
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -99,12 +310,26 @@ var _token, _username, _password *string
 
 var account, repoSlug string
 
-var githubToken string
+var githubToken, githubHost, githubApiUrl, githubGraphQLUrl string
+
+var gitlabToken, gitlabHost string
+
+var giteaToken, giteaHost string
 
 var localRepo string
 
+var profileName string
+
+var editor string
+
+// defaultBaseBranch is the base of the new PRs when not given, the default branch of the repository if empty
+var defaultBaseBranch string
+
 var sshKeyComment string
 
+// noCache disables the api cache, offline serves the cached responses only
+var noCache, offline bool
+
 func GetClient() (*bitbucket.APIClient, context.Context) {
 	cfg := bitbucket.NewConfiguration()
 	cfg.HTTPClient = &http.Client{}
@@ -113,15 +338,53 @@ func GetClient() (*bitbucket.APIClient, context.Context) {
 	return bitbucket.NewAPIClient(cfg), ctx
 }
 
+// githubApiUrls are the endpoints of a GitHub host, unless given on the command line
+func githubApiUrls(host string) (string, string) {
+	apiUrl, graphQLUrl := sv.GitHubApiUrls(host)
+	if githubApiUrl != "" {
+		apiUrl = githubApiUrl
+	}
+	if githubGraphQLUrl != "" {
+		graphQLUrl = githubGraphQLUrl
+	}
+	return apiUrl, graphQLUrl
+}
+
+// GetSv returns the provider of the origin, its api responses going through the cache unless disabled
 func GetSv() sv.Sv {
-	if len(githubToken) > 0 {
+	s := newSv()
+	if noCache && offline {
+		pterm.Fatal.Println("--offline needs the cache")
+	} else if noCache {
+		return s
+	}
+	dir, err := httpCacheDir()
+	if err != nil {
+		if offline {
+			pterm.Fatal.Printfln("Cannot find the cache directory: %v", err)
+		}
+		pterm.Debug.Printfln("cannot find the cache directory, not caching: %v", err)
+		return s
+	}
+	cache := sv.NewCache(dir)
+	cache.Offline = offline
+	return sv.NewCachedSv(s, cache)
+}
+
+func newSv() sv.Sv {
+	if originType == GitLabOriginType {
+		return sv.NewGitLabSv(gitlabToken, sv.GitLabApiUrl(gitlabHost), nil, localRepo, sshKeyComment, fmt.Sprintf("%s/%s", account, repoSlug))
+	} else if originType == GiteaOriginType {
+		return sv.NewGiteaSv(giteaToken, sv.GiteaApiUrl(giteaHost), nil, localRepo, sshKeyComment, account, repoSlug)
+	} else if len(githubToken) > 0 {
 		if originType != GitHubOriginType {
-			pterm.Warning.Println("Remote '%s' mismatches with origin url : %s", defaultOrigin, origin.Config().URLs[0])
+			pterm.Warning.Printfln("Remote '%s' mismatches with origin url : %s", defaultOrigin, origin.Config().URLs[0])
 		}
-		return sv.NewGitHubSv(githubToken, localRepo, sshKeyComment, account, repoSlug)
+		apiUrl, graphQLUrl := githubApiUrls(githubHost)
+		return sv.NewGitHubSv(githubToken, apiUrl, graphQLUrl, localRepo, sshKeyComment, account, repoSlug)
 	} else {
 		if originType != BitbucketOriginType {
-			pterm.Warning.Println("Remote '%s' mismatches with origin url : %s", defaultOrigin, origin.Config().URLs[0])
+			pterm.Warning.Printfln("Remote '%s' mismatches with origin url : %s", defaultOrigin, origin.Config().URLs[0])
 		}
 		return sv.NewBitBucketSv(*_username, *_password, repoSlug, account, localRepo)
 	}
@@ -137,15 +400,25 @@ func init() {
 	if err != nil {
 		pterm.Warning.Println("Cannot get wd", wd)
 	}
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.gobb-cli.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", os.Getenv("SV_PROFILE"), "Configuration profile (default value will be deduced from the workspace or the remote host)")
 	_username = rootCmd.PersistentFlags().StringP("username", "u", "", "Username")
 	_password = rootCmd.PersistentFlags().StringP("password", "p", "", "Password")
 	rootCmd.PersistentFlags().StringVarP(&account, "account", "a", "", "Account (default value will be deduced from the local repo)")
 	rootCmd.PersistentFlags().StringVarP(&repoSlug, "repository", "r", "", "Repository (Account (default value will be deduced from the local repo)")
 	rootCmd.PersistentFlags().StringVarP(&githubToken, "token", "t", os.Getenv("GITHUB_TOKEN"), "Github token")
+	rootCmd.PersistentFlags().StringVar(&githubHost, "github-host", "", "GitHub host, github.com or an Enterprise Server (default value will be deduced from the local repo)")
+	rootCmd.PersistentFlags().StringVar(&githubApiUrl, "github-api-url", "", "GitHub REST api url (default value will be deduced from the GitHub host)")
+	rootCmd.PersistentFlags().StringVar(&githubGraphQLUrl, "github-graphql-url", "", "GitHub GraphQL api url (default value will be deduced from the GitHub host)")
+	rootCmd.PersistentFlags().StringVar(&gitlabToken, "gitlab-token", os.Getenv("GITLAB_TOKEN"), "GitLab token")
+	rootCmd.PersistentFlags().StringVar(&gitlabHost, "gitlab-host", "", "GitLab host (default value will be deduced from the local repo)")
+	rootCmd.PersistentFlags().StringVar(&giteaToken, "gitea-token", os.Getenv("GITEA_TOKEN"), "Gitea/Forgejo token")
+	rootCmd.PersistentFlags().StringVar(&giteaHost, "gitea-host", "", "Gitea/Forgejo host (default value will be deduced from the local repo)")
 	rootCmd.PersistentFlags().StringVarP(&localRepo, "workspace", "w", wd, "Local copy")
 	rootCmd.PersistentFlags().StringVar(&defaultOrigin, "remote", "origin", "Default origin to use")
 	rootCmd.PersistentFlags().StringVarP(&sshKeyComment, "ssh-key-comment", "K", ".*", "REGEXP that should match with the SSH key to be used")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Don't cache the api responses")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "Only show the cached api responses, without reaching the network")
+	rootCmd.PersistentFlags().StringVar(&editor, "editor", "", "Editor command for the texts to write (the builtin editor if empty)")
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 
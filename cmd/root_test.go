@@ -0,0 +1,34 @@
+package cmd
+
+import "testing"
+
+// resetOrigin clears what detectOrigin deduces, and sets the GitHub host flag
+func resetOrigin(t *testing.T, host string) {
+	t.Cleanup(func() {
+		account, repoSlug, githubHost, originType = "", "", "", UnknownOriginType
+	})
+	account, repoSlug, githubHost, originType = "", "", host, UnknownOriginType
+}
+
+func TestDetectOriginConfiguredHost(t *testing.T) {
+	resetOrigin(t, "git.example.com")
+	detectOrigin("git@git.example.com:me/repo.git")
+	if originType != GitHubOriginType || account != "me" || repoSlug != "repo" {
+		t.Errorf("enterprise remote = %v %s/%s", originType, account, repoSlug)
+	}
+}
+
+func TestDetectOriginDefaultHosts(t *testing.T) {
+	// An Enterprise Server host given for other repositories doesn't hide the github.com ones
+	resetOrigin(t, "git.example.com")
+	detectOrigin("https://github.com/me/repo.git")
+	if originType != GitHubOriginType || account != "me" || repoSlug != "repo" {
+		t.Errorf("github.com remote = %v %s/%s", originType, account, repoSlug)
+	}
+
+	resetOrigin(t, "")
+	detectOrigin("git@github.com:me/repo.git")
+	if originType != GitHubOriginType || githubHost != "github.com" {
+		t.Errorf("github.com remote = %v on %s", originType, githubHost)
+	}
+}
@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/antihax/optional"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/vballestra/sv/sv"
+	"strings"
+)
+
+// stackCmd represents the stack command
+var stackCmd = &cobra.Command{
+	Use:   "stack",
+	Short: "Stacked PRs",
+	Long: `Works with the stack of the current branch: the chain of dependent branches going from the trunk to it, and
+above it as long as a single branch is based on the previous one. The parent of a branch is the nearest local branch
+among its commits missing from the trunk. Each branch gets a PR based on its parent, whose description holds a table
+of the whole stack.`,
+}
+
+// stackShowCmd represents the stackShow command
+var stackShowCmd = &cobra.Command{
+	Use:     "show",
+	Short:   "Shows the stack of the current branch",
+	Long:    `Shows the branches of the stack of the current branch with their PR, from the top to the trunk.`,
+	Aliases: []string{"ls"},
+	Run: func(cmd *cobra.Command, args []string) {
+		s := GetSv()
+		stack, current := discoverStack(s)
+		entries, err := stackPullRequests(s, stack)
+		if err != nil {
+			pterm.Fatal.Println(err)
+		}
+
+		data := pterm.TableData{{"", "Branch", "Base", "PR", "State"}}
+		for i := len(entries) - 1; i >= 0; i-- {
+			e := entries[i]
+			marker := ""
+			if e.branch == current {
+				marker = "*"
+			}
+			id, state := "", "not submitted"
+			if e.merged {
+				state = "merged"
+			} else if e.pr != nil {
+				id, state = fmt.Sprint(e.pr.GetId()), "open"
+				if e.pr.IsDraft() {
+					state = "draft"
+				}
+				if base := e.pr.GetBase().GetName(); base != stack.Parent(i) {
+					state += fmt.Sprintf(" (based on %s)", base)
+				}
+			}
+			data = append(data, []string{marker, e.branch, stack.Parent(i), id, state})
+		}
+		if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+			pterm.Fatal.Println(err)
+		}
+	},
+}
+
+// stackSubmitCmd represents the stackSubmit command
+var stackSubmitCmd = &cobra.Command{
+	Use:   "submit",
+	Short: "Creates or updates the PRs of the stack",
+	Long: `Pushes every branch of the stack, creates the missing PRs based on the parent branch, retargets the PRs based
+on another branch and puts the stack table in their description. The title and the description of the new PRs are
+inferred as with prs new.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		s := GetSv()
+		stack, _ := discoverStack(s)
+		entries, err := stackPullRequests(s, stack)
+		if err != nil {
+			pterm.Fatal.Println(err)
+		}
+
+		for i, e := range entries {
+			parent := stack.Parent(i)
+			if e.merged {
+				pterm.Fatal.Printfln("The PR of %s is merged, run stack sync first", e.branch)
+			}
+			if pushed, err := s.PushBranch(defaultOrigin, e.branch, stackForce); err != nil {
+				pterm.Fatal.Printfln("couldn't push %s : %v", e.branch, err)
+			} else if pushed {
+				pterm.Info.Printfln("Pushed %s", e.branch)
+			}
+
+			if e.pr == nil {
+				if status, err := s.CreatePullRequest(sv.CreatePullRequestArgs{
+					BaseBranch:          optional.NewString(parent),
+					HeadBranch:          optional.NewString(e.branch),
+					CreateMissingLabels: true,
+					Draft:               stackDraft,
+					Remote:              defaultOrigin,
+				}); err != nil {
+					pterm.Fatal.Printfln("couldn't create the PR of %s : %v", e.branch, err)
+				} else if entries[i].pr, err = s.GetPullRequest(fmt.Sprint(status.GetId())); err != nil {
+					pterm.Fatal.Println(err)
+				} else {
+					pterm.Success.Printfln("PR %v created for %s", status.GetId(), e.branch)
+				}
+			} else if err := retarget(e, parent); err != nil {
+				pterm.Fatal.Println(err)
+			}
+		}
+
+		updateStackTables(stack, entries)
+	},
+}
+
+// stackSyncCmd represents the stackSync command
+var stackSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Restacks the branches once PRs are merged",
+	Long: `Fetches the remote, then the branches whose PR is merged leave the stack: the branches above them are rebased
+onto the next branch below, the trunk at the bottom, force pushed and their PR is retargeted. The force push is
+refused when the remote branch changed since the fetch. The stack tables are updated. The merged local branches are
+kept.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		s := GetSv()
+		if err := sv.ForceFetch(s, defaultOrigin); err != nil {
+			pterm.Warning.Printfln("Cannot fetch: %v", err)
+		}
+		stack, _ := discoverStack(s)
+		all, err := stackPullRequests(s, stack)
+		if err != nil {
+			pterm.Fatal.Println(err)
+		}
+
+		kept := sv.Stack{Trunk: stack.Trunk, Heads: stack.Heads}
+		entries := make([]stackEntry, 0, len(all))
+		oldParents := make(map[string]string)
+		for i, e := range all {
+			if e.merged {
+				pterm.Info.Printfln("The PR of %s is merged, it leaves the stack", e.branch)
+				continue
+			}
+			kept.Branches = append(kept.Branches, e.branch)
+			entries = append(entries, e)
+			oldParents[e.branch] = stack.Parent(i)
+		}
+
+		rebased := make(map[string]bool)
+		for i, e := range entries {
+			parent, oldParent := kept.Parent(i), oldParents[e.branch]
+			if parent != oldParent || rebased[parent] {
+				onto := parent
+				if parent == kept.Trunk {
+					onto = defaultOrigin + "/" + parent
+				}
+				if err := sv.RebaseBranch(localRepo, e.branch, stack.Heads[oldParent], onto); err != nil {
+					pterm.Fatal.Println(err)
+				}
+				rebased[e.branch] = true
+				pterm.Success.Printfln("Rebased %s onto %s", e.branch, onto)
+			}
+			if e.pr == nil {
+				continue
+			}
+
+			if err := retarget(e, parent); err != nil {
+				pterm.Fatal.Println(err)
+			} else if pushed, err := s.PushBranch(defaultOrigin, e.branch, rebased[e.branch]); err != nil {
+				pterm.Fatal.Printfln("couldn't push %s : %v", e.branch, err)
+			} else if pushed {
+				pterm.Info.Printfln("Pushed %s", e.branch)
+			}
+		}
+
+		updateStackTables(kept, entries)
+	},
+}
+
+var stackTrunk string
+var stackDraft, stackForce bool
+
+// stackEntry is a branch of the stack with its open PR, nil when there is none. merged tells that it has a merged
+// PR instead.
+type stackEntry struct {
+	branch string
+	pr     sv.PullRequest
+	merged bool
+}
+
+// discoverStack finds the stack of the current branch, it returns the stack and the current branch
+func discoverStack(s sv.Sv) (sv.Stack, string) {
+	trunk := stackTrunk
+	if trunk == "" {
+		trunk = defaultBaseBranch
+	}
+	if trunk == "" {
+		if defaultBranch, err := s.GetDefaultBranch(); err != nil {
+			pterm.Fatal.Println(err)
+		} else {
+			trunk = defaultBranch
+		}
+	}
+
+	current, err := s.GetCurrentBranch()
+	if err != nil {
+		pterm.Fatal.Println(err)
+	}
+	stack, err := sv.DiscoverStack(localRepo, defaultOrigin, trunk, current)
+	if err != nil {
+		pterm.Fatal.Println(err)
+	}
+	return stack, current
+}
+
+// branchPullRequest returns the PR of the branch in the given state, nil when there is none
+func branchPullRequest(s sv.Sv, branch string, state string) (sv.PullRequest, error) {
+	prs, err := s.ListPullRequests(sv.PullRequestFilter{Head: branch, State: state, MaxPages: 1})
+	if err != nil {
+		return nil, err
+	}
+	var found sv.PullRequest
+	for pr := range prs {
+		if found == nil && pr.GetBranch().GetName() == branch {
+			found = pr
+		}
+	}
+	return found, nil
+}
+
+func stackPullRequests(s sv.Sv, stack sv.Stack) ([]stackEntry, error) {
+	entries := make([]stackEntry, 0, len(stack.Branches))
+	for _, branch := range stack.Branches {
+		if pr, err := branchPullRequest(s, branch, "open"); err != nil {
+			return nil, err
+		} else if pr != nil {
+			entries = append(entries, stackEntry{branch: branch, pr: pr})
+		} else if merged, err := branchPullRequest(s, branch, "merged"); err != nil {
+			return nil, err
+		} else {
+			entries = append(entries, stackEntry{branch: branch, merged: merged != nil})
+		}
+	}
+	return entries, nil
+}
+
+// retarget changes the base of the PR of the entry when it isn't the parent
+func retarget(e stackEntry, parent string) error {
+	if e.pr.GetBase().GetName() == parent {
+		return nil
+	} else if err := e.pr.Edit(sv.EditPullRequestArgs{BaseBranch: optional.NewString(parent)}); err != nil {
+		return fmt.Errorf("couldn't retarget the PR of %s onto %s : %w", e.branch, parent, err)
+	}
+	pterm.Info.Printfln("PR %v retargeted onto %s", e.pr.GetId(), parent)
+	return nil
+}
+
+// stackTable is the markdown table of the stack, from the top to the trunk, pointing at the current entry
+func stackTable(stack sv.Stack, entries []stackEntry, current int) string {
+	var b strings.Builder
+	b.WriteString("**Stack**\n\n|   | Pull request | Branch |\n|---|---|---|\n")
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		marker, pr := "", ""
+		if i == current {
+			marker = "👉"
+		}
+		if e.pr != nil {
+			pr = fmt.Sprintf("#%v %s", e.pr.GetId(), strings.ReplaceAll(e.pr.GetTitle(), "|", `\|`))
+			if url := e.pr.GetUrl(); url != "" {
+				pr = fmt.Sprintf("[%s](%s)", pr, url)
+			}
+		}
+		fmt.Fprintf(&b, "| %s | %s | `%s` |\n", marker, pr, e.branch)
+	}
+	fmt.Fprintf(&b, "|   |   | `%s` |\n", stack.Trunk)
+	return b.String()
+}
+
+// updateStackTables puts the stack table in the description of every PR of the stack
+func updateStackTables(stack sv.Stack, entries []stackEntry) {
+	for i, e := range entries {
+		if e.pr == nil {
+			continue
+		}
+		description := sv.WithStackTable(e.pr.GetDescription(), stackTable(stack, entries, i))
+		if description == e.pr.GetDescription() {
+			continue
+		}
+		if err := e.pr.Edit(sv.EditPullRequestArgs{Description: optional.NewString(description)}); err != nil {
+			pterm.Warning.Printfln("Cannot update the description of PR %v: %v", e.pr.GetId(), err)
+		}
+	}
+	pterm.Success.Printfln("Stack of %d branches up to date", len(entries))
+}
+
+func init() {
+	rootCmd.AddCommand(stackCmd)
+	stackCmd.AddCommand(stackShowCmd)
+	stackCmd.AddCommand(stackSubmitCmd)
+	stackCmd.AddCommand(stackSyncCmd)
+
+	stackCmd.PersistentFlags().StringVar(&stackTrunk, "trunk", "", "The branch at the bottom of the stack, the base branch of the profile or the default branch otherwise")
+	stackSubmitCmd.Flags().BoolVar(&stackDraft, "draft", false, "Open the new PRs as drafts")
+	stackSubmitCmd.Flags().BoolVar(&stackForce, "force", false, "Force push the branches that diverged from the remote after a rebase, unless the remote branches changed since the last fetch")
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/pterm/pterm"
 	"strings"
+	"time"
 )
 
 type contentView struct {
@@ -17,6 +18,13 @@ type contentView struct {
 	selectedLine int
 	oldLine      string
 	codeLines    map[int]codeLine
+	// rightCodeLines are the code lines of the right column of the split diff, codeLines holding the left one
+	rightCodeLines map[int]codeLine
+	// splitWidth is the width of a column of the split diff, 0 for the unified diff
+	splitWidth   int
+	selectedSide diffSide
+	// matches are the lines matching the search, in order
+	matches []searchMatch
 }
 
 func (cv *contentView) printf(line string, args ...any) {
@@ -27,6 +35,9 @@ func (cv *contentView) clear() {
 	c := make(lines, 0)
 	cv.content = &c
 	cv.codeLines = make(map[int]codeLine)
+	cv.rightCodeLines = make(map[int]codeLine)
+	cv.splitWidth = 0
+	cv.matches = nil
 }
 
 func (c contentView) Init() tea.Cmd {
@@ -99,8 +110,14 @@ func (content contentView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				content.selectLine(-1)
 			case "r":
 				return content, lineCommand(replyComment, content.selectedLine, nil)
+			case "left":
+				content.selectSide(leftSide)
+			case "right":
+				content.selectSide(rightSide)
 			case "+":
-				if ln, ok := content.codeLines[content.selectedLine]; ok {
+				if ln, ok := content.selectedCode(); ok && !ln.inDiff() {
+					return content, showStatusCmd(normalMode, "Only the lines of the diff can be commented, not the context around them", 3*time.Second)
+				} else if ok {
 					cmd := lineCommand(newComment, content.selectedLine, &ln)
 					content.selectLine(-1)
 					return content, cmd
@@ -145,16 +162,22 @@ func (c *contentView) selectLine(l int) {
 
 	if l != -1 {
 		c.oldLine = (*c.content)[l]
-		newLine := pterm.RemoveColorFromString(c.oldLine)
-		newLine = lipgloss.NewStyle().
+		newLine := []rune(pterm.RemoveColorFromString(c.oldLine))
+		start := 0
+		if c.splitWidth > 0 && c.selectedSide == rightSide {
+			start = c.splitWidth + 1
+		}
+		if len(newLine) >= start+5 {
+			copy(newLine[start:], []rune("➡    "))
+		}
+		(*c.content)[l] = lipgloss.NewStyle().
 			Width(c.viewport.Width).
 			MaxHeight(1).
 			Background(lipgloss.Color("#a00000")).
 			Foreground(lipgloss.Color("#ffffff")).
 			Bold(true).
 			Italic(true).
-			Render("➡    " + newLine[5:])
-		(*c.content)[l] = newLine
+			Render(string(newLine))
 		c.selectedLine = l
 	}
 
@@ -176,8 +199,46 @@ type codeLine struct {
 	file     *gitdiff.File
 	code     gitdiff.Line
 	commitId string
+	// isNew tells whether comments go to the new side of the line
+	isNew bool
+}
+
+// inDiff tells whether the line is one of the fragments of the diff, the context added around them can't be commented
+func (l codeLine) inDiff() bool {
+	if l.code.Op == gitdiff.OpDelete {
+		return true
+	}
+	for _, frag := range l.file.TextFragments {
+		if l.new >= frag.NewPosition && l.new < frag.NewPosition+frag.NewLines {
+			return true
+		}
+	}
+	return false
 }
 
-func (c *contentView) saveLine(commitId string, old int64, new int64, pos int, path *gitdiff.File, ln gitdiff.Line) {
-	c.codeLines[len(*c.content)] = codeLine{old, new, pos, path, ln, commitId}
+func (c *contentView) saveLine(commitId string, old int64, new int64, pos int, path *gitdiff.File, ln gitdiff.Line, isNew bool) {
+	c.codeLines[len(*c.content)] = codeLine{old, new, pos, path, ln, commitId, isNew}
+}
+
+// saveRightLine saves the code line of the right column of the split diff, always commented on its new side
+func (c *contentView) saveRightLine(commitId string, old int64, new int64, pos int, path *gitdiff.File, ln gitdiff.Line) {
+	c.rightCodeLines[len(*c.content)] = codeLine{old, new, pos, path, ln, commitId, true}
+}
+
+// selectedCode is the code line of the selected side of the selected line, the other side when it's empty
+func (c *contentView) selectedCode() (codeLine, bool) {
+	left, hasLeft := c.codeLines[c.selectedLine]
+	right, hasRight := c.rightCodeLines[c.selectedLine]
+	if hasRight && (c.selectedSide == rightSide || !hasLeft) {
+		return right, true
+	}
+	return left, hasLeft
+}
+
+// selectSide moves the selection to a column of the split diff
+func (c *contentView) selectSide(side diffSide) {
+	if c.splitWidth > 0 && c.selectedSide != side {
+		c.selectedSide = side
+		c.selectLine(c.selectedLine)
+	}
 }
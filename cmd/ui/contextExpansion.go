@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
+	"math"
+	"strings"
+)
+
+// contextStep is the number of lines of context added by each expansion
+const contextStep = 10
+
+const HUNK_CATEGORY = bookmarkCategory("HUNK")
+
+// fragmentContext is the number of lines of the file added as context above and below a fragment of the diff
+type fragmentContext struct {
+	above int
+	below int
+}
+
+// expandedFragment is a fragment shown with more context, covering the fragments of the diff from first to last when
+// the added context joined them
+type expandedFragment struct {
+	*gitdiff.TextFragment
+	first *gitdiff.TextFragment
+	last  *gitdiff.TextFragment
+}
+
+// hunkBookmark is the data of the bookmark of a shown fragment
+type hunkBookmark struct {
+	file     *gitdiff.File
+	fragment expandedFragment
+}
+
+// fileLines returns the lines of the file in the head of the pull request, read once
+func (d *pullRequestData) fileLines(path string) ([]string, error) {
+	if lines, ok := d.fileContents[path]; ok {
+		return lines, nil
+	}
+	content, err := d.GetFileContent(path, true)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	d.fileContents[path] = lines
+	return lines, nil
+}
+
+// expandContext adds context above or below the fragment, the whole file being read first so that it can fail
+func (d *pullRequestData) expandContext(file *gitdiff.File, frag *gitdiff.TextFragment, above int, below int) error {
+	if _, err := d.fileLines(getFileName(file)); err != nil {
+		return err
+	}
+	ctx := d.contexts[frag]
+	ctx.above += above
+	ctx.below += below
+	d.contexts[frag] = ctx
+	d.forgetExpansion(file)
+	return nil
+}
+
+// toggleWholeFile shows the whole file with the changes inlined, or only the fragments again
+func (d *pullRequestData) toggleWholeFile(file *gitdiff.File) error {
+	if _, err := d.fileLines(getFileName(file)); err != nil {
+		return err
+	}
+	d.wholeFiles[file] = !d.wholeFiles[file]
+	d.forgetExpansion(file)
+	return nil
+}
+
+// forgetExpansion drops the expanded fragments of the file and their highlighting, they're computed again
+func (d *pullRequestData) forgetExpansion(file *gitdiff.File) {
+	for _, frag := range d.expanded[file] {
+		delete(d.highlights, frag.TextFragment)
+	}
+	delete(d.expanded, file)
+}
+
+// fileFragments are the fragments of the file to show, with their added context
+func (d *pullRequestData) fileFragments(file *gitdiff.File) []expandedFragment {
+	if fragments, ok := d.expanded[file]; ok {
+		return fragments
+	}
+
+	fragments := make([]expandedFragment, 0, len(file.TextFragments))
+	if !d.hasContext(file) {
+		for _, frag := range file.TextFragments {
+			fragments = append(fragments, expandedFragment{frag, frag, frag})
+		}
+		return fragments
+	}
+	lines, err := d.fileLines(getFileName(file))
+	if err != nil {
+		for _, frag := range file.TextFragments {
+			fragments = append(fragments, expandedFragment{frag, frag, frag})
+		}
+		return fragments
+	}
+
+	contexts := d.contexts
+	if d.wholeFiles[file] {
+		contexts = make(map[*gitdiff.TextFragment]fragmentContext)
+		for _, frag := range file.TextFragments {
+			contexts[frag] = fragmentContext{math.MaxInt32, math.MaxInt32}
+		}
+	}
+	fragments = expandFragments(file.TextFragments, lines, contexts)
+	d.expanded[file] = fragments
+	return fragments
+}
+
+func (d *pullRequestData) hasContext(file *gitdiff.File) bool {
+	if d.wholeFiles[file] {
+		return true
+	}
+	for _, frag := range file.TextFragments {
+		if _, ok := d.contexts[frag]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// expandFragments adds the lines of the new file around the fragments as context lines, the fragments joined by
+// their context are merged. The added lines aren't in the diff, they can't be commented (see codeLine.inDiff).
+func expandFragments(frags []*gitdiff.TextFragment, lines []string, contexts map[*gitdiff.TextFragment]fragmentContext) []expandedFragment {
+	result := make([]expandedFragment, 0, len(frags))
+	// covered is the last line of the new file shown so far
+	covered := int64(0)
+	for i, frag := range frags {
+		ctx := contexts[frag]
+		// The new lines of the fragment, a fragment only deleting lines comes after its new position
+		first, last := frag.NewPosition, frag.NewPosition+frag.NewLines-1
+		if frag.NewLines == 0 {
+			first, last = frag.NewPosition+1, frag.NewPosition
+		}
+
+		start := maxInt64(first-int64(ctx.above), covered+1, 1)
+		end := minInt64(last+int64(ctx.below), int64(len(lines)))
+		if i+1 < len(frags) {
+			next := frags[i+1].NewPosition
+			if frags[i+1].NewLines == 0 {
+				next++
+			}
+			end = minInt64(end, next-1)
+		}
+
+		above := contextLines(lines, start, first-1)
+		below := contextLines(lines, last+1, end)
+		expanded := *frag
+		expanded.Lines = make([]gitdiff.Line, 0, len(above)+len(frag.Lines)+len(below))
+		expanded.Lines = append(append(append(expanded.Lines, above...), frag.Lines...), below...)
+		expanded.OldPosition -= int64(len(above))
+		expanded.NewPosition = start
+		expanded.OldLines += int64(len(above) + len(below))
+		expanded.NewLines += int64(len(above) + len(below))
+		// The position of a side without lines is the line before, it becomes the first shown line with context
+		if frag.OldLines == 0 && expanded.OldLines > 0 {
+			expanded.OldPosition++
+		}
+		if expanded.NewLines == 0 {
+			expanded.NewPosition = frag.NewPosition
+		}
+		expanded.LeadingContext += int64(len(above))
+		expanded.TrailingContext += int64(len(below))
+
+		if n := len(result); n > 0 && joins(result[n-1], start) {
+			result[n-1] = mergeFragments(result[n-1], expandedFragment{&expanded, frag, frag})
+		} else {
+			result = append(result, expandedFragment{&expanded, frag, frag})
+		}
+		covered = maxInt64(end, last)
+	}
+	return result
+}
+
+// joins tells whether the fragment ends right before the line of the new file
+func joins(f expandedFragment, line int64) bool {
+	return f.NewPosition+f.NewLines == line || (f.NewLines == 0 && f.NewPosition+1 == line)
+}
+
+// mergeFragments appends the lines of b to a, b starting right after a
+func mergeFragments(a expandedFragment, b expandedFragment) expandedFragment {
+	merged := *a.TextFragment
+	merged.Lines = append(append(make([]gitdiff.Line, 0, len(a.Lines)+len(b.Lines)), a.Lines...), b.Lines...)
+	merged.OldLines += b.OldLines
+	merged.NewLines += b.NewLines
+	merged.LinesAdded += b.LinesAdded
+	merged.LinesDeleted += b.LinesDeleted
+	merged.TrailingContext = b.TrailingContext
+	return expandedFragment{&merged, a.first, b.last}
+}
+
+// contextLines are the lines from..to of the file, numbered from 1, as context lines
+func contextLines(lines []string, from int64, to int64) []gitdiff.Line {
+	result := make([]gitdiff.Line, 0)
+	for n := from; n <= to && n <= int64(len(lines)); n++ {
+		if n >= 1 {
+			result = append(result, gitdiff.Line{Op: gitdiff.OpContext, Line: lines[n-1]})
+		}
+	}
+	return result
+}
+
+func maxInt64(a ...int64) int64 {
+	m := a[0]
+	for _, v := range a[1:] {
+		if v > m {
+			m = v
+		}
+	}
+	return m
+}
+
+func minInt64(a ...int64) int64 {
+	m := a[0]
+	for _, v := range a[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,322 @@
+package ui
+
+import (
+	"github.com/alecthomas/chroma"
+	"github.com/alecthomas/chroma/lexers"
+	"github.com/alecthomas/chroma/styles"
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
+	"github.com/charmbracelet/lipgloss"
+	"regexp"
+	"strings"
+)
+
+// syntaxStyle colours the code of the diffs, the same as the markdown of the comments
+var syntaxStyle = styles.Get("dracula")
+
+// span is a piece of a diff line with its syntax colour, emphasized when it belongs to the words changed by the line,
+// matched when it matches the search
+type span struct {
+	text       string
+	entry      chroma.StyleEntry
+	emphasized bool
+	matched    bool
+}
+
+// fragmentSpans returns the highlighted lines of the fragment, indexed like its lines, computed once per fragment
+func (d *pullRequestData) fragmentSpans(fileName string, frag *gitdiff.TextFragment) [][]span {
+	if spans, ok := d.highlights[frag]; ok {
+		return spans
+	}
+	spans := highlightFragment(lexers.Match(fileName), frag)
+	markWordChanges(frag, spans)
+	d.highlights[frag] = spans
+	return spans
+}
+
+// highlightFragment tokenizes the old and the new side of the fragment separately, so that each line is highlighted
+// in the context of its side. The lines are left plain when there is no lexer for the file.
+func highlightFragment(lexer chroma.Lexer, frag *gitdiff.TextFragment) [][]span {
+	var oldText, newText strings.Builder
+	for _, ln := range frag.Lines {
+		text := lineText(ln) + "\n"
+		if ln.Op != gitdiff.OpAdd {
+			oldText.WriteString(text)
+		}
+		if ln.Op != gitdiff.OpDelete {
+			newText.WriteString(text)
+		}
+	}
+	oldLines, newLines := tokenLines(lexer, oldText.String()), tokenLines(lexer, newText.String())
+
+	spans := make([][]span, len(frag.Lines))
+	oldN, newN := 0, 0
+	for i, ln := range frag.Lines {
+		var tokens []chroma.Token
+		if ln.Op == gitdiff.OpDelete {
+			if oldN < len(oldLines) {
+				tokens = oldLines[oldN]
+			}
+		} else if newN < len(newLines) {
+			tokens = newLines[newN]
+		}
+		if ln.Op != gitdiff.OpAdd {
+			oldN++
+		}
+		if ln.Op != gitdiff.OpDelete {
+			newN++
+		}
+		spans[i] = lineSpans(lineText(ln), tokens)
+	}
+	return spans
+}
+
+// lineText is the line without its end of line
+func lineText(ln gitdiff.Line) string {
+	return strings.TrimRight(ln.Line, "\r\n")
+}
+
+// tokenLines tokenizes the text and splits its tokens by line, nil when it can't
+func tokenLines(lexer chroma.Lexer, text string) [][]chroma.Token {
+	if lexer == nil {
+		return nil
+	}
+	it, err := chroma.Coalesce(lexer).Tokenise(nil, text)
+	if err != nil {
+		return nil
+	}
+	return chroma.SplitTokensIntoLines(it.Tokens())
+}
+
+// lineSpans colours the line with its tokens, the line is kept plain when they don't match it
+func lineSpans(text string, tokens []chroma.Token) []span {
+	spans := make([]span, 0, len(tokens))
+	var b strings.Builder
+	for _, t := range tokens {
+		value := strings.TrimRight(t.Value, "\n")
+		if value == "" {
+			continue
+		}
+		b.WriteString(value)
+		entry := syntaxStyle.Get(t.Type)
+		if entry.Colour == syntaxStyle.Get(chroma.Background).Colour {
+			// The plain text keeps the colour of the line
+			entry.Colour = 0
+		}
+		spans = append(spans, span{text: value, entry: entry})
+	}
+	if b.String() != text {
+		return []span{{text: text}}
+	}
+	return spans
+}
+
+// markWordChanges emphasizes the words that changed between the deleted lines and the added lines replacing them,
+// the k-th deleted line of a block being paired with the k-th added line of the following block
+func markWordChanges(frag *gitdiff.TextFragment, spans [][]span) {
+	for i := 0; i < len(frag.Lines); {
+		if frag.Lines[i].Op != gitdiff.OpDelete {
+			i++
+			continue
+		}
+		delStart := i
+		for i < len(frag.Lines) && frag.Lines[i].Op == gitdiff.OpDelete {
+			i++
+		}
+		addStart := i
+		for i < len(frag.Lines) && frag.Lines[i].Op == gitdiff.OpAdd {
+			i++
+		}
+		for k := 0; delStart+k < addStart && addStart+k < i; k++ {
+			del, add := delStart+k, addStart+k
+			oldRanges, newRanges := wordChanges(lineText(frag.Lines[del]), lineText(frag.Lines[add]))
+			spans[del] = emphasize(spans[del], oldRanges)
+			spans[add] = emphasize(spans[add], newRanges)
+		}
+	}
+}
+
+var wordRegexp = regexp.MustCompile(`\w+|\s+|[^\w\s]`)
+
+// maxWordDiff bounds the size of the table of the longest common subsequence of words
+const maxWordDiff = 200 * 200
+
+// wordChanges returns the byte ranges of the words of each line missing from the longest common subsequence of
+// their words. Nothing is returned for lines without any word in common, emphasizing all of them wouldn't help.
+func wordChanges(old string, new string) ([][2]int, [][2]int) {
+	oldWords, newWords := wordRegexp.FindAllStringIndex(old, -1), wordRegexp.FindAllStringIndex(new, -1)
+	n, m := len(oldWords), len(newWords)
+	if n == 0 || m == 0 || n*m > maxWordDiff {
+		return nil, nil
+	}
+	word := func(s string, w []int) string {
+		return s[w[0]:w[1]]
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of oldWords[i:] and newWords[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if word(old, oldWords[i]) == word(new, newWords[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	oldKept, newKept := make([]bool, n), make([]bool, m)
+	common := false
+	for i, j := 0, 0; i < n && j < m; {
+		if word(old, oldWords[i]) == word(new, newWords[j]) {
+			oldKept[i], newKept[j] = true, true
+			common = common || strings.TrimSpace(word(old, oldWords[i])) != ""
+			i++
+			j++
+		} else if lcs[i+1][j] >= lcs[i][j+1] {
+			i++
+		} else {
+			j++
+		}
+	}
+	if !common {
+		return nil, nil
+	}
+	return changedRanges(oldWords, oldKept), changedRanges(newWords, newKept)
+}
+
+// changedRanges merges the consecutive words that aren't kept
+func changedRanges(words [][]int, kept []bool) [][2]int {
+	ranges := make([][2]int, 0)
+	for i, w := range words {
+		if kept[i] {
+			continue
+		} else if len(ranges) > 0 && ranges[len(ranges)-1][1] == w[0] {
+			ranges[len(ranges)-1][1] = w[1]
+		} else {
+			ranges = append(ranges, [2]int{w[0], w[1]})
+		}
+	}
+	return ranges
+}
+
+// emphasize splits the spans on the bounds of the byte ranges and emphasizes the pieces inside them
+func emphasize(spans []span, ranges [][2]int) []span {
+	return markRanges(spans, ranges, func(s *span) {
+		s.emphasized = true
+	})
+}
+
+// markRanges splits the spans on the bounds of the byte ranges and marks the pieces inside them
+func markRanges(spans []span, ranges [][2]int, mark func(*span)) []span {
+	if len(ranges) == 0 {
+		return spans
+	}
+	inRange := func(pos int) (bool, int) {
+		for _, r := range ranges {
+			if pos < r[0] {
+				return false, r[0]
+			} else if pos < r[1] {
+				return true, r[1]
+			}
+		}
+		return false, -1
+	}
+
+	result := make([]span, 0, len(spans)+2*len(ranges))
+	pos := 0
+	for _, s := range spans {
+		for text := s.text; text != ""; {
+			inside, end := inRange(pos)
+			size := len(text)
+			if end != -1 && end-pos < size {
+				size = end - pos
+			}
+			piece := s
+			piece.text = text[:size]
+			if inside {
+				mark(&piece)
+			}
+			result = append(result, piece)
+			text = text[size:]
+			pos += size
+		}
+	}
+	return result
+}
+
+// diffLineStyle is the style of a kind of diff line, emphasis being the background of its changed words
+type diffLineStyle struct {
+	base     lipgloss.Style
+	emphasis lipgloss.Color
+}
+
+// render prints the prefix then the spans shifted by xOffset runes, tabs expanded, to fill exactly width runes
+func (st diffLineStyle) render(prefix string, spans []span, xOffset int, width int) string {
+	var b strings.Builder
+	remaining := width
+	emit := func(text string, style lipgloss.Style) {
+		runes := []rune(text)
+		if len(runes) > remaining {
+			runes = runes[:remaining]
+		}
+		if len(runes) > 0 {
+			b.WriteString(style.Render(string(runes)))
+			remaining -= len(runes)
+		}
+	}
+
+	emit(prefix, st.base)
+	skip := xOffset
+	for _, s := range spans {
+		runes := []rune(strings.ReplaceAll(s.text, "\t", "    "))
+		if skip >= len(runes) {
+			skip -= len(runes)
+			continue
+		}
+		runes, skip = runes[skip:], 0
+
+		style := st.base.Copy()
+		if s.entry.Colour.IsSet() {
+			style = style.Foreground(lipgloss.Color(s.entry.Colour.String()))
+		}
+		if s.entry.Bold == chroma.Yes {
+			style = style.Bold(true)
+		}
+		if s.entry.Italic == chroma.Yes {
+			style = style.Italic(true)
+		}
+		if s.emphasized {
+			style = style.Background(st.emphasis)
+		}
+		if s.matched {
+			style = style.Background(matchColor).Foreground(lipgloss.Color("#000000"))
+		}
+		emit(string(runes), style)
+	}
+	if remaining > 0 {
+		b.WriteString(st.base.Render(strings.Repeat(" ", remaining)))
+	}
+	return b.String()
+}
+
+// newDiffStyles are the styles of the added, the deleted and the context lines
+func newDiffStyles() map[gitdiff.LineOp]diffLineStyle {
+	return map[gitdiff.LineOp]diffLineStyle{
+		gitdiff.OpAdd: {lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#ffffff")).
+			Background(lipgloss.Color("#005E00e0")), lipgloss.Color("#00a000")},
+		gitdiff.OpDelete: {lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#ffffff")).
+			Background(lipgloss.Color("#5e0000")), lipgloss.Color("#b00000")},
+		gitdiff.OpContext: {lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#999999")).
+			Background(lipgloss.Color("#000000")), lipgloss.Color("#000000")},
+	}
+}
@@ -2,18 +2,53 @@ package ui
 
 import (
 	"fmt"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"strings"
+	"time"
 )
 
+// fileList shows the changed files as a tree of directories, or the files matching the fuzzy filter while it is typed
 type fileList struct {
 	pullRequestData *pullRequestData
 	w               int
 	h               int
 	active          bool
-	selectedLine    int
-	firstLine       int
+	// selectedLine is the selected row of the tree, or of the matches of the filter
+	selectedLine int
+	firstLine    int
+	// currentFile is the file shown in the content
+	currentFile int
+	tree        *fileNode
+	collapsed   map[string]bool
+	filter      fileFilter
+}
+
+// fileFilter is the fuzzy filter of the files, typed after /
+type fileFilter struct {
+	input  textinput.Model
+	active bool
+	// matches are the indexes of the matching files, the best first
+	matches []int
+}
+
+func newFileList(data *pullRequestData) fileList {
+	return fileList{
+		pullRequestData: data,
+		tree:            buildFileTree(data.files),
+		collapsed:       make(map[string]bool),
+	}
+}
+
+// setData shows the files of the reloaded pull request, keeping the collapsed directories
+func (f *fileList) setData(data *pullRequestData) {
+	f.pullRequestData = data
+	f.tree = buildFileTree(data.files)
+	f.filter = fileFilter{}
+	if f.selectedLine >= len(f.rows()) {
+		f.selectedLine, f.firstLine = 0, 0
+	}
 }
 
 func (f fileList) Init() tea.Cmd {
@@ -31,6 +66,126 @@ func fileSelected(file int, move bool) tea.Cmd {
 	}
 }
 
+// rows are the lines of the list, the matches of the filter being flat
+func (f *fileList) rows() []fileRow {
+	if f.filter.active {
+		rows := make([]fileRow, len(f.filter.matches))
+		for i, file := range f.filter.matches {
+			path := getFileName(f.pullRequestData.files[file])
+			added, deleted := fileStats(f.pullRequestData.files[file])
+			rows[i] = fileRow{&fileNode{name: path, path: path, file: file, added: added, deleted: deleted}, 0, -1}
+		}
+		return rows
+	}
+	return visibleRows(f.tree, f.collapsed)
+}
+
+// listHeight is the number of rows shown, the filter taking the first line
+func (f *fileList) listHeight() int {
+	if f.filter.active {
+		return f.h - 1
+	}
+	return f.h
+}
+
+func (f *fileList) scrollToSelection() {
+	f.firstLine = min1(-min1(-(f.selectedLine-(f.listHeight()-1)), -f.firstLine), f.selectedLine)
+	if f.firstLine < 0 {
+		f.firstLine = 0
+	}
+}
+
+// selectRow selects the row, moving the content to its file
+func (f *fileList) selectRow(row int, rows []fileRow) tea.Cmd {
+	if row < 0 || row >= len(rows) {
+		return nil
+	}
+	f.selectedLine = row
+	f.scrollToSelection()
+	if node := rows[row].node; !node.isDir() && !f.filter.active {
+		return fileSelected(node.file, true)
+	}
+	return nil
+}
+
+// selectFile selects the row of the file, or of the collapsed directory hiding it
+func (f *fileList) selectFile(file int) {
+	rows := f.rows()
+	for i, row := range rows {
+		if row.node.file == file {
+			f.selectedLine = i
+			f.scrollToSelection()
+			return
+		}
+	}
+	if dirs, ok := f.tree.ancestors(file); ok {
+		for _, dir := range dirs {
+			if f.collapsed[dir.path] {
+				for i, row := range rows {
+					if row.node == dir {
+						f.selectedLine = i
+						f.scrollToSelection()
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// toggleViewed marks the files of the selected row as viewed, or not viewed when they all are
+func (f *fileList) toggleViewed(rows []fileRow) tea.Cmd {
+	if f.selectedLine >= len(rows) {
+		return nil
+	}
+	files := rows[f.selectedLine].node.files()
+	if err := f.pullRequestData.setViewed(files, !f.pullRequestData.isViewed(files)); err != nil {
+		return showErrCmd(err)
+	}
+	viewed := 0
+	for i := range f.pullRequestData.files {
+		if f.pullRequestData.isViewed([]int{i}) {
+			viewed++
+		}
+	}
+	return showStatusCmd(normalMode, fmt.Sprintf("%d/%d files viewed", viewed, len(f.pullRequestData.files)), 3*time.Second)
+}
+
+// updateFilter handles the keys typed in the filter, enter moving to the selected match
+func (f fileList) updateFilter(msg tea.KeyMsg) (fileList, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		f.filter = fileFilter{}
+		f.selectFile(f.currentFile)
+		return f, nil
+	case "enter":
+		if f.selectedLine >= len(f.filter.matches) {
+			return f, nil
+		}
+		file := f.filter.matches[f.selectedLine]
+		f.filter = fileFilter{}
+		if dirs, ok := f.tree.ancestors(file); ok {
+			for _, dir := range dirs {
+				delete(f.collapsed, dir.path)
+			}
+		}
+		f.selectFile(file)
+		return f, fileSelected(file, true)
+	case "down", "ctrl+n":
+		f.selectRow(f.selectedLine+1, f.rows())
+		return f, nil
+	case "up", "ctrl+p":
+		f.selectRow(f.selectedLine-1, f.rows())
+		return f, nil
+	}
+
+	var cmd tea.Cmd
+	f.filter.input, cmd = f.filter.input.Update(msg)
+	f.filter.matches = fuzzyMatches(f.filter.input.Value(), f.pullRequestData.files)
+	f.selectedLine, f.firstLine = 0, 0
+	return f, cmd
+}
+
 func (f fileList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd = nil
 	switch m := msg.(type) {
@@ -38,28 +193,49 @@ func (f fileList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		f.w = m.Width
 		f.h = m.Height
 	case tea.KeyMsg:
+		if f.filter.active {
+			return f.updateFilter(m)
+		}
+		rows := f.rows()
 		switch m.String() {
 		case "down":
-			if f.selectedLine < len(f.pullRequestData.files)-1 {
-				f.selectedLine += 1
-				cmd = fileSelected(f.selectedLine, true)
-			}
-			for (f.selectedLine - f.firstLine) >= f.h {
-				f.firstLine++
-			}
+			cmd = f.selectRow(f.selectedLine+1, rows)
 		case "up":
-			if f.selectedLine > 0 {
-				f.selectedLine -= 1
-				cmd = fileSelected(f.selectedLine, true)
+			cmd = f.selectRow(f.selectedLine-1, rows)
+		case "enter":
+			if f.selectedLine >= len(rows) {
+				break
+			} else if node := rows[f.selectedLine].node; node.isDir() {
+				f.collapsed[node.path] = !f.collapsed[node.path]
+			} else {
+				cmd = fileSelected(node.file, true)
 			}
-			for f.selectedLine < f.firstLine {
-				f.firstLine--
+		case "left":
+			// Collapse the directory, or go up to the directory of the file
+			if f.selectedLine >= len(rows) {
+				break
+			} else if row := rows[f.selectedLine]; row.node.isDir() && !f.collapsed[row.node.path] {
+				f.collapsed[row.node.path] = true
+			} else {
+				cmd = f.selectRow(row.parent, rows)
 			}
+		case "right":
+			if f.selectedLine < len(rows) && rows[f.selectedLine].node.isDir() {
+				delete(f.collapsed, rows[f.selectedLine].node.path)
+			}
+		case " ":
+			cmd = f.toggleViewed(rows)
+		case "/":
+			input := newSearchInput("/")
+			input.Width = f.w - 2
+			f.filter = fileFilter{input: input, active: true,
+				matches: fuzzyMatches("", f.pullRequestData.files)}
+			f.selectedLine, f.firstLine = 0, 0
 		}
 	case fileSelectedMsg:
-		if f.selectedLine != m.ordinal {
-			f.selectedLine = m.ordinal
-			f.firstLine = min1(-min1(-(m.ordinal-(f.h-1)), -f.firstLine), f.selectedLine)
+		f.currentFile = m.ordinal
+		if !f.filter.active {
+			f.selectFile(m.ordinal)
 		}
 	case focusChangedMsg:
 		f.active = m.newFocus == FILEVIEW_ADDRESS
@@ -68,33 +244,74 @@ func (f fileList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (f fileList) View() string {
-	s := lipgloss.NewStyle().Width(f.w).Inline(true)
+	s := lipgloss.NewStyle().Inline(true)
 	var sel lipgloss.Style
 	if f.active {
-		sel = lipgloss.NewStyle().Width(f.w).Background(lipgloss.Color("#ffffff")).
+		sel = lipgloss.NewStyle().Background(lipgloss.Color("#ffffff")).
 			Foreground(lipgloss.Color("#000000")).Inline(true)
 	} else {
-		sel = lipgloss.NewStyle().Width(f.w).Background(lipgloss.Color("#e0e0e0")).
+		sel = lipgloss.NewStyle().Background(lipgloss.Color("#e0e0e0")).
 			Foreground(lipgloss.Color("#000000")).Inline(true)
 	}
 	l := make([]string, 0)
-	for i, file := range f.pullRequestData.files[f.firstLine:] {
-		var ss lipgloss.Style
-		if i+f.firstLine == f.selectedLine {
+	if f.filter.active {
+		l = append(l, lipgloss.NewStyle().Width(f.w).Inline(true).Render(f.filter.input.View()))
+	}
+	rows := f.rows()
+	for i := f.firstLine; i < len(rows) && len(l) < f.h; i++ {
+		ss := s
+		if i == f.selectedLine {
 			ss = sel
-		} else {
-			ss = s
-		}
-		fn := getFileName(file)
-		fn = crop(fn, f.w)
-		l = append(l, ss.Render(fillLine(fn, f.w)))
-		if len(l) >= f.h {
-			break
 		}
+		l = append(l, f.renderRow(rows[i], ss))
 	}
 	return strings.Join(l, "\n")
 }
 
+// renderRow prints the row indented by its depth, the directories with their state, the files with their viewed mark,
+// the added and deleted lines on the right
+func (f fileList) renderRow(row fileRow, style lipgloss.Style) string {
+	node := row.node
+	mark, name := "  ", node.name
+	if node.isDir() {
+		mark = "▾ "
+		if f.collapsed[node.path] {
+			mark = "▸ "
+		}
+		name += "/"
+	}
+	if f.pullRequestData.isViewed(node.files()) {
+		// The directories keep their state, they are only dimmed
+		if !node.isDir() {
+			mark = "✓ "
+		}
+		style = style.Copy().Faint(true)
+	}
+	prefix := strings.Repeat("  ", row.depth) + mark
+
+	added, deleted := fmt.Sprintf("+%d", node.added), fmt.Sprintf("-%d", node.deleted)
+	stats := len(added) + len(deleted) + 2
+	width := f.w - len([]rune(prefix)) - stats
+	if width < 4 {
+		// No room for the stats
+		width, stats = f.w-len([]rune(prefix)), 0
+	}
+	if width <= 0 {
+		return style.Render(fillLine("", f.w))
+	} else if len(name) > width && width >= 4 {
+		name = crop(name, width)
+	}
+
+	line := style.Render(prefix + fillLine(name, width))
+	if stats > 0 {
+		line += style.Render(" ") +
+			style.Copy().Foreground(lipgloss.Color("#00c000")).Render(added) +
+			style.Render(" ") +
+			style.Copy().Foreground(lipgloss.Color("#e00000")).Render(deleted)
+	}
+	return line
+}
+
 func crop(fn string, w int) string {
 	if len(fn) > w {
 		x := strings.Index(fn, "/") + 1
@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
+	"github.com/pterm/pterm"
+	"github.com/vballestra/sv/sv"
+	"sort"
+	"strings"
+)
+
+// fileNode is a directory or a changed file of the tree of the file list, the directories with a single directory
+// inside are shown together as a/b/c
+type fileNode struct {
+	name string
+	// path is the path of the directory or the file in the repository
+	path string
+	// file is the index of the file in the diff, -1 for the directories
+	file     int
+	children []*fileNode
+	added    int64
+	deleted  int64
+}
+
+func (n *fileNode) isDir() bool {
+	return n.file < 0
+}
+
+// fileStats are the lines added and deleted by the file
+func fileStats(file *gitdiff.File) (int64, int64) {
+	added, deleted := int64(0), int64(0)
+	for _, frag := range file.TextFragments {
+		added += frag.LinesAdded
+		deleted += frag.LinesDeleted
+	}
+	return added, deleted
+}
+
+// buildFileTree puts the files of the diff in a tree of directories, in the order of the diff
+func buildFileTree(files []*gitdiff.File) *fileNode {
+	root := &fileNode{file: -1}
+	dirs := map[string]*fileNode{"": root}
+	for i, file := range files {
+		path := getFileName(file)
+		parent := root
+		parts := strings.Split(path, "/")
+		for k := range parts[:len(parts)-1] {
+			dirPath := strings.Join(parts[:k+1], "/")
+			dir, ok := dirs[dirPath]
+			if !ok {
+				dir = &fileNode{name: parts[k], path: dirPath, file: -1}
+				dirs[dirPath] = dir
+				parent.children = append(parent.children, dir)
+			}
+			parent = dir
+		}
+		added, deleted := fileStats(file)
+		parent.children = append(parent.children, &fileNode{name: parts[len(parts)-1], path: path, file: i,
+			added: added, deleted: deleted})
+	}
+	sumStats(root)
+	for _, child := range root.children {
+		compactDirs(child)
+	}
+	return root
+}
+
+func sumStats(n *fileNode) {
+	for _, child := range n.children {
+		if child.isDir() {
+			sumStats(child)
+		}
+		n.added += child.added
+		n.deleted += child.deleted
+	}
+}
+
+// compactDirs merges the directories having a single directory inside
+func compactDirs(n *fileNode) {
+	for n.isDir() && len(n.children) == 1 && n.children[0].isDir() {
+		child := n.children[0]
+		n.name += "/" + child.name
+		n.path = child.path
+		n.children = child.children
+	}
+	for _, child := range n.children {
+		compactDirs(child)
+	}
+}
+
+// files are the indexes of the files under the node
+func (n *fileNode) files() []int {
+	if !n.isDir() {
+		return []int{n.file}
+	}
+	files := make([]int, 0)
+	for _, child := range n.children {
+		files = append(files, child.files()...)
+	}
+	return files
+}
+
+// fileRow is a line of the file list
+type fileRow struct {
+	node  *fileNode
+	depth int
+	// parent is the row of the directory of the node, -1 at the top
+	parent int
+}
+
+// visibleRows are the rows of the tree, the content of the collapsed directories being hidden
+func visibleRows(root *fileNode, collapsed map[string]bool) []fileRow {
+	rows := make([]fileRow, 0)
+	var walk func(n *fileNode, depth int, parent int)
+	walk = func(n *fileNode, depth int, parent int) {
+		for _, child := range n.children {
+			rows = append(rows, fileRow{child, depth, parent})
+			if child.isDir() && !collapsed[child.path] {
+				walk(child, depth+1, len(rows)-1)
+			}
+		}
+	}
+	walk(root, 0, -1)
+	return rows
+}
+
+// ancestors are the directories containing the file, from the top
+func (n *fileNode) ancestors(file int) ([]*fileNode, bool) {
+	for _, child := range n.children {
+		if child.file == file {
+			return []*fileNode{}, true
+		} else if child.isDir() {
+			if dirs, ok := child.ancestors(file); ok {
+				return append([]*fileNode{child}, dirs...), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// fuzzyScore tells whether the letters of the pattern appear in order in the path, ignoring the case, and scores the
+// match. The consecutive letters, the letters starting a word and the letters of the file name score more.
+func fuzzyScore(pattern string, path string) (int, bool) {
+	p, s := []rune(strings.ToLower(pattern)), []rune(strings.ToLower(path))
+	baseName := len([]rune(path[:strings.LastIndex(path, "/")+1]))
+
+	score, pi, last := 0, 0, -2
+	for i := 0; i < len(s) && pi < len(p); i++ {
+		if s[i] != p[pi] {
+			continue
+		}
+		score++
+		if last == i-1 {
+			score += 5
+		}
+		if i == 0 || strings.ContainsRune("/._- ", s[i-1]) {
+			score += 3
+		}
+		if i >= baseName {
+			score += 2
+		}
+		last = i
+		pi++
+	}
+	return score, pi == len(p)
+}
+
+// fuzzyMatches are the indexes of the files matching the pattern, the best first then the shortest paths
+func fuzzyMatches(pattern string, files []*gitdiff.File) []int {
+	type match struct {
+		file  int
+		score int
+		size  int
+	}
+	matches := make([]match, 0, len(files))
+	for i, file := range files {
+		if score, ok := fuzzyScore(pattern, getFileName(file)); ok {
+			matches = append(matches, match{i, score, len(getFileName(file))})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].size < matches[j].size
+	})
+
+	result := make([]int, len(matches))
+	for i, m := range matches {
+		result[i] = m.file
+	}
+	return result
+}
+
+// loadViewedFiles reads the files of the pull request already viewed, none when they can't be read
+func loadViewedFiles(pr sv.PullRequest, files []*gitdiff.File) (sv.ViewedFilesTracker, map[string]bool) {
+	if tracker, err := sv.ViewedFiles(pr, files); err != nil {
+		pterm.Debug.Println("Couldn't track the viewed files ", err)
+		return nil, make(map[string]bool)
+	} else if viewed, err := tracker.GetViewedFiles(); err != nil {
+		pterm.Debug.Println("Couldn't read the viewed files ", err)
+		return tracker, make(map[string]bool)
+	} else {
+		return tracker, viewed
+	}
+}
+
+// setViewed marks the files as viewed or not, stopping at the first one the tracker fails to update
+func (d *pullRequestData) setViewed(files []int, viewed bool) error {
+	if d.viewedTracker == nil {
+		return sv.ErrNotSupported
+	}
+	for _, i := range files {
+		path := getFileName(d.files[i])
+		if d.viewed[path] == viewed {
+			continue
+		} else if err := d.viewedTracker.SetFileViewed(path, viewed); err != nil {
+			return err
+		} else if viewed {
+			d.viewed[path] = true
+		} else {
+			delete(d.viewed, path)
+		}
+	}
+	return nil
+}
+
+// isViewed tells whether all the files have been viewed
+func (d *pullRequestData) isViewed(files []int) bool {
+	for _, i := range files {
+		if !d.viewed[getFileName(d.files[i])] {
+			return false
+		}
+	}
+	return len(files) > 0
+}
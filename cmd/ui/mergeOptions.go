@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+	"github.com/erikgeiser/promptkit/confirmation"
+	"github.com/erikgeiser/promptkit/selection"
+	"github.com/pterm/pterm"
+	"github.com/vballestra/sv/cmd/ui/simpleEditor"
+	"github.com/vballestra/sv/sv"
+)
+
+const (
+	defaultMergeMethod = "default"
+	mergeNow           = "now"
+	mergeOnceChecked   = "once the checks pass"
+)
+
+// EditText opens the editor on a text, the builtin one unless the external editor is enabled
+func EditText(title string, initialText string) (string, error) {
+	return launchEditor(initialText,
+		simpleEditor.WithWidth{Width: pterm.GetTerminalWidth()},
+		simpleEditor.WithTitle{Title: title})
+}
+
+// promptMergeOptions asks how to merge a pull request, ok is false when the user gave up
+func promptMergeOptions(pr sv.PullRequest) (opts sv.MergeOptions, ok bool) {
+	methods := append([]string{defaultMergeMethod}, sv.MergeMethods...)
+	if method, err := selection.New("Merge method", selection.Choices(methods)).RunPrompt(); err != nil {
+		return opts, false
+	} else if method.String != defaultMergeMethod {
+		opts.Method = method.String
+	}
+
+	if when, err := selection.New("Merge", selection.Choices([]string{mergeNow, mergeOnceChecked})).RunPrompt(); err != nil {
+		return opts, false
+	} else {
+		opts.Auto = when.String == mergeOnceChecked
+	}
+
+	if message, err := launchEditor(pr.GetTitle(),
+		simpleEditor.WithWidth{Width: pterm.GetTerminalWidth()},
+		simpleEditor.WithTitle{Title: "Commit message"},
+		simpleEditor.WithPlaceholder{Value: "Title on the first line, empty for the default message"}); err != nil {
+		return opts, false
+	} else {
+		opts.SetMessage(message)
+	}
+
+	if yes, err := confirmation.New("Delete the branch once merged ?", confirmation.No).RunPrompt(); err != nil {
+		return opts, false
+	} else {
+		opts.DeleteBranch = yes
+	}
+
+	yes, err := confirmation.New(fmt.Sprintf("Want to merge PR %v ?", pr.GetId()), confirmation.Yes).RunPrompt()
+	return opts, yes && err == nil
+}
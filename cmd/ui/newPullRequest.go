@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"github.com/antihax/optional"
+	"github.com/charmbracelet/glamour"
+	"github.com/erikgeiser/promptkit"
+	"github.com/erikgeiser/promptkit/confirmation"
+	"github.com/erikgeiser/promptkit/selection"
+	"github.com/erikgeiser/promptkit/textinput"
+	"github.com/pterm/pterm"
+	"github.com/vballestra/sv/cmd/ui/simpleEditor"
+	"github.com/vballestra/sv/sv"
+	"strings"
+)
+
+const (
+	donePicking  = "(done)"
+	otherPicking = "(other)"
+)
+
+// PromptNewPullRequest asks for the base branch, the title and the description, the reviewers, the labels and the
+// draft status of a new pull request, starting from args, then shows a preview before submitting it.
+// branches are the candidate bases, promptkit.ErrAborted is returned when the user gave up.
+func PromptNewPullRequest(repo sv.Sv, branches []string, args sv.CreatePullRequestArgs) (sv.CreatePullRequestArgs, error) {
+	head := args.HeadBranch.Default("")
+	if head == "" {
+		if current, err := repo.GetCurrentBranch(); err != nil {
+			return args, err
+		} else {
+			head = current
+			args.HeadBranch = optional.NewString(head)
+		}
+	}
+
+	base := args.BaseBranch.Default("")
+	if base == "" {
+		if defaultBranch, err := repo.GetDefaultBranch(); err != nil {
+			pterm.Debug.Printfln("cannot get the default branch: %v", err)
+		} else {
+			base = defaultBranch
+		}
+	}
+	bases := make([]string, 0, len(branches)+1)
+	if base != "" {
+		bases = append(bases, base)
+	}
+	for _, b := range branches {
+		if b != base && b != head {
+			bases = append(bases, b)
+		}
+	}
+	if len(bases) == 0 {
+		return args, errors.New("no base branch to choose from")
+	}
+	baseSelection := selection.New(fmt.Sprintf("Base of %s", head), selection.Choices(bases))
+	baseSelection.PageSize = 10
+	if choice, err := baseSelection.RunPrompt(); err != nil {
+		return args, err
+	} else {
+		base = choice.String
+		args.BaseBranch = optional.NewString(base)
+	}
+
+	title, description, err := repo.ResolveTitleAndDescription(args.Remote, args.Title, args.Description, head, base)
+	if err != nil {
+		pterm.Debug.Printfln("cannot infer the title and the description: %v", err)
+	}
+	if text, err := launchEditor(strings.TrimSpace(title+"\n\n"+description),
+		simpleEditor.WithWidth{Width: pterm.GetTerminalWidth()},
+		simpleEditor.WithTitle{Title: "Title and description"},
+		simpleEditor.WithPlaceholder{Value: "Title on the first line, then the description"}); err != nil {
+		return args, err
+	} else if title, description, _ := strings.Cut(strings.TrimSpace(text), "\n"); strings.TrimSpace(title) == "" {
+		return args, errors.New("the title can't be empty")
+	} else {
+		args.Title = optional.NewString(strings.TrimSpace(title))
+		args.Description = optional.NewString(strings.TrimSpace(description))
+	}
+
+	collaborators, err := repo.ListCollaborators()
+	if err != nil {
+		pterm.Warning.Printfln("Cannot list the collaborators: %v", err)
+	}
+	if args.Reviewers, err = pickMany("Reviewers", collaborators, args.Reviewers); err != nil {
+		return args, err
+	}
+
+	labels, err := repo.ListLabels()
+	if err != nil {
+		pterm.Warning.Printfln("Cannot list the labels: %v", err)
+	}
+	if args.Labels, err = pickMany("Labels", labels, args.Labels); err != nil {
+		return args, err
+	}
+
+	draft := confirmation.No
+	if args.Draft {
+		draft = confirmation.Yes
+	}
+	if yes, err := confirmation.New("Open as a draft ?", draft).RunPrompt(); err != nil {
+		return args, err
+	} else {
+		args.Draft = yes
+	}
+
+	printPullRequestPreview(args)
+	if yes, err := confirmation.New("Create the PR ?", confirmation.Yes).RunPrompt(); err != nil {
+		return args, err
+	} else if !yes {
+		return args, promptkit.ErrAborted
+	}
+	return args, nil
+}
+
+// pickMany lets the user pick choices one by one, the filter of the selection completing them, or type others
+func pickMany(prompt string, choices []string, picked []string) ([]string, error) {
+	picked = append([]string{}, picked...)
+	for {
+		isPicked := make(map[string]bool)
+		for _, p := range picked {
+			isPicked[p] = true
+		}
+		remaining := []string{donePicking, otherPicking}
+		for _, c := range choices {
+			if !isPicked[c] {
+				remaining = append(remaining, c)
+			}
+		}
+
+		s := selection.New(fmt.Sprintf("%s: %s", prompt, strings.Join(picked, ", ")), selection.Choices(remaining))
+		s.PageSize = 10
+		choice, err := s.RunPrompt()
+		if err != nil {
+			return picked, err
+		}
+
+		switch choice.String {
+		case donePicking:
+			return picked, nil
+		case otherPicking:
+			input := textinput.New(prompt)
+			input.Placeholder = "comma separated"
+			input.Validate = nil
+			text, err := input.RunPrompt()
+			if err != nil {
+				return picked, err
+			}
+			for _, item := range strings.Split(text, ",") {
+				if item = strings.TrimSpace(item); item != "" && !isPicked[item] {
+					isPicked[item] = true
+					picked = append(picked, item)
+				}
+			}
+		default:
+			picked = append(picked, choice.String)
+		}
+	}
+}
+
+// printPullRequestPreview shows the pull request as it will be created
+func printPullRequestPreview(args sv.CreatePullRequestArgs) {
+	pterm.DefaultSection.Println(args.Title.Value())
+
+	draft := "no"
+	if args.Draft {
+		draft = "yes"
+	}
+	_ = pterm.DefaultTable.WithData(pterm.TableData{
+		{"Branches", fmt.Sprintf("%s ← %s", args.BaseBranch.Value(), args.HeadBranch.Value())},
+		{"Reviewers", strings.Join(args.Reviewers, ", ")},
+		{"Labels", strings.Join(args.Labels, ", ")},
+		{"Draft", draft},
+	}).Render()
+
+	if description := args.Description.Default(""); description != "" {
+		if r, err := glamour.NewTermRenderer(glamour.WithAutoStyle(), glamour.WithWordWrap(pterm.GetTerminalWidth())); err != nil {
+			pterm.Println(description)
+		} else if out, err := r.Render(description); err != nil {
+			pterm.Println(description)
+		} else {
+			pterm.Println(out)
+		}
+	}
+}
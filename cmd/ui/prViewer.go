@@ -39,6 +39,16 @@ type pullRequestData struct {
 	files         []*gitdiff.File
 	lastCommitId  string
 	pendingReview sv.Review
+	// highlights are the highlighted lines of the fragments of the diff
+	highlights map[*gitdiff.TextFragment][][]span
+	// fileContents are the lines of the files in the head, read to expand the context of the fragments
+	fileContents map[string][]string
+	contexts     map[*gitdiff.TextFragment]fragmentContext
+	wholeFiles   map[*gitdiff.File]bool
+	expanded     map[*gitdiff.File][]expandedFragment
+	// viewed are the files marked as viewed, kept by viewedTracker
+	viewedTracker sv.ViewedFilesTracker
+	viewed        map[string]bool
 }
 
 func (d *pullRequestData) addComment(path string, old int64, new int64, isNew bool, comment sv.Comment) {
@@ -75,6 +85,7 @@ func loadPullRequestData(pr sv.PullRequest) (*pullRequestData, error) {
 	} else if files, err := pr.GetDiff(); err != nil {
 		return nil, err
 	} else {
+		tracker, viewed := loadViewedFiles(pr, files)
 		return &pullRequestData{pr,
 			checks,
 			reviews,
@@ -82,7 +93,14 @@ func loadPullRequestData(pr sv.PullRequest) (*pullRequestData, error) {
 			commentMap,
 			files,
 			pr.GetLastCommitId(),
-			pending}, nil
+			pending,
+			make(map[*gitdiff.TextFragment][][]span),
+			make(map[string][]string),
+			make(map[*gitdiff.TextFragment]fragmentContext),
+			make(map[*gitdiff.File]bool),
+			make(map[*gitdiff.File][]expandedFragment),
+			tracker,
+			viewed}, nil
 	}
 }
 
@@ -103,6 +121,7 @@ type PullRequestView struct {
 
 	dirty   bool
 	xOffset int
+	search  search
 }
 
 var focusOrder = [...]viewAddress{CONTENT_ADDRESS, FILEVIEW_ADDRESS}
@@ -137,7 +156,7 @@ func NewView(pr sv.PullRequest) (*PullRequestView, error) {
 
 		mode := newLayoutMode()
 
-		fileView := fileList{data, 0, 0, false, 0, 0}
+		fileView := newFileList(data)
 
 		if layout, err := initWidgetsLayout(&box, header, content, fileView, mode); err != nil {
 			pterm.Fatal.Print(err)
@@ -300,6 +319,19 @@ func (p *PullRequestView) withFileListViewPtr(action func(*fileList) error) erro
 	return withViewPtr(p, FILEVIEW_ADDRESS, action)
 }
 
+// updateFileList sends the message to the file list
+func (p *PullRequestView) updateFileList(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	if err := p.withFileListView(func(view fileList) (fileList, error) {
+		newView, c := view.Update(msg)
+		cmd = c
+		return newView.(fileList), nil
+	}); err != nil {
+		return showErrCmd(err)
+	}
+	return cmd
+}
+
 type viewAddress string
 
 const (
@@ -311,6 +343,8 @@ const (
 
 type layoutMode struct {
 	showFileView bool
+	// split shows the old and the new lines side by side
+	split bool
 }
 
 func newLayoutMode() layoutMode {
@@ -320,9 +354,13 @@ func newLayoutMode() layoutMode {
 }
 
 func (mode layoutMode) withFileView(visible bool) layoutMode {
-	return layoutMode{
-		showFileView: visible,
-	}
+	mode.showFileView = visible
+	return mode
+}
+
+func (mode layoutMode) withSplit(split bool) layoutMode {
+	mode.split = split
+	return mode
 }
 
 func initWidgetsLayout(box *boxer.Boxer, header pullRequestHeader, content contentView, fileView fileList, mode layoutMode) (boxer.Node, error) {
@@ -637,20 +675,64 @@ func bookmarkAt(p *PullRequestView, b bookmarkCategory, line int) (int, interfac
 }
 
 func currentBookmark2(p *PullRequestView, b bookmarkCategory) (int, interface{}) {
+	content, _ := p.getContentView()
+	return bookmarkAround(p, b, content.viewport.YOffset)
+}
+
+// bookmarkAround returns the last bookmark of the category at or before the line
+func bookmarkAround(p *PullRequestView, b bookmarkCategory, line int) (int, interface{}) {
 	bookmarks := p.bookmarks[b]
 	for n, l := range bookmarks {
-		content, _ := p.getContentView()
 		l1 := math.MaxInt
 		if n+1 < len(bookmarks) {
 			l1 = bookmarks[n+1].line
 		}
-		if l.line <= content.viewport.YOffset && content.viewport.YOffset < l1 {
+		if l.line <= line && line < l1 {
 			return n, l.data
 		}
 	}
 	return 0, nil
 }
 
+// cursorLine is the selected line, the top of the content otherwise
+func (p *PullRequestView) cursorLine() int {
+	if content, ok := p.getContentView(); !ok {
+		return 0
+	} else if content.isLineSelected() {
+		return content.selectedLine
+	} else {
+		return content.viewport.YOffset
+	}
+}
+
+// expandCurrentContext adds context above or below the fragment at the cursor, or toggles its whole file
+func (p *PullRequestView) expandCurrentContext(above bool, below bool, wholeFile bool) tea.Cmd {
+	var err error
+	if wholeFile {
+		if _, data := bookmarkAround(p, FILE_CATEGORY, p.cursorLine()); data == nil {
+			return nil
+		} else if file := data.(*gitdiff.File); file.IsBinary || file.IsDelete {
+			return nil
+		} else {
+			err = p.pullRequest.toggleWholeFile(file)
+		}
+	} else if _, data := bookmarkAround(p, HUNK_CATEGORY, p.cursorLine()); data == nil {
+		return nil
+	} else if hunk := data.(hunkBookmark); above {
+		err = p.pullRequest.expandContext(hunk.file, hunk.fragment.first, contextStep, 0)
+	} else if below {
+		err = p.pullRequest.expandContext(hunk.file, hunk.fragment.last, 0, contextStep)
+	}
+	if err != nil {
+		return showErrCmd(err)
+	}
+	_ = p.withContentViewPtr(func(content *contentView) error {
+		content.selectLine(-1)
+		return nil
+	})
+	return renderPrCmd
+}
+
 func getNodeRecur(nodes []boxer.Node, name viewAddress) *boxer.Node {
 	for _, nd := range nodes {
 		if nd.IsLeaf() {
@@ -703,7 +785,7 @@ func (p *PullRequestView) reloadPullRequest() tea.Cmd {
 			return nil
 		})
 		p.withFileListViewPtr(func(list *fileList) error {
-			list.pullRequestData = pr
+			list.setData(pr)
 			return nil
 		})
 		return tea.Batch(tea.ClearScrollArea, renderPrCmd)
@@ -756,10 +838,10 @@ func (p PullRequestView) Update(m tea.Msg) (tea.Model, tea.Cmd) {
 			if yes, err := input.RunPrompt(); yes && err == nil {
 				// Do nothing for now
 				if comment, err := launchEditor("",
-					simpleEditor.WithWidth{pterm.GetTerminalWidth()},
-					simpleEditor.WithTitle{"New Comment"},
-					simpleEditor.WithPlaceholder{"Edit comment"}); err == nil && comment != "" {
-					isNew := msg.code.code.New()
+					simpleEditor.WithWidth{Width: pterm.GetTerminalWidth()},
+					simpleEditor.WithTitle{Title: "New Comment"},
+					simpleEditor.WithPlaceholder{Value: "Edit comment"}); err == nil && comment != "" {
+					isNew := msg.code.isNew
 					fn := getFileName(msg.code.file)
 					lineNum := int(msg.code.new)
 					if !isNew {
@@ -782,9 +864,9 @@ func (p PullRequestView) Update(m tea.Msg) (tea.Model, tea.Cmd) {
 					if yes, err := input.RunPrompt(); yes && err == nil {
 						// Do nothing for now
 						if replyText, err := launchEditor("",
-							simpleEditor.WithWidth{pterm.GetTerminalWidth()},
-							simpleEditor.WithTitle{"Reply to Comment"},
-							simpleEditor.WithPlaceholder{"Edit comment"}); err == nil {
+							simpleEditor.WithWidth{Width: pterm.GetTerminalWidth()},
+							simpleEditor.WithTitle{Title: "Reply to Comment"},
+							simpleEditor.WithPlaceholder{Value: "Edit comment"}); err == nil {
 							if _, err := p.pullRequest.ReplyToComment(comment, replyText); err != nil {
 								return p, showErrCmd(err)
 							} else {
@@ -823,6 +905,22 @@ func (p PullRequestView) Update(m tea.Msg) (tea.Model, tea.Cmd) {
 			return p, renderPrCmd
 		}
 	case tea.KeyMsg:
+		if p.search.typing {
+			return p, p.updateSearch(msg)
+		} else if flv, ok := p.getFileListView(); ok && p.currentFocus() == FILEVIEW_ADDRESS && (flv.filter.active || msg.String() == "/") {
+			// The file list has its own filter
+			return p, p.updateFileList(msg)
+		} else if p.search.pattern != nil && p.currentFocus() == CONTENT_ADDRESS {
+			switch msg.String() {
+			case "n":
+				return p, p.nextMatch(false)
+			case "N":
+				return p, p.nextMatch(true)
+			case "esc":
+				p.search = search{}
+				return p, tea.Batch(renderPrCmd, showStatusCmd(normalMode, "", 0))
+			}
+		}
 
 		switch k := msg.String(); k {
 		case "tab":
@@ -845,9 +943,9 @@ func (p PullRequestView) Update(m tea.Msg) (tea.Model, tea.Cmd) {
 			} else if yes, err := confirmation.New(fmt.Sprintf("Want to request changes for PR %s ?", rev.GetId()), confirmation.Yes).RunPrompt(); !yes || err != nil {
 				return p, nil
 			} else if text, err := launchEditor("",
-				simpleEditor.WithWidth{pterm.GetTerminalWidth()},
-				simpleEditor.WithTitle{"Request changes"},
-				simpleEditor.WithPlaceholder{"Edit review comment"}); err != nil {
+				simpleEditor.WithWidth{Width: pterm.GetTerminalWidth()},
+				simpleEditor.WithTitle{Title: "Request changes"},
+				simpleEditor.WithPlaceholder{Value: "Edit review comment"}); err != nil {
 				return p, nil
 			} else if err := rev.RequestChanges(&text); err != nil {
 				return p, showErrCmd(err)
@@ -877,9 +975,37 @@ func (p PullRequestView) Update(m tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 		case "M":
-			if yes, err := confirmation.New(fmt.Sprintf("Want to merge rev %v ?", p.pullRequest.GetId()), confirmation.Yes).RunPrompt(); !yes || err != nil {
+			if opts, ok := promptMergeOptions(p.pullRequest); !ok {
 				return p, nil
-			} else if err := p.pullRequest.Merge(); err != nil {
+			} else if err := p.pullRequest.Merge(opts); err != nil {
+				return p, showErrCmd(err)
+			} else {
+				return p, p.reloadPullRequest()
+			}
+		case "X":
+			if yes, err := confirmation.New(fmt.Sprintf("Want to close PR %v without merging it ?", p.pullRequest.GetId()), confirmation.No).RunPrompt(); !yes || err != nil {
+				return p, nil
+			} else if err := p.pullRequest.Close(); err != nil {
+				return p, showErrCmd(err)
+			} else {
+				return p, p.reloadPullRequest()
+			}
+		case "O":
+			if yes, err := confirmation.New(fmt.Sprintf("Want to reopen PR %v ?", p.pullRequest.GetId()), confirmation.Yes).RunPrompt(); !yes || err != nil {
+				return p, nil
+			} else if err := p.pullRequest.Reopen(); err != nil {
+				return p, showErrCmd(err)
+			} else {
+				return p, p.reloadPullRequest()
+			}
+		case "D":
+			prompt := fmt.Sprintf("Want to convert PR %v to a draft ?", p.pullRequest.GetId())
+			if p.pullRequest.IsDraft() {
+				prompt = fmt.Sprintf("Want to mark PR %v ready for review ?", p.pullRequest.GetId())
+			}
+			if yes, err := confirmation.New(prompt, confirmation.Yes).RunPrompt(); !yes || err != nil {
+				return p, nil
+			} else if err := p.pullRequest.SetDraft(!p.pullRequest.IsDraft()); err != nil {
 				return p, showErrCmd(err)
 			} else {
 				return p, p.reloadPullRequest()
@@ -888,9 +1014,9 @@ func (p PullRequestView) Update(m tea.Msg) (tea.Model, tea.Cmd) {
 		case "A":
 			if rev := p.pullRequest.pendingReview; rev != nil {
 				if text, err := launchEditor("",
-					simpleEditor.WithWidth{pterm.GetTerminalWidth()},
-					simpleEditor.WithTitle{"Request changes"},
-					simpleEditor.WithPlaceholder{"Edit review comment"}); err != nil {
+					simpleEditor.WithWidth{Width: pterm.GetTerminalWidth()},
+					simpleEditor.WithTitle{Title: "Request changes"},
+					simpleEditor.WithPlaceholder{Value: "Edit review comment"}); err != nil {
 					return p, showErrCmd(err)
 				} else if yes, err := confirmation.New(fmt.Sprintf("Want to approve rev %v ?", rev.GetId()), confirmation.Yes).RunPrompt(); !yes || err != nil {
 					return p, nil
@@ -912,6 +1038,24 @@ func (p PullRequestView) Update(m tea.Msg) (tea.Model, tea.Cmd) {
 			if !p.isVisible(p.currentFocus()) {
 				p.nextFocus()
 			}
+		case "/":
+			cmds = append(cmds, p.startSearch(false))
+		case "?":
+			cmds = append(cmds, p.startSearch(true))
+		case "[":
+			cmds = append(cmds, p.expandCurrentContext(true, false, false))
+		case "]":
+			cmds = append(cmds, p.expandCurrentContext(false, true, false))
+		case "F":
+			cmds = append(cmds, p.expandCurrentContext(false, false, true))
+		case "s":
+			// The split diff only changes the rendering of the content, the selection doesn't survive it
+			p.layoutMode = p.layoutMode.withSplit(!p.layoutMode.split)
+			_ = p.withContentViewPtr(func(content *contentView) error {
+				content.selectLine(-1)
+				return nil
+			})
+			cmds = append(cmds, renderPrCmd)
 
 		default:
 			switch p.currentFocus() {
@@ -924,13 +1068,7 @@ func (p PullRequestView) Update(m tea.Msg) (tea.Model, tea.Cmd) {
 					cmds = append(cmds, showErrCmd(err))
 				}
 			case FILEVIEW_ADDRESS:
-				if err := p.withFileListView(func(view fileList) (fileList, error) {
-					newView, cmd := view.Update(msg)
-					cmds = append(cmds, cmd)
-					return newView.(fileList), nil
-				}); err != nil {
-					cmds = append(cmds, showErrCmd(err))
-				}
+				cmds = append(cmds, p.updateFileList(msg))
 			}
 		}
 
@@ -974,7 +1112,7 @@ func (p PullRequestView) Update(m tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Eventually send a file selected event
 	if flv, ok := p.getFileListView(); ok {
-		if ord, val := currentBookmark2(&p, FILE_CATEGORY); val != nil && ord != flv.selectedLine {
+		if ord, val := currentBookmark2(&p, FILE_CATEGORY); val != nil && ord != flv.currentFile {
 			cmds = append(cmds, fileSelected(ord, false))
 		}
 	}
@@ -982,11 +1120,12 @@ func (p PullRequestView) Update(m tea.Msg) (tea.Model, tea.Cmd) {
 	return p, tea.Batch(cmds...)
 }
 
-var useEditor = false
+// Editor is the command editing the texts, the builtin editor is used when empty
+var Editor = ""
 
 func launchEditor(initialText string, opts ...simpleEditor.Opts) (string, error) {
 
-	if useEditor {
+	if strings.TrimSpace(Editor) != "" {
 		if file, err := os.CreateTemp("", "comment-*.txt"); err == nil {
 			if _, err = file.WriteString(initialText); err != nil {
 				return "", err
@@ -994,13 +1133,9 @@ func launchEditor(initialText string, opts ...simpleEditor.Opts) (string, error)
 			file.Close()
 			defer os.Remove(file.Name())
 
-			// Run editor
-			var editorPath string
-			if editorPath = os.Getenv("EDITOR"); editorPath == "" {
-				editorPath = "vim"
-			}
-
-			cmd := exec.Command(editorPath, file.Name())
+			// Run editor, the command can have its own arguments
+			editorArgs := append(strings.Fields(Editor), file.Name())
+			cmd := exec.Command(editorArgs[0], editorArgs[1:]...)
 			cmd.Stdin = os.Stdin
 			cmd.Stdout = os.Stdout
 			if err = cmd.Start(); err != nil {
@@ -1021,7 +1156,7 @@ func launchEditor(initialText string, opts ...simpleEditor.Opts) (string, error)
 			return "", err
 		}
 	} else {
-		opts = append(opts, simpleEditor.WithValue{initialText})
+		opts = append(opts, simpleEditor.WithValue{Value: initialText})
 		return simpleEditor.RunEditor(opts...)
 	}
 }
@@ -1113,6 +1248,7 @@ func (prv *PullRequestView) renderPullRequest() {
 			// clear bookmarks
 			prv.bookmarks[FILE_CATEGORY] = make([]bookmark, 0)
 			prv.bookmarks[COMMENT_CATEGORY] = make([]bookmark, 0)
+			prv.bookmarks[HUNK_CATEGORY] = make([]bookmark, 0)
 
 			header.header = &h
 
@@ -1182,60 +1318,32 @@ func (prv *PullRequestView) renderPullRequest() {
 					content.printf("\nBINARY FILE\n")
 				} else {
 					w := content.viewport.Width
-					styleAdd := lipgloss.NewStyle().
-						Bold(true).
-						Foreground(lipgloss.Color("#ffffff")).
-						Background(lipgloss.Color("#005E00e0")).
-						Width(w).MaxHeight(1)
-					styleNorm := lipgloss.NewStyle().
-						Foreground(lipgloss.Color("#999999")).
-						Background(lipgloss.Color("#000000")).
-						Width(w).MaxHeight(1)
-					styleDel := lipgloss.NewStyle().
-						Bold(true).
-						Foreground(lipgloss.Color("#ffffff")).
-						Background(lipgloss.Color("#5e0000")).
-						Width(w).MaxHeight(1)
-
-					for _, frag := range file.TextFragments {
+					styles := newDiffStyles()
 
+					for _, expanded := range prv.pullRequest.fileFragments(file) {
+						frag := expanded.TextFragment
+						prv.addBookmark(content, HUNK_CATEGORY, hunkBookmark{file, expanded})
 						addHeading(content, header, content.viewport.Width, COMMIT_LEVEL, "==O== ==N== (+%d, -%d,  O=%d, N=%d)", frag.LinesAdded, frag.LinesDeleted,
 							frag.OldLines, frag.NewLines)
+						spans := prv.pullRequest.fragmentSpans(fn, frag)
+						if prv.layoutMode.split {
+							prv.renderSplitFragment(content, header, file, frag, spans, styles, commentsForFile)
+							continue
+						}
 						oldN := frag.OldPosition
 						newN := frag.NewPosition
 
 						for pos, ln := range frag.Lines {
-							content.saveLine(prv.pullRequest.GetLastCommitId(), oldN, newN, pos+1, file, ln)
-							var style lipgloss.Style
-							switch ln.Op {
-							case gitdiff.OpAdd:
-								style = styleAdd
-							case gitdiff.OpDelete:
-								style = styleDel
-							default:
-								style = styleNorm
-							}
+							content.saveLine(prv.pullRequest.GetLastCommitId(), oldN, newN, pos+1, file, ln, ln.New())
 
-							escaped := strings.ReplaceAll(ln.Line, "%", "%%")
-							if len(escaped) >= prv.xOffset {
-								escaped = escaped[prv.xOffset:]
-							} else {
-								escaped = ""
-							}
-
-							rendered := style.Render(fillLine(pterm.RemoveColorFromString(fmt.Sprintf("%05d %05d %s  %s", oldN, newN, ln.Op, escaped)), w))
+							prefix := fmt.Sprintf("%05d %05d %s  ", oldN, newN, ln.Op)
+							lineSpans := content.matchCode(prv.search.pattern, spans[pos], lineText(ln), w-len(prefix))
+							rendered := styles[ln.Op].render(prefix, lineSpans, prv.xOffset, w)
+							rendered = strings.ReplaceAll(rendered, "%", "%%")
 
 							content.printf(rendered)
 							if haveFileComments {
-								if commentsForLine, haveLineComments := commentsForFile[-newN]; ln.Op != gitdiff.OpDelete && haveLineComments {
-									prv.PrintComments(content, header, commentsForLine, content.viewport.Width)
-									delete(commentsForFile, -newN)
-								}
-
-								if commentsForLine, haveLineComments := commentsForFile[oldN]; ln.Op != gitdiff.OpAdd && haveLineComments {
-									prv.PrintComments(content, header, commentsForLine, content.viewport.Width)
-									delete(commentsForFile, oldN)
-								}
+								prv.printLineComments(content, header, commentsForFile, oldN, newN, ln.Op != gitdiff.OpAdd, ln.Op != gitdiff.OpDelete)
 							}
 
 							if ln.Op == gitdiff.OpAdd {
@@ -1251,6 +1359,8 @@ func (prv *PullRequestView) renderPullRequest() {
 				}
 			}
 
+			content.matchOtherLines(prv.search.pattern)
+
 			if prv.ready {
 				content.updateViewportWithContent()
 			}
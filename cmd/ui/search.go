@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pterm/pterm"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// matchColor is the background of the text matching the search
+var matchColor = lipgloss.Color("#e0e000")
+
+// search is the regexp search of the content, typed after / to search forward or ? to search backward
+type search struct {
+	input    textinput.Model
+	typing   bool
+	backward bool
+	pattern  *regexp.Regexp
+	// origin is the top line when the search started, the incremental search goes from there
+	origin int
+}
+
+// searchMatch is a line of the content matching the search
+type searchMatch struct {
+	line int
+	// column is the column of the first match in the code, tabs expanded, -1 for the lines that don't scroll
+	column int
+	// width is the number of columns of code shown on the line
+	width int
+}
+
+func newSearchInput(prompt string) textinput.Model {
+	input := textinput.New()
+	input.Prompt = prompt
+	input.SetCursorMode(textinput.CursorStatic)
+	input.Focus()
+	return input
+}
+
+func (c *contentView) addMatch(column int, width int) {
+	c.matches = append(c.matches, searchMatch{c.currentLine(), column, width})
+}
+
+// matchCode marks the pieces of the code line matching the search, the line being the next one of the content
+func (c *contentView) matchCode(pattern *regexp.Regexp, spans []span, text string, width int) []span {
+	if pattern == nil {
+		return spans
+	}
+	locs := pattern.FindAllStringIndex(text, -1)
+	ranges := make([][2]int, 0, len(locs))
+	for _, loc := range locs {
+		if loc[1] > loc[0] {
+			ranges = append(ranges, [2]int{loc[0], loc[1]})
+		}
+	}
+	if len(ranges) == 0 {
+		return spans
+	}
+	c.addMatch(len([]rune(strings.ReplaceAll(text[:ranges[0][0]], "\t", "    "))), width)
+	return markRanges(spans, ranges, func(s *span) {
+		s.matched = true
+	})
+}
+
+// matchOtherLines highlights the matches of the lines that aren't code, the comments and the headings, they lose
+// their style like a selected line
+func (c *contentView) matchOtherLines(pattern *regexp.Regexp) {
+	if pattern == nil {
+		return
+	}
+	normal := lipgloss.NewStyle()
+	matched := lipgloss.NewStyle().Background(matchColor).Foreground(lipgloss.Color("#000000"))
+	for i, line := range *c.content {
+		if _, ok := c.codeLines[i]; ok {
+			continue
+		} else if _, ok := c.rightCodeLines[i]; ok {
+			continue
+		}
+		text := pterm.RemoveColorFromString(line)
+		locs := pattern.FindAllStringIndex(text, -1)
+		var b strings.Builder
+		pos := 0
+		for _, loc := range locs {
+			if loc[1] == loc[0] {
+				continue
+			}
+			b.WriteString(normal.Render(text[pos:loc[0]]))
+			b.WriteString(matched.Render(text[loc[0]:loc[1]]))
+			pos = loc[1]
+		}
+		if pos > 0 {
+			b.WriteString(normal.Render(text[pos:]))
+			(*c.content)[i] = b.String()
+			c.matches = append(c.matches, searchMatch{i, -1, 0})
+		}
+	}
+	sort.Slice(c.matches, func(i, j int) bool {
+		return c.matches[i].line < c.matches[j].line
+	})
+}
+
+// startSearch opens the search prompt
+func (p *PullRequestView) startSearch(backward bool) tea.Cmd {
+	prompt := "/"
+	if backward {
+		prompt = "?"
+	}
+	// The content is rendered again as the pattern changes, the selection wouldn't survive it
+	_ = p.withContentViewPtr(func(content *contentView) error {
+		content.selectLine(-1)
+		return nil
+	})
+	p.search = search{input: newSearchInput(prompt), typing: true, backward: backward, origin: p.cursorLine()}
+	return showStatusCmd(normalMode, p.search.input.View(), 0)
+}
+
+// updateSearch handles the keys typed in the search prompt, the matches being shown as the pattern changes
+func (p *PullRequestView) updateSearch(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		origin := p.search.origin
+		p.search = search{}
+		p.dirty = true
+		p.renderPullRequest()
+		_ = p.withContentViewPtr(func(content *contentView) error {
+			content.viewport.YOffset = origin
+			return nil
+		})
+		return showStatusCmd(normalMode, "", 0)
+	case "enter":
+		p.search.typing = false
+		if p.search.pattern == nil {
+			p.search = search{}
+			return showStatusCmd(normalMode, "", 0)
+		} else if content, ok := p.getContentView(); ok && len(content.matches) == 0 {
+			return showStatusCmd(severeMode, fmt.Sprintf("Pattern not found: %s", p.search.pattern), 3*time.Second)
+		}
+		return showStatusCmd(normalMode, fmt.Sprintf("%s%s", p.search.input.Prompt, p.search.pattern), 0)
+	}
+
+	p.search.input, _ = p.search.input.Update(msg)
+	status := p.search.input.View()
+	if value := p.search.input.Value(); value == "" {
+		p.search.pattern = nil
+	} else if re, err := regexp.Compile(value); err != nil {
+		// Keep the last valid pattern while the user is typing
+		status += "  (" + err.Error() + ")"
+	} else {
+		p.search.pattern = re
+	}
+
+	p.dirty = true
+	p.renderPullRequest()
+	cmd := p.jumpToMatch(!p.search.backward, p.search.origin, true)
+	return tea.Batch(cmd, showStatusCmd(normalMode, status, 0))
+}
+
+// nextMatch moves to the next match in the direction of the search, or in the other direction when reverse
+func (p *PullRequestView) nextMatch(reverse bool) tea.Cmd {
+	forward := p.search.backward == reverse
+	return tea.Batch(p.jumpToMatch(forward, p.cursorLine(), false),
+		showStatusCmd(normalMode, fmt.Sprintf("%s%s", p.search.input.Prompt, p.search.pattern), 0))
+}
+
+// jumpToMatch scrolls to the first match after the line, or before it when going backward, wrapping around the
+// content. The match itself is included when inclusive. The content is scrolled horizontally when the match is out of
+// sight.
+func (p *PullRequestView) jumpToMatch(forward bool, line int, inclusive bool) tea.Cmd {
+	content, ok := p.getContentView()
+	if !ok || len(content.matches) == 0 {
+		return nil
+	}
+
+	var found *searchMatch
+	if forward {
+		for i, m := range content.matches {
+			if m.line > line || (inclusive && m.line == line) {
+				found = &content.matches[i]
+				break
+			}
+		}
+		if found == nil {
+			found = &content.matches[0]
+		}
+	} else {
+		for i := len(content.matches) - 1; i >= 0; i-- {
+			if m := content.matches[i]; m.line < line || (inclusive && m.line == line) {
+				found = &content.matches[i]
+				break
+			}
+		}
+		if found == nil {
+			found = &content.matches[len(content.matches)-1]
+		}
+	}
+
+	_ = p.withContentViewPtr(func(content *contentView) error {
+		content.selectLine(-1)
+		content.viewport.YOffset = found.line
+		return nil
+	})
+	if found.column >= 0 && (found.column < p.xOffset || found.column >= p.xOffset+found.width) {
+		// The horizontal scrolling goes by 4 columns
+		p.xOffset = found.column - found.column%4
+		return renderPrCmd
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"fmt"
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vballestra/sv/sv"
+	"strings"
+)
+
+// diffSide is a column of the split diff
+type diffSide int
+
+const (
+	leftSide diffSide = iota
+	rightSide
+)
+
+// splitRow is a row of the split diff, with the indexes in the fragment of its old and new lines, -1 for none
+type splitRow struct {
+	old int
+	new int
+}
+
+// splitRows aligns the lines of the fragment: the context lines face themselves, the deleted lines of a change face
+// the added lines replacing them
+func splitRows(frag *gitdiff.TextFragment) []splitRow {
+	rows := make([]splitRow, 0, len(frag.Lines))
+	for i := 0; i < len(frag.Lines); {
+		if frag.Lines[i].Op == gitdiff.OpContext {
+			rows = append(rows, splitRow{i, i})
+			i++
+			continue
+		}
+		dels, adds := make([]int, 0), make([]int, 0)
+		for ; i < len(frag.Lines) && frag.Lines[i].Op == gitdiff.OpDelete; i++ {
+			dels = append(dels, i)
+		}
+		for ; i < len(frag.Lines) && frag.Lines[i].Op == gitdiff.OpAdd; i++ {
+			adds = append(adds, i)
+		}
+		for k := 0; k < len(dels) || k < len(adds); k++ {
+			row := splitRow{-1, -1}
+			if k < len(dels) {
+				row.old = dels[k]
+			}
+			if k < len(adds) {
+				row.new = adds[k]
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// lineNumbers are the old and the new line numbers of the lines of the fragment, as shown by the unified diff
+func lineNumbers(frag *gitdiff.TextFragment) ([]int64, []int64) {
+	oldNums, newNums := make([]int64, len(frag.Lines)), make([]int64, len(frag.Lines))
+	oldN, newN := frag.OldPosition, frag.NewPosition
+	for i, ln := range frag.Lines {
+		oldNums[i], newNums[i] = oldN, newN
+		if ln.Op != gitdiff.OpAdd {
+			oldN++
+		}
+		if ln.Op != gitdiff.OpDelete {
+			newN++
+		}
+	}
+	return oldNums, newNums
+}
+
+// renderSplitFragment prints the old lines of the fragment on the left and the new ones on the right, each column
+// having its own code lines so that comments go to the selected side
+func (prv *PullRequestView) renderSplitFragment(content *contentView, header *pullRequestHeader, file *gitdiff.File,
+	frag *gitdiff.TextFragment, spans [][]span, styles map[gitdiff.LineOp]diffLineStyle, comments map[int64][]sv.Comment) {
+	w := content.viewport.Width
+	colW := (w - 1) / 2
+	content.splitWidth = colW
+	empty := lipgloss.NewStyle().Background(lipgloss.Color("#202020")).Render(strings.Repeat(" ", colW))
+	separator := styles[gitdiff.OpContext].base.Render(strings.Repeat("│", w-2*colW))
+	commitId := prv.pullRequest.GetLastCommitId()
+	oldNums, newNums := lineNumbers(frag)
+
+	side := func(i int, nums []int64) string {
+		if i == -1 {
+			return empty
+		}
+		ln := frag.Lines[i]
+		prefix := fmt.Sprintf("%05d %s ", nums[i], ln.Op)
+		lineSpans := content.matchCode(prv.search.pattern, spans[i], lineText(ln), colW-len(prefix))
+		return styles[ln.Op].render(prefix, lineSpans, prv.xOffset, colW)
+	}
+
+	for _, row := range splitRows(frag) {
+		if row.old != -1 {
+			ln := frag.Lines[row.old]
+			content.saveLine(commitId, oldNums[row.old], newNums[row.old], row.old+1, file, ln, false)
+		}
+		if row.new != -1 {
+			ln := frag.Lines[row.new]
+			content.saveRightLine(commitId, oldNums[row.new], newNums[row.new], row.new+1, file, ln)
+		}
+
+		rendered := side(row.old, oldNums) + separator + side(row.new, newNums)
+		content.printf(strings.ReplaceAll(rendered, "%", "%%"))
+
+		oldN, newN := int64(0), int64(0)
+		if row.old != -1 {
+			oldN = oldNums[row.old]
+		}
+		if row.new != -1 {
+			newN = newNums[row.new]
+		}
+		prv.printLineComments(content, header, comments, oldN, newN, row.old != -1, row.new != -1)
+	}
+}
+
+// printLineComments prints the comments of the new line then the ones of the old line, they're printed once
+func (prv *PullRequestView) printLineComments(content *contentView, header *pullRequestHeader, comments map[int64][]sv.Comment,
+	oldN int64, newN int64, hasOld bool, hasNew bool) {
+	if commentsForLine, ok := comments[-newN]; hasNew && ok {
+		prv.PrintComments(content, header, commentsForLine, content.viewport.Width)
+		delete(comments, -newN)
+	}
+	if commentsForLine, ok := comments[oldN]; hasOld && ok {
+		prv.PrintComments(content, header, commentsForLine, content.viewport.Width)
+		delete(comments, oldN)
+	}
+}
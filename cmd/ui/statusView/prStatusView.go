@@ -16,6 +16,7 @@ import (
 
 type PrStatusView struct {
 	sv           sv.Sv
+	remote       string
 	pullRequests []sv.PullRequestStatus
 	w            int
 	h            int
@@ -48,6 +49,11 @@ func (m showStatusError) Update(view PrStatusView) (PrStatusView, tea.Cmd) {
 	return view, nil
 }
 
+// showStatusInfoCmd displays a message in the footer, the same way as the errors
+func showStatusInfoCmd(info string) tea.Cmd {
+	return showStatusErrorCmd(info)
+}
+
 func showStatusErrorCmd(err string) tea.Cmd {
 	return func() tea.Msg {
 		return showStatusError{err}
@@ -313,6 +319,16 @@ func showPrCmd(s sv.Sv, id string) tea.Cmd {
 	}
 }
 
+func checkoutPrCmd(s sv.Sv, remote string, id string) tea.Cmd {
+	return func() tea.Msg {
+		if branch, err := s.CheckoutPullRequest(remote, id, false); err == nil {
+			return showStatusInfoCmd(fmt.Sprintf("Switched to branch %s", branch))()
+		} else {
+			return showStatusErrorCmd(fmt.Sprintf("Cannot checkout pr %s : %s", id, err))()
+		}
+	}
+}
+
 type modelAndCmd struct {
 	model tea.Model
 	cmd   tea.Cmd
@@ -335,7 +351,7 @@ func (m showPrMsg) Update(view PrStatusView) (tea.Model, tea.Cmd) {
 		if err := ui.ShowPr(m.pullRequest); err != nil {
 			if _, ok := err.(*sv.MissingCommitError); ok {
 				// Let's try updating the archive
-				if err := sv.ForceFetch(view.sv); err == nil {
+				if err := sv.ForceFetch(view.sv, view.remote); err == nil {
 					cmds = append(cmds, showPrCmd(view.sv, fmt.Sprintf("%d", m.pullRequest.GetId())))
 				} else {
 					cmds = append(cmds, showStatusErrorCmd(fmt.Sprintf("Error while showing load pr %d : %s", m.pullRequest.GetId(), err)))
@@ -478,6 +494,13 @@ func (p PrStatusView) handleKey(m tea.KeyMsg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, tea.Quit)
 		case 'r':
 			cmds = append(cmds, loadPrStatusCmd)
+		case 'c':
+			pr := p.pullRequests[p.statusTable.GetHighlightedRowIndex()]
+			if pr.GetRepository() == p.sv.GetRepositoryFullName() {
+				cmds = append(cmds, showStatusInfoCmd(fmt.Sprintf("Checking out pr %d ...", pr.GetId())), checkoutPrCmd(p.sv, p.remote, fmt.Sprintf("%d", pr.GetId())))
+			} else {
+				cmds = append(cmds, showStatusErrorCmd(fmt.Sprintf("Repo '%s' doesn't match with '%s'", pr.GetRepository(), p.sv.GetRepositoryFullName())))
+			}
 		case 'm':
 			pp.isMonitoring = !p.isMonitoring
 			if pp.isMonitoring {
@@ -522,10 +545,11 @@ func (p PrStatusView) View() string {
 	return strings.Join(res, "\n")
 }
 
-func RunPrStatusView(s sv.Sv) error {
+func RunPrStatusView(s sv.Sv, remote string) error {
 
 	view := PrStatusView{
 		sv:            s,
+		remote:        remote,
 		w:             0,
 		h:             0,
 		ready:         false,
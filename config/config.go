@@ -0,0 +1,186 @@
+// Package config reads and writes the sv configuration file, made of named profiles
+package config
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const DefaultProfile = "default"
+
+// Profile holds the settings of a host or a workspace. Empty values are unset.
+type Profile struct {
+	// Host selects the profile when the remote is hosted there
+	Host string `yaml:"host,omitempty"`
+	// Workspace selects the profile for the local copies inside this directory
+	Workspace     string `yaml:"workspace,omitempty"`
+	Token         string `yaml:"token,omitempty"`
+	Username      string `yaml:"username,omitempty"`
+	AppPassword   string `yaml:"app-password,omitempty"`
+	Remote        string `yaml:"remote,omitempty"`
+	SshKeyComment string `yaml:"ssh-key-comment,omitempty"`
+	Editor        string `yaml:"editor,omitempty"`
+	BaseBranch    string `yaml:"base-branch,omitempty"`
+}
+
+// Keys are the names of the profile settings, in the file order
+var Keys = []string{"host", "workspace", "token", "username", "app-password", "remote", "ssh-key-comment", "editor", "base-branch"}
+
+// SecretKeys are the settings that shouldn't be displayed as is
+var SecretKeys = map[string]bool{"token": true, "app-password": true}
+
+func (p *Profile) fields() map[string]*string {
+	return map[string]*string{
+		"host":            &p.Host,
+		"workspace":       &p.Workspace,
+		"token":           &p.Token,
+		"username":        &p.Username,
+		"app-password":    &p.AppPassword,
+		"remote":          &p.Remote,
+		"ssh-key-comment": &p.SshKeyComment,
+		"editor":          &p.Editor,
+		"base-branch":     &p.BaseBranch,
+	}
+}
+
+func (p *Profile) Get(key string) (string, error) {
+	if f, ok := p.fields()[key]; ok {
+		return *f, nil
+	}
+	return "", fmt.Errorf("unknown key '%s', expected one of %s", key, strings.Join(Keys, ", "))
+}
+
+func (p *Profile) Set(key string, value string) error {
+	if f, ok := p.fields()[key]; ok {
+		*f = value
+		return nil
+	}
+	return fmt.Errorf("unknown key '%s', expected one of %s", key, strings.Join(Keys, ", "))
+}
+
+type Config struct {
+	Profiles map[string]*Profile `yaml:"profiles"`
+
+	path string
+}
+
+// DefaultPath is $XDG_CONFIG_HOME/sv/config.yaml, XDG_CONFIG_HOME defaulting to ~/.config
+func DefaultPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, ".config")
+		}
+	}
+	return filepath.Join(dir, "sv", "config.yaml")
+}
+
+// Load reads the configuration at path, a missing file is an empty configuration
+func Load(path string) (*Config, error) {
+	cfg := &Config{Profiles: make(map[string]*Profile), path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid configuration %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]*Profile)
+	}
+	return cfg, nil
+}
+
+// Save writes the configuration back, readable by the user only since it holds credentials
+func (c *Config) Save() error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0600)
+}
+
+func (c *Config) Path() string {
+	return c.path
+}
+
+// Profile returns the named profile, creating it if needed
+func (c *Config) Profile(name string) *Profile {
+	p, ok := c.Profiles[name]
+	if !ok {
+		p = &Profile{}
+		c.Profiles[name] = p
+	}
+	return p
+}
+
+// ProfileNames are sorted
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Match finds the profile of a workspace or, failing that, of a remote host. The deepest workspace wins, the
+// default profile is used when nothing matches. The name is empty when there's no profile at all.
+func (c *Config) Match(workspace string, host string) (string, *Profile) {
+	best, bestLen := "", -1
+	if abs, err := filepath.Abs(workspace); err == nil {
+		for _, name := range c.ProfileNames() {
+			p := c.Profiles[name]
+			if p.Workspace == "" {
+				continue
+			}
+			dir := filepath.Clean(expandHome(p.Workspace))
+			if (abs == dir || strings.HasPrefix(abs, dir+string(filepath.Separator))) && len(dir) > bestLen {
+				best, bestLen = name, len(dir)
+			}
+		}
+	}
+	if best != "" {
+		return best, c.Profiles[best]
+	}
+
+	if name, p := c.ForHost(host); p != nil {
+		return name, p
+	}
+
+	if p, ok := c.Profiles[DefaultProfile]; ok {
+		return DefaultProfile, p
+	}
+	return "", nil
+}
+
+// ForHost returns the first profile of the host, the name is empty when there's none
+func (c *Config) ForHost(host string) (string, *Profile) {
+	for _, name := range c.ProfileNames() {
+		if p := c.Profiles[name]; p.Host != "" && strings.EqualFold(p.Host, host) {
+			return name, p
+		}
+	}
+	return "", nil
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
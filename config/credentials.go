@@ -0,0 +1,92 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrNoCredential is returned when the credential helper has nothing for a host
+var ErrNoCredential = errors.New("no credential stored")
+
+// Credential is a secret (token or app password) stored by the git credential helper for https://<Host>/sv
+type Credential struct {
+	Host     string
+	Username string
+	Secret   string
+}
+
+// credentialPath keeps the credentials of sv apart from the ones git uses for the remotes: they are only read back
+// when the helper is asked for this path
+const credentialPath = "sv"
+
+// gitCredential runs "git credential <action>" for the credentials of sv, never prompting the user
+func gitCredential(action string, c Credential) (map[string]string, error) {
+	path, err := exec.LookPath("git")
+	if err != nil {
+		return nil, err
+	}
+
+	input := fmt.Sprintf("protocol=https\nhost=%s\npath=%s\n", c.Host, credentialPath)
+	if c.Username != "" {
+		input += fmt.Sprintf("username=%s\n", c.Username)
+	}
+	if c.Secret != "" {
+		input += fmt.Sprintf("password=%s\n", c.Secret)
+	}
+
+	// The helpers ignore the path unless told otherwise
+	cmd := exec.Command(path, "-c", "credential.useHttpPath=true", "credential", action)
+	cmd.Stdin = strings.NewReader(input + "\n")
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=")
+	out, err := cmd.Output()
+	if err != nil {
+		if action == "fill" {
+			// git fails when it would have to prompt
+			return nil, ErrNoCredential
+		}
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if k, v, ok := strings.Cut(scanner.Text(), "="); ok {
+			values[k] = v
+		}
+	}
+	return values, nil
+}
+
+// LoadCredential asks the credential helper for the secret of host stored by StoreCredential, username may be empty
+func LoadCredential(host string, username string) (*Credential, error) {
+	values, err := gitCredential("fill", Credential{Host: host, Username: username})
+	if err != nil {
+		return nil, err
+	} else if values["password"] == "" {
+		return nil, ErrNoCredential
+	}
+	return &Credential{Host: host, Username: values["username"], Secret: values["password"]}, nil
+}
+
+// StoreCredential saves c with the credential helper, and checks it can be read back since git silently
+// ignores the credentials when no helper is configured
+func StoreCredential(c Credential) error {
+	if _, err := gitCredential("approve", c); err != nil {
+		return err
+	}
+	if stored, err := LoadCredential(c.Host, c.Username); err != nil || stored.Secret != c.Secret {
+		return errors.New("the credential wasn't stored, configure a git credential helper first (git config --global credential.helper ...)")
+	}
+	return nil
+}
+
+// EraseCredential removes the credential of host from the credential helper
+func EraseCredential(host string, username string) error {
+	_, err := gitCredential("reject", Credential{Host: host, Username: username})
+	return err
+}
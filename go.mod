@@ -4,6 +4,7 @@ go 1.18
 
 require (
 	github.com/Khan/genqlient v0.5.0
+	github.com/alecthomas/chroma v0.10.0
 	github.com/antihax/optional v1.0.0
 	github.com/bluekeyes/go-gitdiff v0.6.1
 	github.com/briandowns/spinner v1.18.1
@@ -24,6 +25,7 @@ require (
 	github.com/xanzy/ssh-agent v0.3.0
 	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519
 	golang.org/x/oauth2 v0.0.0-20220309155454-6242fa91716a
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
 
 require (
@@ -31,7 +33,6 @@ require (
 	github.com/ProtonMail/go-crypto v0.0.0-20210428141323-04723f9f07d7 // indirect
 	github.com/acomagu/bufpipe v1.0.3 // indirect
 	github.com/agnivade/levenshtein v1.1.1 // indirect
-	github.com/alecthomas/chroma v0.10.0 // indirect
 	github.com/alexflint/go-arg v1.4.2 // indirect
 	github.com/alexflint/go-scalar v1.0.0 // indirect
 	github.com/atomicgo/cursor v0.0.1 // indirect
@@ -87,5 +88,4 @@ require (
 	google.golang.org/protobuf v1.27.1 // indirect
 	gopkg.in/warnings.v0 v0.1.2 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
 )
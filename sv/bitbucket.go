@@ -3,20 +3,16 @@ package sv
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"github.com/antihax/optional"
 	"github.com/bluekeyes/go-gitdiff/gitdiff"
-	"github.com/briandowns/spinner"
-	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/pterm/pterm"
 	"github.com/vballestra/sv/bitbucket"
-	sshagent "github.com/xanzy/ssh-agent"
-	ssh2 "golang.org/x/crypto/ssh"
-	"golang.org/x/crypto/ssh/agent"
-	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -26,76 +22,398 @@ type BitBucketSv struct {
 	repoSlug  string
 	workspace string
 	localRepo string
+	// httpClient is the client of the api client configuration
+	httpClient *http.Client
+	// rest sends the bodies the generated client can't, it shares the http client
+	rest *restClient
+
+	pendingReviews map[int32]*BitbucketPendingReview
 }
 
 func (b *BitBucketSv) GetCurrentBranch() (string, error) {
-	//TODO implement me
-	panic("implement me")
+	return getCurrentBranch(b.localRepo)
+}
+
+func (b *BitBucketSv) resolveHeadBranch(headBranch optional.String) (string, error) {
+	if headBranch.IsSet() {
+		return headBranch.Value(), nil
+	} else {
+		return b.GetCurrentBranch()
+	}
+}
+
+func (b *BitBucketSv) GetDefaultBranch() (string, error) {
+	if repo, _, err := b.client.RepositoriesApi.RepositoriesWorkspaceRepoSlugGet(b.ctx, b.repoSlug, b.workspace); err != nil {
+		return "", err
+	} else if repo.Mainbranch == nil {
+		return "", fmt.Errorf("repository %s has no main branch", b.GetRepositoryFullName())
+	} else {
+		return repo.Mainbranch.Name, nil
+	}
+}
+
+func (b *BitBucketSv) resolveBaseBranch(baseBranch optional.String) (string, error) {
+	if baseBranch.IsSet() {
+		return baseBranch.Value(), nil
+	} else {
+		return b.GetDefaultBranch()
+	}
+}
+
+// resolveReviewers converts the reviewers to accounts and eventually adds the default reviewers, skipping
+// the current user because Bitbucket doesn't allow the author to be a reviewer. A reviewer is given by its {uuid},
+// used as is, by its username, nickname or display name, looked up among the members of the workspace, or by its
+// account id.
+func (b *BitBucketSv) resolveReviewers(usernames []string, withDefaults bool) ([]bitbucket.Account, error) {
+	me, err := b.currentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{me.Uuid: true}
+	reviewers := make([]bitbucket.Account, 0)
+	add := func(uuid string) {
+		if !seen[uuid] {
+			seen[uuid] = true
+			reviewers = append(reviewers, bitbucket.Account{Type_: "user", Uuid: uuid})
+		}
+	}
+
+	var members []bitbucket.Account
+	for _, username := range usernames {
+		if strings.HasPrefix(username, "{") && strings.HasSuffix(username, "}") {
+			add(username)
+			continue
+		} else if members == nil {
+			if members, err = b.workspaceMembers(); err != nil {
+				return nil, err
+			}
+		}
+
+		if u, ok := findMember(members, username); ok {
+			add(u.Uuid)
+		} else if u, _, err := b.client.UsersApi.UsersSelectedUserGet(b.ctx, username); err == nil {
+			// The account ids aren't in the memberships, the users api finds them
+			add(u.Uuid)
+		} else {
+			return nil, fmt.Errorf("cannot find user '%s' among the members of %s: %w", username, b.workspace, err)
+		}
+	}
+
+	if withDefaults {
+		if defaults, _, err := b.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugDefaultReviewersGet(b.ctx, b.repoSlug, b.workspace); err != nil {
+			return nil, err
+		} else {
+			for u := range Paginate[bitbucket.User, bitbucket.PaginatedUsers](b.ctx, PaginatedUsers{&defaults}) {
+				add(u.Uuid)
+			}
+		}
+	}
+
+	return reviewers, nil
+}
+
+func (b *BitBucketSv) ResolveTitleAndDescription(remote string, titleOpt optional.String, descriptionOpt optional.String, headBranch string, baseBranch string) (string, string, error) {
+	return resolveTitleAndDescription(b.localRepo, bitbucketTemplates, remote, titleOpt, descriptionOpt, headBranch, baseBranch)
+}
+
+// workspaceMembers returns the accounts of the workspace
+func (b *BitBucketSv) workspaceMembers() ([]bitbucket.Account, error) {
+	members, _, err := b.client.WorkspacesApi.WorkspacesWorkspaceMembersGet(b.ctx, b.workspace)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]bitbucket.Account, 0)
+	for m := range Paginate[bitbucket.WorkspaceMembership, bitbucket.PaginatedWorkspaceMemberships](b.ctx, PaginatedWorkspaceMemberships{&members}) {
+		if m.User != nil {
+			users = append(users, *m.User)
+		}
+	}
+	return users, nil
+}
+
+// findMember finds the account by its username, its nickname or its display name, in this order
+func findMember(members []bitbucket.Account, name string) (bitbucket.Account, bool) {
+	for _, field := range []func(u bitbucket.Account) string{
+		func(u bitbucket.Account) string { return u.Username },
+		func(u bitbucket.Account) string { return u.Nickname },
+		func(u bitbucket.Account) string { return u.DisplayName },
+	} {
+		for _, u := range members {
+			if v := field(u); v != "" && strings.EqualFold(v, name) {
+				return u, true
+			}
+		}
+	}
+	return bitbucket.Account{}, false
+}
+
+// ListCollaborators returns the members of the workspace, by their username, their nickname or their uuid when they
+// have neither
+func (b *BitBucketSv) ListCollaborators() ([]string, error) {
+	members, err := b.workspaceMembers()
+	if err != nil {
+		return nil, err
+	}
+	logins := make([]string, 0)
+	for _, u := range members {
+		if u.Username != "" {
+			logins = append(logins, u.Username)
+		} else if u.Nickname != "" {
+			logins = append(logins, u.Nickname)
+		} else {
+			logins = append(logins, u.Uuid)
+		}
+	}
+	return logins, nil
+}
+
+func (b *BitBucketSv) ListLabels() ([]string, error) {
+	return []string{}, nil
 }
 
 func (b *BitBucketSv) CreatePullRequest(args CreatePullRequestArgs) (PullRequestStatus, error) {
-	//TODO implement me
-	panic("implement me")
+	if len(args.Labels) > 0 {
+		pterm.Warning.Println("Bitbucket doesn't support labels, ignoring them")
+	}
+
+	if reviewers, err := b.resolveReviewers(args.Reviewers, args.DefaultReviewers); err != nil {
+		return nil, err
+	} else if headBranch, err := b.resolveHeadBranch(args.HeadBranch); err != nil {
+		return nil, err
+	} else if baseBranch, err := b.resolveBaseBranch(args.BaseBranch); err != nil {
+		return nil, err
+	} else if title, description, err := b.ResolveTitleAndDescription(args.Remote, args.Title, args.Description, headBranch, baseBranch); err != nil {
+		return nil, err
+	} else if pr, resp, err := b.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugPullrequestsPost(b.ctx, b.repoSlug, b.workspace, &bitbucket.PullrequestsApiRepositoriesWorkspaceRepoSlugPullrequestsPostOpts{
+		Body: optional.NewInterface(bitbucket.Pullrequest{
+			Type_:       "pullrequest",
+			Title:       title,
+			Summary:     map[string]interface{}{"raw": description},
+			Source:      &bitbucket.PullrequestEndpoint{Branch: map[string]interface{}{"name": headBranch}},
+			Destination: &bitbucket.PullrequestEndpoint{Branch: map[string]interface{}{"name": baseBranch}},
+			Reviewers:   reviewers,
+			Draft:       args.Draft,
+		}),
+	}); err != nil {
+		return nil, err
+	} else if resp.StatusCode != 201 {
+		return nil, fmt.Errorf("cannot create pr, status code = %d", resp.StatusCode)
+	} else {
+		return BitbucketPullRequestStatusWrapper{&pr, nil, true}, nil
+	}
 }
 
 func (b *BitBucketSv) GetRepositoryFullName() string {
 	return fmt.Sprintf("%s/%s", b.workspace, b.repoSlug)
 }
 
+func (b *BitBucketSv) currentUser() (bitbucket.User, error) {
+	user, resp, err := b.client.UsersApi.UserGet(b.ctx)
+	if err != nil {
+		return user, err
+	} else if resp.StatusCode != 200 {
+		return user, fmt.Errorf("cannot read current user, status code = %d", resp.StatusCode)
+	}
+	return user, nil
+}
+
+// statusFields adds the participants to the lists of pull requests, Bitbucket leaves them out by default
+const statusFields = "+values.participants"
+
 func (b *BitBucketSv) PullRequestStatus() (<-chan PullRequestStatus, error) {
-	//TODO implement me
-	panic("implement me")
+	user, err := b.currentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	// The first pages are read here so that the failures are reported instead of looking like no PRs
+	mine, _, err := b.client.PullrequestsApi.PullrequestsSelectedUserGet(b.ctx, user.Uuid, &bitbucket.PullrequestsApiPullrequestsSelectedUserGetOpts{
+		State:  optional.NewString("OPEN"),
+		Fields: optional.NewString(statusFields),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read authored prs: %w", err)
+	}
+	toReview, _, err := b.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugPullrequestsGet(b.ctx, b.repoSlug, b.workspace, &bitbucket.PullrequestsApiRepositoriesWorkspaceRepoSlugPullrequestsGetOpts{
+		State:  optional.NewInterface([]string{"OPEN"}),
+		Q:      optional.NewString(fmt.Sprintf(`reviewers.uuid="%s"`, user.Uuid)),
+		Fields: optional.NewString(statusFields),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read prs to review: %w", err)
+	}
+
+	ch := make(chan PullRequestStatus)
+	go func() {
+		for pr := range Paginate[bitbucket.Pullrequest, bitbucket.PaginatedPullrequests](b.ctx, PaginatedPullrequests{&mine}) {
+			ch <- b.toPullRequestStatus(pr, true)
+		}
+		for pr := range Paginate[bitbucket.Pullrequest, bitbucket.PaginatedPullrequests](b.ctx, PaginatedPullrequests{&toReview}) {
+			ch <- b.toPullRequestStatus(pr, false)
+		}
+		close(ch)
+	}()
+
+	return ch, nil
 }
 
-func (b *BitBucketSv) Fetch() error {
-	rep, giterr := git.PlainOpen(b.localRepo)
-	if giterr != nil {
-		return giterr
+// toPullRequestStatus reads the commit statuses of the last commit of the pr, the participants being in the list
+func (b *BitBucketSv) toPullRequestStatus(pr bitbucket.Pullrequest, isMine bool) PullRequestStatus {
+	workspace, repoSlug := b.workspace, b.repoSlug
+	if pr.Destination != nil && pr.Destination.Repository != nil {
+		if parts := strings.SplitN(pr.Destination.Repository.FullName, "/", 2); len(parts) == 2 {
+			workspace, repoSlug = parts[0], parts[1]
+		}
 	}
-	if sshagent.Available() {
 
-		if a, _, err := sshagent.New(); err != nil {
-			return fmt.Errorf("error creating SSH agent: %q", err)
-		} else if sigs, err := a.Signers(); err != nil {
-			return fmt.Errorf("While getting signers", err)
-		} else {
-			newSigs := make([]ssh2.Signer, 0)
-			for _, s := range sigs {
-				if k, ok := s.PublicKey().(*agent.Key); ok {
-					// We don't use selector for now in BB (just because I'm lazy and don't want to spend
-					// time on it)
-					if k != nil /*b.sshKeySelector.MatchString(k.Comment)*/ {
-						newSigs = append(newSigs, s)
-					}
-				}
-			}
+	statuses := make([]bitbucket.Commitstatus, 0)
+	if res, _, err := b.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdStatusesGet(b.ctx, pr.Id, repoSlug, workspace, nil); err == nil {
+		// Statuses are few, don't bother with pagination here
+		statuses = res.Values
+	} else {
+		pterm.Warning.Printfln("cannot read pr %d statuses: %v", pr.Id, err)
+	}
+
+	return BitbucketPullRequestStatusWrapper{&pr, statuses, isMine}
+}
+
+type BitbucketPullRequestStatusWrapper struct {
+	*bitbucket.Pullrequest
+	statuses []bitbucket.Commitstatus
+	isMine   bool
+}
 
-			if len(newSigs) > 0 {
-				ag := &ssh.PublicKeysCallback{
-					User: "git",
-					Callback: func() ([]ssh2.Signer, error) {
-						return newSigs, nil
-					},
-				}
+func (b BitbucketPullRequestStatusWrapper) GetId() interface{} {
+	return b.Id
+}
 
-				ag.HostKeyCallback = func(hostname string, remote net.Addr, key ssh2.PublicKey) error {
-					return nil
-				}
+func (b BitbucketPullRequestStatusWrapper) GetTitle() string {
+	return b.Title
+}
 
-				sp := spinner.New(spinner.CharSets[55], time.Millisecond*50, spinner.WithSuffix(fmt.Sprintf(" Updating repository")))
-				sp.Start()
-				err = rep.Fetch(&git.FetchOptions{RemoteName: "origin", Auth: ag})
-				sp.Stop()
+func (b BitbucketPullRequestStatusWrapper) GetStatus() string {
+	return b.State
+}
 
-				return err
+func (b BitbucketPullRequestStatusWrapper) GetBranchName() string {
+	if b.Source == nil {
+		return ""
+	}
+	data, _ := b.Source.Branch.(map[string]interface{})
+	name, _ := data["name"].(string)
+	return name
+}
 
-			} else {
-				return fmt.Errorf("Couldn't find any suitable keys, won't try to fetch the repo '%s'", b.localRepo)
-			}
+func (b BitbucketPullRequestStatusWrapper) GetBaseName() string {
+	if b.Destination == nil {
+		return ""
+	}
+	data, _ := b.Destination.Branch.(map[string]interface{})
+	name, _ := data["name"].(string)
+	return name
+}
+
+func (b BitbucketPullRequestStatusWrapper) GetReviews() []Review {
+	rev := make([]Review, 0)
+	for _, p := range b.Participants {
+		if state := participantState(p); state != "" {
+			rev = append(rev, BitbucketReview{p, state})
 		}
-	} else {
-		return errors.New("Please use ssh agent")
 	}
+	return rev
+}
+
+// isPipeline tells apart Bitbucket Pipelines builds, that we show as checks, from external
+// build statuses, that we show as contexts (just like GitHub actions vs commit statuses).
+func isPipeline(st bitbucket.Commitstatus) bool {
+	return strings.Contains(st.Url, "/addon/pipelines/") || strings.Contains(st.Url, "/pipelines/results/")
+}
+
+var bitbucketCheckStates = map[string]string{
+	"SUCCESSFUL": "SUCCESS",
+	"FAILED":     "FAILURE",
+	"INPROGRESS": "IN_PROGRESS",
+	"STOPPED":    "CANCELLED",
+}
+
+var bitbucketContextStates = map[string]string{
+	"SUCCESSFUL": "SUCCESS",
+	"FAILED":     "ERROR",
+	"INPROGRESS": "PENDING",
+	"STOPPED":    "ERROR",
+}
+
+func (b BitbucketPullRequestStatusWrapper) countStatuses(pipelines bool, names map[string]string) map[string]int {
+	states := make(map[string]int)
+	for _, st := range b.statuses {
+		if isPipeline(st) != pipelines {
+			continue
+		}
+		state, ok := names[st.State]
+		if !ok {
+			state = st.State
+		}
+		states[state] += 1
+	}
+	return states
+}
+
+func (b BitbucketPullRequestStatusWrapper) GetChecksByStatus() map[string]int {
+	return b.countStatuses(true, bitbucketCheckStates)
+}
+
+func (b BitbucketPullRequestStatusWrapper) GetContextByStatus() map[string]int {
+	return b.countStatuses(false, bitbucketContextStates)
+}
+
+func (b BitbucketPullRequestStatusWrapper) GetAuthor() string {
+	if b.Author != nil {
+		return b.Author.DisplayName
+	}
+	return ""
+}
+
+func (b BitbucketPullRequestStatusWrapper) GetRepository() string {
+	if b.Destination != nil && b.Destination.Repository != nil {
+		return b.Destination.Repository.FullName
+	}
+	return ""
+}
+
+func (b BitbucketPullRequestStatusWrapper) IsMine() bool {
+	return b.isMine
+}
+
+func (b *BitBucketSv) Fetch(remote string) error {
+	// We don't use selector for now in BB (just because I'm lazy and don't want to spend
+	// time on it)
+	return fetchWithAgent(b.localRepo, remote, nil)
+}
+
+func (b *BitBucketSv) PushBranch(remote string, branch string, force bool) (bool, error) {
+	return pushBranch(b.localRepo, nil, remote, branch, force)
+}
+
+// CheckoutPullRequest fetches the source branch, from the source repository when it's a fork since Bitbucket has
+// no pull request refs
+func (b *BitBucketSv) CheckoutPullRequest(remote string, id string, force bool) (string, error) {
+	pr, err := b.GetPullRequest(id)
+	if err != nil {
+		return "", err
+	}
+	bpr := pr.(BitbucketPullRequestWrapper)
+	branch := bpr.GetBranch().GetName()
+
+	head := pullRequestHead{id: id, remote: remote, ref: fmt.Sprintf("refs/heads/%s", branch), branch: branch}
+	originPath := fmt.Sprintf("%s/%s", b.workspace, b.repoSlug)
+	if bpr.Source.Repository != nil && !strings.EqualFold(bpr.Source.Repository.FullName, originPath) {
+		if head.remoteUrl, err = forkRemoteUrl(b.localRepo, remote, originPath, bpr.Source.Repository.FullName); err != nil {
+			return "", err
+		}
+		head.branch = forkBranch(bpr.Id, branch)
+	}
+	return head.branch, checkoutHead(b.localRepo, nil, head, force)
 }
 
 func (b *BitBucketSv) GetPullRequest(id string) (PullRequest, error) {
@@ -114,17 +432,103 @@ func NewBitBucketSv(username string, password string, repoSlug string, workspace
 	cfg.HTTPClient = &http.Client{}
 	auth := bitbucket.BasicAuth{UserName: username, Password: password}
 	ctx := context.WithValue(context.Background(), bitbucket.ContextBasicAuth, auth)
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
 	return &BitBucketSv{ctx: ctx, client: bitbucket.NewAPIClient(cfg), repoSlug: repoSlug, workspace: workspace,
-		localRepo: repo,
+		localRepo:      repo,
+		httpClient:     cfg.HTTPClient,
+		rest:           newRestClient(cfg.BasePath, "Authorization", basic, cfg.HTTPClient),
+		pendingReviews: make(map[int32]*BitbucketPendingReview),
 	}
 }
 
-func (b *BitBucketSv) ListPullRequests(prsQuery string) (<-chan PullRequest, error) {
+func (b *BitBucketSv) wrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	b.httpClient.Transport = wrap(b.httpClient.Transport)
+}
+
+// BitbucketLogin returns the username of the account the app password belongs to
+func BitbucketLogin(username string, appPassword string) (string, error) {
+	if user, err := NewBitBucketSv(username, appPassword, "", "", "").(*BitBucketSv).currentUser(); err != nil {
+		return "", err
+	} else {
+		return user.Username, nil
+	}
+}
+
+var bitbucketStates = map[string][]string{
+	"":       {"OPEN"},
+	"open":   {"OPEN"},
+	"closed": {"DECLINED", "SUPERSEDED"},
+	"merged": {"MERGED"},
+	"all":    {"OPEN", "MERGED", "DECLINED", "SUPERSEDED"},
+}
+
+// bbqlString quotes a value for a BBQL query
+func bbqlString(value string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(value, `\`, `\\`), `"`, `\"`) + `"`
+}
+
+// bbqlUser matches a user by nickname, or by uuid for @me
+func (b *BitBucketSv) bbqlUser(field string, login string) (string, error) {
+	if login != CurrentUser {
+		return fmt.Sprintf("%s.nickname=%s", field, bbqlString(login)), nil
+	}
+	if user, err := b.currentUser(); err != nil {
+		return "", err
+	} else {
+		return fmt.Sprintf("%s.uuid=%s", field, bbqlString(user.Uuid)), nil
+	}
+}
+
+// bbql translates the filter in a BBQL query, the state is given apart
+func (b *BitBucketSv) bbql(filter PullRequestFilter) (string, error) {
+	terms := make([]string, 0)
+	if filter.Label != "" {
+		return "", fmt.Errorf("filtering on labels: %w", ErrNotSupported)
+	}
+	if filter.Author != "" {
+		if term, err := b.bbqlUser("author", filter.Author); err != nil {
+			return "", err
+		} else {
+			terms = append(terms, term)
+		}
+	}
+	if filter.Reviewer != "" {
+		if term, err := b.bbqlUser("reviewers", filter.Reviewer); err != nil {
+			return "", err
+		} else {
+			terms = append(terms, term)
+		}
+	}
+	if filter.Base != "" {
+		terms = append(terms, fmt.Sprintf("destination.branch.name=%s", bbqlString(filter.Base)))
+	}
+	if filter.Head != "" {
+		terms = append(terms, fmt.Sprintf("source.branch.name=%s", bbqlString(filter.Head)))
+	}
+	if filter.Draft.IsSet() {
+		terms = append(terms, fmt.Sprintf("draft=%t", filter.Draft.Value()))
+	}
+	if !filter.UpdatedSince.IsZero() {
+		terms = append(terms, fmt.Sprintf("updated_on>=%s", filter.UpdatedSince.Format(time.RFC3339)))
+	}
+	if filter.Query != "" {
+		terms = append(terms, fmt.Sprintf("(%s)", filter.Query))
+	}
+	return strings.Join(terms, " AND "), nil
+}
+
+func (b *BitBucketSv) ListPullRequests(filter PullRequestFilter) (<-chan PullRequest, error) {
+	states, ok := bitbucketStates[filter.State]
+	if !ok {
+		return nil, fmt.Errorf("unknown state '%s'", filter.State)
+	}
 	vars := bitbucket.PullrequestsApiRepositoriesWorkspaceRepoSlugPullrequestsGetOpts{
-		State: optional.NewString("ACTIVE"),
+		State: optional.NewInterface(states),
 	}
-	if len(prsQuery) > 0 {
-		vars.Q = optional.NewString(prsQuery)
+	if q, err := b.bbql(filter); err != nil {
+		return nil, err
+	} else if len(q) > 0 {
+		vars.Q = optional.NewString(q)
 	}
 	prs, resp, err := b.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugPullrequestsGet(b.ctx, b.repoSlug, b.workspace, &vars)
 	if err != nil {
@@ -137,8 +541,9 @@ func (b *BitBucketSv) ListPullRequests(prsQuery string) (<-chan PullRequest, err
 	res := make(chan PullRequest)
 
 	go func() {
-		for pr := range Paginate[bitbucket.Pullrequest, bitbucket.PaginatedPullrequests](b.ctx, PaginatedPullrequests{&prs}) {
-			res <- BitbucketPullRequestWrapper{&pr, b}
+		for pr := range PaginateMax[bitbucket.Pullrequest, bitbucket.PaginatedPullrequests](b.ctx, PaginatedPullrequests{&prs}, filter.MaxPages) {
+			p := pr
+			res <- BitbucketPullRequestWrapper{&p, b}
 		}
 		close(res)
 	}()
@@ -151,44 +556,430 @@ type BitbucketPullRequestWrapper struct {
 	client *BitBucketSv
 }
 
-func (b BitbucketPullRequestWrapper) Merge() error {
-	//TODO implement me
-	panic("implement me")
+// bitbucketMergeStrategies maps the merge methods, Bitbucket can't rebase but fast-forwards the branches that are
+// already on top of their base
+var bitbucketMergeStrategies = map[string]string{
+	"":       "",
+	"merge":  "merge_commit",
+	"squash": "squash",
+	"rebase": "fast_forward",
+}
+
+// bitbucketMergePollInterval is the delay between two reads of the status of an asynchronous merge
+const bitbucketMergePollInterval = 2 * time.Second
+
+// bitbucketMergeTimeout bounds the wait of an asynchronous merge, it may still succeed once sv gave up
+const bitbucketMergeTimeout = 5 * time.Minute
+
+func (b BitbucketPullRequestWrapper) Merge(opts MergeOptions) error {
+	sv := b.client
+	strategy, ok := bitbucketMergeStrategies[opts.Method]
+	if !ok {
+		return fmt.Errorf("unknown merge method %s", opts.Method)
+	} else if opts.Auto {
+		return fmt.Errorf("cannot merge once the checks pass: %w", ErrNotSupported)
+	}
+
+	params := bitbucket.PullrequestMergeParameters{
+		Type_:             "pullrequest_merge_parameters",
+		Message:           opts.message(),
+		CloseSourceBranch: opts.DeleteBranch,
+		MergeStrategy:     strategy,
+	}
+	_, resp, err := sv.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdMergePost(sv.ctx, b.Id, sv.repoSlug, sv.workspace,
+		&bitbucket.PullrequestsApiRepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdMergePostOpts{Body: optional.NewInterface(params)})
+	if resp != nil && resp.StatusCode == 202 {
+		// The merge goes on in the background, the body is empty and the task to poll is in the Location header
+		return b.waitMerge(resp.Header.Get("Location"))
+	} else if err != nil {
+		return err
+	} else if resp.StatusCode != 200 {
+		return fmt.Errorf("cannot merge pr %d, status code = %d", b.Id, resp.StatusCode)
+	}
+	return nil
 }
 
+// waitMerge polls the merge task until it's done, the merge failing unless the task succeeded
+func (b BitbucketPullRequestWrapper) waitMerge(location string) error {
+	sv := b.client
+	taskId := location[strings.LastIndex(location, "/")+1:]
+	if taskId == "" {
+		return fmt.Errorf("cannot follow the merge of pr %d, no task in '%s'", b.Id, location)
+	}
+
+	timeout := time.After(bitbucketMergeTimeout)
+	for {
+		status, _, err := sv.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdMergeTaskStatusTaskIdGet(sv.ctx, b.Id, sv.repoSlug, taskId, sv.workspace)
+		if err != nil {
+			return fmt.Errorf("the merge of pr %d failed: %w", b.Id, err)
+		}
+		pterm.Debug.Printfln("merge task %s of pr %d: %s", taskId, b.Id, status.TaskStatus)
+		switch status.TaskStatus {
+		case "PENDING":
+		case "SUCCESS":
+			return nil
+		default:
+			return fmt.Errorf("the merge of pr %d failed, its task ended with status '%s'", b.Id, status.TaskStatus)
+		}
+		select {
+		case <-sv.ctx.Done():
+			return sv.ctx.Err()
+		case <-timeout:
+			return fmt.Errorf("the merge of pr %d is still pending after %v, see %s", b.Id, bitbucketMergeTimeout, location)
+		case <-time.After(bitbucketMergePollInterval):
+		}
+	}
+}
+
+func (b BitbucketPullRequestWrapper) Close() error {
+	sv := b.client
+	if _, resp, err := sv.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdDeclinePost(sv.ctx, b.Id, sv.repoSlug, sv.workspace); err != nil {
+		return err
+	} else if resp.StatusCode != 200 {
+		return fmt.Errorf("cannot decline pr %d, status code = %d", b.Id, resp.StatusCode)
+	}
+	return nil
+}
+
+// Reopen fails, a declined pull request can't be opened again on Bitbucket
+func (b BitbucketPullRequestWrapper) Reopen() error {
+	return fmt.Errorf("cannot reopen a declined pull request: %w", ErrNotSupported)
+}
+
+// Edit updates the pull request, its reviewers are rewritten as a whole. Bitbucket has no labels nor assignees.
+func (b BitbucketPullRequestWrapper) Edit(args EditPullRequestArgs) error {
+	sv := b.client
+	if len(args.AddLabels) > 0 || len(args.RemoveLabels) > 0 {
+		pterm.Warning.Println("Bitbucket doesn't support labels, ignoring them")
+	}
+	if len(args.AddAssignees) > 0 || len(args.RemoveAssignees) > 0 {
+		pterm.Warning.Println("Bitbucket doesn't support assignees, ignoring them")
+	}
+
+	body := bitbucket.Pullrequest{Type_: "pullrequest", Title: b.Title}
+	if args.Title.IsSet() {
+		body.Title = args.Title.Value()
+	}
+	if args.Description.IsSet() {
+		body.Summary = map[string]interface{}{"raw": args.Description.Value()}
+	}
+	if args.BaseBranch.IsSet() {
+		body.Destination = &bitbucket.PullrequestEndpoint{Branch: map[string]interface{}{"name": args.BaseBranch.Value()}}
+	}
+
+	if len(args.AddReviewers) > 0 || len(args.RemoveReviewers) > 0 {
+		added, err := sv.resolveReviewers(args.AddReviewers, false)
+		if err != nil {
+			return err
+		}
+		removed, err := sv.resolveReviewers(args.RemoveReviewers, false)
+		if err != nil {
+			return err
+		}
+		skip := make(map[string]bool)
+		for _, r := range removed {
+			skip[r.Uuid] = true
+		}
+		reviewers := append(append(make([]bitbucket.Account, 0), b.Reviewers...), added...)
+		for _, r := range reviewers {
+			if !skip[r.Uuid] {
+				skip[r.Uuid] = true
+				body.Reviewers = append(body.Reviewers, bitbucket.Account{Type_: "user", Uuid: r.Uuid})
+			}
+		}
+		// An empty list isn't sent, leaving the reviewers as they are
+		if len(body.Reviewers) == 0 {
+			return fmt.Errorf("cannot remove every reviewer: %w", ErrNotSupported)
+		}
+	}
+
+	if _, resp, err := sv.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdPut(sv.ctx, b.Id, sv.repoSlug, sv.workspace,
+		&bitbucket.PullrequestsApiRepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdPutOpts{Body: optional.NewInterface(body)}); err != nil {
+		return err
+	} else if resp.StatusCode != 200 {
+		return fmt.Errorf("cannot update pr %d, status code = %d", b.Id, resp.StatusCode)
+	}
+	return nil
+}
+
+func (b BitbucketPullRequestWrapper) IsDraft() bool {
+	return b.Draft
+}
+
+// SetDraft updates the draft flag alone, through a raw body since the generated model leaves out a false one
+func (b BitbucketPullRequestWrapper) SetDraft(draft bool) error {
+	sv := b.client
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d", sv.workspace, sv.repoSlug, b.Id)
+	body := map[string]interface{}{"type": "pullrequest", "title": b.Title, "draft": draft}
+	if _, err := sv.rest.put(sv.ctx, path, body, nil); err != nil {
+		return fmt.Errorf("cannot update pr %d: %w", b.Id, err)
+	}
+	return nil
+}
+
+// StartReview opens a local review: Bitbucket has no server side pending reviews so
+// we keep it in the client until it gets approved, rejected, closed or canceled.
 func (b BitbucketPullRequestWrapper) StartReview() (Review, error) {
-	//TODO implement me
-	panic("implement me")
+	if rev, err := b.GetPendingReview(); err != nil || rev != nil {
+		return rev, err
+	}
+
+	user, _, err := b.client.client.UsersApi.UserGet(b.client.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rev := &BitbucketPendingReview{
+		pr:        b,
+		author:    user.DisplayName,
+		createdOn: time.Now(),
+	}
+	b.client.pendingReviews[b.Id] = rev
+
+	return rev, nil
 }
 
 func (b BitbucketPullRequestWrapper) GetPendingReview() (Review, error) {
-	//TODO implement me
-	panic("implement me")
+	if rev, ok := b.client.pendingReviews[b.Id]; ok {
+		return rev, nil
+	}
+	// No error but no reviews
+	return nil, nil
+}
+
+func (b BitbucketPullRequestWrapper) postComment(comment bitbucket.PullrequestComment) (Comment, error) {
+	sv := b.client
+	if res, resp, err := sv.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdCommentsPost(sv.ctx, b.Id, sv.repoSlug, sv.workspace, comment); err != nil {
+		return nil, err
+	} else if resp.StatusCode != 201 && resp.StatusCode != 200 {
+		return nil, fmt.Errorf("cannot add comment to pr %d, status code = %d", b.Id, resp.StatusCode)
+	} else {
+		return toBitbucketComment(res), nil
+	}
 }
 
 func (b BitbucketPullRequestWrapper) CreateComment(path string, commitId string, line int, isNew bool, body string) (Comment, error) {
-	//TODO implement me
-	panic("implement me")
+	inline := map[string]interface{}{
+		"path": path,
+	}
+	if isNew {
+		inline["to"] = line
+	} else {
+		inline["from"] = line
+	}
+
+	return b.postComment(bitbucket.PullrequestComment{
+		Type_:   "pullrequest_comment",
+		Content: map[string]interface{}{"raw": body},
+		Inline:  inline,
+	})
 }
 
 func (b BitbucketPullRequestWrapper) GetLastCommitId() string {
-	//TODO implement me
-	panic("implement me")
+	if commit, ok := b.Source.Commit.(map[string]interface{}); ok {
+		if hash, ok := commit["hash"].(string); ok {
+			return hash
+		}
+	}
+	return ""
 }
 
 func (b BitbucketPullRequestWrapper) ReplyToComment(comment Comment, replyText string) (Comment, error) {
-	//TODO implement me
-	panic("implement me")
+	if c, ok := comment.(BitbucketComment); ok {
+		return b.postComment(bitbucket.PullrequestComment{
+			Type_:   "pullrequest_comment",
+			Content: map[string]interface{}{"raw": replyText},
+			Parent:  &bitbucket.Comment{Id: c.Id},
+		})
+	} else {
+		return nil, fmt.Errorf("illegal argument: not a bitbucket comment")
+	}
 }
 
 func (b BitbucketPullRequestWrapper) GetReviews() ([]Review, error) {
-	//TODO implement me
-	panic("implement me")
+	result := make([]Review, 0)
+
+	for _, p := range b.Participants {
+		if state := participantState(p); state != "" {
+			result = append(result, BitbucketReview{p, state})
+		}
+	}
+
+	return result, nil
+}
+
+func participantState(p bitbucket.Participant) string {
+	switch {
+	case p.State == "approved" || p.Approved:
+		return "APPROVED"
+	case p.State == "changes_requested":
+		return "CHANGES_REQUESTED"
+	case p.Role == "PARTICIPANT":
+		return "COMMENTED"
+	default:
+		return ""
+	}
 }
 
 func (b BitbucketPullRequestWrapper) GetChecks() ([]Check, error) {
-	//TODO implement me
-	panic("implement me")
+	sv := b.client
+
+	statuses, _, err := sv.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdStatusesGet(sv.ctx, b.Id, sv.repoSlug, sv.workspace, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Check, 0)
+	for st := range Paginate[bitbucket.Commitstatus, bitbucket.PaginatedCommitstatuses](sv.ctx, PaginatedCommitstatuses{&statuses}) {
+		result = append(result, BitbucketCheck{st})
+	}
+
+	return result, nil
+}
+
+type BitbucketCheck struct {
+	bitbucket.Commitstatus
+}
+
+func (b BitbucketCheck) GetName() string {
+	if b.Name != "" {
+		return b.Name
+	}
+	return b.Key
+}
+
+func (b BitbucketCheck) GetStatus() string {
+	return b.State
+}
+
+func (b BitbucketCheck) GetUrl() string {
+	return b.Url
+}
+
+// BitbucketReview is the review of another participant, built upon its participation status
+type BitbucketReview struct {
+	bitbucket.Participant
+	state string
+}
+
+func (b BitbucketReview) GetId() string {
+	return ""
+}
+
+func (b BitbucketReview) GetState() string {
+	return b.state
+}
+
+func (b BitbucketReview) GetAuthor() string {
+	if b.User != nil {
+		return b.User.DisplayName
+	}
+	return ""
+}
+
+func (b BitbucketReview) GetSubmitedAt() time.Time {
+	return b.ParticipatedOn
+}
+
+func (b BitbucketReview) Dismiss() error {
+	return errors.New("cannot operate on other's reviews")
+}
+
+func (b BitbucketReview) Close(comment *string) error {
+	return errors.New("cannot operate on other's reviews")
+}
+
+func (b BitbucketReview) Approve(comment *string) error {
+	return errors.New("cannot operate on other's reviews")
+}
+
+func (b BitbucketReview) RequestChanges(comment *string) error {
+	return errors.New("cannot operate on other's reviews")
+}
+
+func (b BitbucketReview) Cancel() error {
+	return errors.New("cannot operate on other's reviews")
+}
+
+// BitbucketPendingReview is the review of the current user, started with StartReview
+type BitbucketPendingReview struct {
+	pr        BitbucketPullRequestWrapper
+	author    string
+	createdOn time.Time
+}
+
+func (b *BitbucketPendingReview) GetId() string {
+	return fmt.Sprintf("%d", b.pr.Id)
+}
+
+func (b *BitbucketPendingReview) GetState() string {
+	return "PENDING"
+}
+
+func (b *BitbucketPendingReview) GetAuthor() string {
+	return b.author
+}
+
+func (b *BitbucketPendingReview) GetSubmitedAt() time.Time {
+	return b.createdOn
+}
+
+func (b *BitbucketPendingReview) addComment(comment *string) error {
+	if comment == nil || strings.TrimSpace(*comment) == "" {
+		return nil
+	}
+	_, err := b.pr.postComment(bitbucket.PullrequestComment{
+		Type_:   "pullrequest_comment",
+		Content: map[string]interface{}{"raw": *comment},
+	})
+	return err
+}
+
+// Dismiss withdraws the approval or the change request of the user, Bitbucket answers 404 for the one that isn't set
+func (b *BitbucketPendingReview) Dismiss() error {
+	sv := b.pr.client
+	if resp, err := sv.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdApproveDelete(sv.ctx, b.pr.Id, sv.repoSlug, sv.workspace); err != nil && !isNotFound(resp) {
+		return err
+	} else if resp, err := sv.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdRequestChangesDelete(sv.ctx, b.pr.Id, sv.repoSlug, sv.workspace); err != nil && !isNotFound(resp) {
+		return err
+	}
+	return b.Cancel()
+}
+
+func isNotFound(resp *http.Response) bool {
+	return resp != nil && resp.StatusCode == http.StatusNotFound
+}
+
+func (b *BitbucketPendingReview) Close(comment *string) error {
+	if err := b.addComment(comment); err != nil {
+		return err
+	}
+	return b.Cancel()
+}
+
+func (b *BitbucketPendingReview) Approve(comment *string) error {
+	sv := b.pr.client
+	if err := b.addComment(comment); err != nil {
+		return err
+	} else if _, _, err := sv.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdApprovePost(sv.ctx, b.pr.Id, sv.repoSlug, sv.workspace); err != nil {
+		return err
+	}
+	return b.Cancel()
+}
+
+func (b *BitbucketPendingReview) RequestChanges(comment *string) error {
+	sv := b.pr.client
+	if err := b.addComment(comment); err != nil {
+		return err
+	} else if _, _, err := sv.client.PullrequestsApi.RepositoriesWorkspaceRepoSlugPullrequestsPullRequestIdRequestChangesPost(sv.ctx, b.pr.Id, sv.repoSlug, sv.workspace); err != nil {
+		return err
+	}
+	return b.Cancel()
+}
+
+func (b *BitbucketPendingReview) Cancel() error {
+	delete(b.pr.client.pendingReviews, b.pr.Id)
+	return nil
 }
 
 func (b BitbucketPullRequestWrapper) GetBase() Branch {
@@ -214,15 +1005,15 @@ func (b BitbucketPullRequestWrapper) GetCommentsByLine() ([]Comment, map[string]
 		if comment.Deleted {
 			continue
 		}
-		cmt := BitbucketComment{&bitbucket.Comment{
-			Id:        comment.Id,
-			CreatedOn: comment.CreatedOn,
-			Content:   comment.Content,
-			User:      comment.User,
-			Parent:    comment.Parent,
-		}}
+		cmt := toBitbucketComment(comment)
 		if inline, ok := comment.Inline.(map[string]interface{}); ok {
-			to := int64(inline["to"].(float64))
+			// Same convention as GitHub : lines on the new side are negative
+			var to int64
+			if n, ok := inline["to"].(float64); ok {
+				to = -int64(n)
+			} else if n, ok := inline["from"].(float64); ok {
+				to = int64(n)
+			}
 			path := inline["path"].(string)
 
 			commentsByPath, hasPath := commentMap[path]
@@ -248,6 +1039,16 @@ type BitbucketComment struct {
 	*bitbucket.Comment
 }
 
+func toBitbucketComment(comment bitbucket.PullrequestComment) BitbucketComment {
+	return BitbucketComment{&bitbucket.Comment{
+		Id:        comment.Id,
+		CreatedOn: comment.CreatedOn,
+		Content:   comment.Content,
+		User:      comment.User,
+		Parent:    comment.Parent,
+	}}
+}
+
 func (b BitbucketComment) GetReactions() Reactions {
 	return make(Reactions)
 }
@@ -310,6 +1111,22 @@ func (b BitbucketPullRequestWrapper) GetDiff() ([]*gitdiff.File, error) {
 	return files, nil
 }
 
+// GetFileContent reads the file from the local repository, which needs the commits of the pull request, Bitbucket
+// only giving abbreviated hashes
+func (b BitbucketPullRequestWrapper) GetFileContent(path string, isNew bool) (string, error) {
+	base := ""
+	if b.Destination != nil {
+		if commit, ok := b.Destination.Commit.(map[string]interface{}); ok {
+			base, _ = commit["hash"].(string)
+		}
+	}
+	head := b.GetLastCommitId()
+	if base == "" || head == "" {
+		return "", fmt.Errorf("pull request %d has no commits", b.Id)
+	}
+	return fileFromLocalRepo(b.client.localRepo, base, head, path, isNew)
+}
+
 func (b BitbucketPullRequestWrapper) GetState() string {
 	return b.State
 }
@@ -338,6 +1155,26 @@ func (b BitbucketPullRequestWrapper) GetTitle() string {
 	return b.Title
 }
 
+func (b BitbucketPullRequestWrapper) GetDescription() string {
+	if summary, ok := b.Summary.(map[string]interface{}); ok {
+		if raw, ok := summary["raw"].(string); ok {
+			return raw
+		}
+	}
+	return ""
+}
+
+func (b BitbucketPullRequestWrapper) GetUrl() string {
+	if links, ok := b.Links.(map[string]interface{}); ok {
+		if html, ok := links["html"].(map[string]interface{}); ok {
+			if href, ok := html["href"].(string); ok {
+				return href
+			}
+		}
+	}
+	return ""
+}
+
 func (b BitbucketPullRequestWrapper) GetBranch() Branch {
 	data := b.Source.Branch.(map[string]interface{})
 	return BitBucketBranchWrapper{&data}
@@ -390,3 +1227,63 @@ func (p PaginatedPullRequestComments) GetPages() int32 {
 func (p PaginatedPullRequestComments) GetValues() []bitbucket.PullrequestComment {
 	return p.Values
 }
+
+type PaginatedCommitstatuses struct {
+	*bitbucket.PaginatedCommitstatuses
+}
+
+func (p PaginatedCommitstatuses) GetContainer() *bitbucket.PaginatedCommitstatuses {
+	return p.PaginatedCommitstatuses
+}
+
+func (p PaginatedCommitstatuses) GetNext() string {
+	return p.Next
+}
+
+func (p PaginatedCommitstatuses) GetPages() int32 {
+	return p.Size
+}
+
+func (p PaginatedCommitstatuses) GetValues() []bitbucket.Commitstatus {
+	return p.Values
+}
+
+type PaginatedWorkspaceMemberships struct {
+	*bitbucket.PaginatedWorkspaceMemberships
+}
+
+func (p PaginatedWorkspaceMemberships) GetContainer() *bitbucket.PaginatedWorkspaceMemberships {
+	return p.PaginatedWorkspaceMemberships
+}
+
+func (p PaginatedWorkspaceMemberships) GetNext() string {
+	return p.Next
+}
+
+func (p PaginatedWorkspaceMemberships) GetPages() int32 {
+	return p.Size
+}
+
+func (p PaginatedWorkspaceMemberships) GetValues() []bitbucket.WorkspaceMembership {
+	return p.Values
+}
+
+type PaginatedUsers struct {
+	*bitbucket.PaginatedUsers
+}
+
+func (p PaginatedUsers) GetContainer() *bitbucket.PaginatedUsers {
+	return p.PaginatedUsers
+}
+
+func (p PaginatedUsers) GetNext() string {
+	return p.Next
+}
+
+func (p PaginatedUsers) GetPages() int32 {
+	return p.Size
+}
+
+func (p PaginatedUsers) GetValues() []bitbucket.User {
+	return p.Values
+}
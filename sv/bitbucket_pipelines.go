@@ -0,0 +1,260 @@
+package sv
+
+import (
+	"fmt"
+	"github.com/antihax/optional"
+	"github.com/vballestra/sv/bitbucket"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// The pipeline ids can either be the uuids or the build numbers, Bitbucket accepts both
+
+// ListPipelines returns the last count pipelines of the repository, the most recent first
+func (b *BitBucketSv) ListPipelines(count int32) ([]bitbucket.Pipeline, error) {
+	pipelines, resp, err := b.client.PipelinesApi.GetPipelinesForRepository(b.ctx, b.workspace, b.repoSlug, &bitbucket.PipelinesApiGetPipelinesForRepositoryOpts{
+		Sort:    optional.NewString("-created_on"),
+		Pagelen: optional.NewInt32(count),
+	})
+	if err != nil {
+		return nil, err
+	} else if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("cannot list pipelines, status code = %d", resp.StatusCode)
+	}
+	return pipelines.Values, nil
+}
+
+func (b *BitBucketSv) GetPipeline(id string) (bitbucket.Pipeline, error) {
+	pipeline, resp, err := b.client.PipelinesApi.GetPipelineForRepository(b.ctx, b.workspace, b.repoSlug, id)
+	if err != nil {
+		return pipeline, err
+	} else if resp.StatusCode != 200 {
+		return pipeline, fmt.Errorf("cannot read pipeline %s, status code = %d", id, resp.StatusCode)
+	}
+	return pipeline, nil
+}
+
+// RunPipeline triggers the pipeline of a branch, the custom one named pattern if not empty
+func (b *BitBucketSv) RunPipeline(branch string, pattern string) (bitbucket.Pipeline, error) {
+	target := &bitbucket.PipelineTarget{Type_: "pipeline_ref_target", RefType: "branch", RefName: branch}
+	if pattern != "" {
+		target.Selector = &bitbucket.PipelineSelector{Type_: "custom", Pattern: pattern}
+	}
+
+	pipeline, resp, err := b.client.PipelinesApi.CreatePipelineForRepository(b.ctx, b.workspace, b.repoSlug, bitbucket.Pipeline{
+		Type_:  "pipeline",
+		Target: target,
+	})
+	if err != nil {
+		return pipeline, err
+	} else if resp.StatusCode != 201 {
+		return pipeline, fmt.Errorf("cannot run pipeline on %s, status code = %d", branch, resp.StatusCode)
+	}
+	return pipeline, nil
+}
+
+// RerunPipeline runs a new pipeline with the target (commit, ref and selector) of the given one
+func (b *BitBucketSv) RerunPipeline(id string) (bitbucket.Pipeline, error) {
+	previous, err := b.GetPipeline(id)
+	if err != nil {
+		return previous, err
+	}
+
+	pipeline, resp, err := b.client.PipelinesApi.CreatePipelineForRepository(b.ctx, b.workspace, b.repoSlug, bitbucket.Pipeline{
+		Type_:  "pipeline",
+		Target: previous.Target,
+	})
+	if err != nil {
+		return pipeline, err
+	} else if resp.StatusCode != 201 {
+		return pipeline, fmt.Errorf("cannot rerun pipeline %s, status code = %d", id, resp.StatusCode)
+	}
+	return pipeline, nil
+}
+
+func (b *BitBucketSv) StopPipeline(id string) error {
+	if resp, err := b.client.PipelinesApi.StopPipeline(b.ctx, b.workspace, b.repoSlug, id); err != nil {
+		return err
+	} else if resp.StatusCode != 204 {
+		return fmt.Errorf("cannot stop pipeline %s, status code = %d", id, resp.StatusCode)
+	}
+	return nil
+}
+
+func (b *BitBucketSv) GetPipelineSteps(id string) ([]bitbucket.PipelineStep, error) {
+	steps, resp, err := b.client.PipelinesApi.GetPipelineStepsForRepository(b.ctx, b.workspace, b.repoSlug, id)
+	if err != nil {
+		return nil, err
+	} else if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("cannot read steps of pipeline %s, status code = %d", id, resp.StatusCode)
+	}
+	// No pagination here, it's polled by the watchers and a pipeline rarely has more than a page of steps
+	return steps.Values, nil
+}
+
+// GetPipelineStepLog returns the log of a step from the offset, it's empty when there's nothing new
+func (b *BitBucketSv) GetPipelineStepLog(pipelineId string, stepUuid string, offset int) ([]byte, error) {
+	opts := &bitbucket.PipelinesApiGetPipelineStepLogForRepositoryOpts{}
+	if offset > 0 {
+		opts.Range_ = optional.NewString(fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	log, resp, err := b.client.PipelinesApi.GetPipelineStepLogForRepository(b.ctx, b.workspace, b.repoSlug, pipelineId, stepUuid, opts)
+	if resp != nil && (resp.StatusCode == http.StatusRequestedRangeNotSatisfiable || resp.StatusCode == http.StatusNotFound) {
+		// No new content, or the step hasn't started yet
+		return []byte{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+// BitbucketPipelineStatus is the result of a completed pipeline, its state otherwise
+func BitbucketPipelineStatus(state *bitbucket.PipelineState) string {
+	if state == nil {
+		return "UNKNOWN"
+	} else if state.Result != nil && state.Result.Name != "" {
+		return state.Result.Name
+	} else if state.Stage != nil && state.Stage.Name != "" {
+		return state.Stage.Name
+	}
+	return state.Name
+}
+
+// BitbucketPipelineStepStatus is the result of a completed step, its state otherwise
+func BitbucketPipelineStepStatus(state *bitbucket.PipelineStepState) string {
+	if state == nil {
+		return "UNKNOWN"
+	} else if state.Result != nil && state.Result.Name != "" {
+		return state.Result.Name
+	}
+	return state.Name
+}
+
+var bitbucketConclusions = map[string]string{
+	"SUCCESSFUL": "SUCCESS",
+	"FAILED":     "FAILURE",
+	"ERROR":      "FAILURE",
+	"STOPPED":    "CANCELLED",
+	"EXPIRED":    "CANCELLED",
+	"NOT_RUN":    "SKIPPED",
+}
+
+func (b BitbucketCheck) GetConclusion() string {
+	return bitbucketConclusions[b.State]
+}
+
+func (b BitbucketCheck) GetStartedAt() time.Time {
+	return b.CreatedOn
+}
+
+func (b BitbucketCheck) GetCompletedAt() time.Time {
+	if b.GetConclusion() == "" {
+		return time.Time{}
+	}
+	return b.UpdatedOn
+}
+
+func (b BitbucketCheck) CanRerun() bool {
+	return false
+}
+
+func (b BitbucketCheck) HasLog() bool {
+	return false
+}
+
+// BitbucketStepCheck is a step of a pipeline reported in the statuses of a pull request
+type BitbucketStepCheck struct {
+	bitbucket.PipelineStep
+	pipeline string
+	url      string
+}
+
+func (b BitbucketStepCheck) GetName() string {
+	return fmt.Sprintf("#%s/%s", b.pipeline, b.Name)
+}
+
+func (b BitbucketStepCheck) GetStatus() string {
+	return BitbucketPipelineStepStatus(b.State)
+}
+
+func (b BitbucketStepCheck) GetUrl() string {
+	return b.url
+}
+
+func (b BitbucketStepCheck) GetConclusion() string {
+	if b.State == nil || b.State.Name != "COMPLETED" {
+		return ""
+	}
+	return bitbucketConclusions[b.GetStatus()]
+}
+
+func (b BitbucketStepCheck) GetStartedAt() time.Time {
+	return b.StartedOn
+}
+
+func (b BitbucketStepCheck) GetCompletedAt() time.Time {
+	return b.CompletedOn
+}
+
+func (b BitbucketStepCheck) CanRerun() bool {
+	return true
+}
+
+func (b BitbucketStepCheck) HasLog() bool {
+	return true
+}
+
+// pipelineStatusUrl matches the url of the statuses created by the pipelines, .../addon/pipelines/home#!/results/42
+var pipelineStatusUrl = regexp.MustCompile(`/pipelines/.*results/(\d+)`)
+
+// GetCheckRuns replaces the statuses of the pipelines by their steps, the other statuses are kept as is
+func (b BitbucketPullRequestWrapper) GetCheckRuns() ([]CheckRun, error) {
+	checks, err := b.GetChecks()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]CheckRun, 0, len(checks))
+	for _, c := range checks {
+		status := c.(BitbucketCheck)
+		if m := pipelineStatusUrl.FindStringSubmatch(status.Url); m == nil {
+			result = append(result, status)
+		} else if steps, err := b.client.GetPipelineSteps(m[1]); err != nil {
+			return nil, err
+		} else {
+			for _, step := range steps {
+				result = append(result, BitbucketStepCheck{step, m[1], status.Url})
+			}
+		}
+	}
+	return result, nil
+}
+
+// RerunChecks runs again the pipelines of the steps, Bitbucket can't rerun a single step
+func (b BitbucketPullRequestWrapper) RerunChecks(checks []CheckRun) error {
+	done := make(map[string]bool)
+	for _, c := range checks {
+		step, ok := c.(BitbucketStepCheck)
+		if !ok {
+			return fmt.Errorf("cannot rerun %s: %w", c.GetName(), ErrNotSupported)
+		}
+		if done[step.pipeline] {
+			continue
+		}
+		done[step.pipeline] = true
+		if _, err := b.client.RerunPipeline(step.pipeline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b BitbucketPullRequestWrapper) GetCheckLog(check CheckRun) ([]byte, error) {
+	if step, ok := check.(BitbucketStepCheck); !ok {
+		return nil, ErrNotSupported
+	} else {
+		return b.client.GetPipelineStepLog(step.pipeline, step.Uuid, 0)
+	}
+}
@@ -0,0 +1,301 @@
+package sv
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pterm/pterm"
+	"github.com/vballestra/sv/bitbucket"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotCached is returned offline for the requests whose response isn't in the cache
+var ErrNotCached = errors.New("not in the cache")
+
+// Cache keeps the api responses on disk. A response is served as is until it expires according to its Cache-Control
+// or Expires headers, then it is revalidated with its ETag or its Last-Modified date. The stale responses are served
+// when the network fails, and all the cached responses are when Offline is set.
+type Cache struct {
+	dir     string
+	Offline bool
+
+	mutex sync.Mutex
+	// dirty is set once a write went through, the responses are then always revalidated
+	dirty bool
+}
+
+// NewCache creates a cache storing its responses under dir, created when needed
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// DefaultCacheDir is the sv directory under the user cache dir, $XDG_CACHE_HOME or ~/.cache on Linux
+func DefaultCacheDir() (string, error) {
+	if dir, err := os.UserCacheDir(); err != nil {
+		return "", err
+	} else {
+		return filepath.Join(dir, "sv"), nil
+	}
+}
+
+// Clear removes all the cached responses
+func (c *Cache) Clear() error {
+	return os.RemoveAll(c.dir)
+}
+
+// cacheEntry is a response as stored on disk
+type cacheEntry struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// key identifies the request by its method, its url, the headers changing the response and its body, which is read
+// and put back
+func (c *Cache) key(req *http.Request) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", req.Method, req.URL.String())
+	for _, name := range []string{"Accept", "Authorization", "Private-Token"} {
+		fmt.Fprintf(h, "%s: %s\n", name, req.Header.Get(name))
+	}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		h.Write(body)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+func (c *Cache) load(key string) *cacheEntry {
+	b, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		pterm.Debug.Printfln("ignoring the corrupted cache entry %s: %v", key, err)
+		return nil
+	}
+	return &e
+}
+
+// store writes the entry in a temporary file renamed afterwards, so that concurrent readers never see half of it
+func (c *Cache) store(key string, e *cacheEntry) {
+	path := c.path(key)
+	err := func() error {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return err
+		}
+		f, err := os.CreateTemp(filepath.Dir(path), key+".*")
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(b); err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+			return err
+		} else if err := f.Close(); err != nil {
+			_ = os.Remove(f.Name())
+			return err
+		}
+		return os.Rename(f.Name(), path)
+	}()
+	if err != nil {
+		pterm.Debug.Printfln("cannot cache %s: %v", key, err)
+	}
+}
+
+func (c *Cache) isDirty() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.dirty
+}
+
+func (c *Cache) setDirty() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.dirty = true
+}
+
+// Transport makes the requests going through next use the cache
+func (c *Cache) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &cacheTransport{cache: c, next: next}
+}
+
+type cacheTransport struct {
+	cache *Cache
+	next  http.RoundTripper
+}
+
+// isGraphQLQuery tells whether the body is a GraphQL query, whose response can be cached, and not a mutation
+func isGraphQLQuery(body []byte) bool {
+	var payload struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return false
+	}
+	query := strings.TrimSpace(payload.Query)
+	return strings.HasPrefix(query, "query") || strings.HasPrefix(query, "{")
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.roundTrip(req, true)
+	} else if req.Method == http.MethodPost && req.Body != nil && req.GetBody != nil {
+		// The GraphQL queries are posted, their responses have no validator and are only served offline
+		if body, err := req.GetBody(); err == nil {
+			b, err := io.ReadAll(body)
+			_ = body.Close()
+			if err == nil && isGraphQLQuery(b) {
+				return t.roundTrip(req, false)
+			}
+		}
+	}
+
+	if t.cache.Offline {
+		return nil, fmt.Errorf("%s %s: cannot write offline", req.Method, req.URL.Redacted())
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err == nil && resp.StatusCode < 400 {
+		t.cache.setDirty()
+	}
+	return resp, err
+}
+
+// roundTrip serves the request from the cache when possible, revalidating the cached response when it is allowed to
+func (t *cacheTransport) roundTrip(req *http.Request, revalidate bool) (*http.Response, error) {
+	key, err := t.cache.key(req)
+	if err != nil {
+		return nil, err
+	}
+	cached := t.cache.load(key)
+
+	if cached != nil && t.cache.Offline {
+		return cached.response(req), nil
+	} else if t.cache.Offline {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Redacted(), ErrNotCached)
+	} else if cached != nil && revalidate && !t.cache.isDirty() && time.Now().Before(bitbucket.CacheExpires(cached.response(req))) {
+		return cached.response(req), nil
+	}
+
+	origin := req
+	if cached != nil && revalidate {
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req = req.Clone(req.Context())
+			req.Header.Set("If-None-Match", etag)
+		} else if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			req = req.Clone(req.Context())
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		if cached != nil {
+			pterm.Debug.Printfln("%s %s failed, serving the cached response: %v", req.Method, req.URL.Redacted(), err)
+			return cached.response(origin), nil
+		}
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		_ = resp.Body.Close()
+		// The validation refreshes the freshness of the cached response
+		for _, name := range []string{"Date", "Cache-Control", "Expires", "ETag", "Last-Modified"} {
+			if v := resp.Header.Values(name); len(v) > 0 {
+				cached.Header[name] = v
+			}
+		}
+		t.cache.store(key, cached)
+		return cached.response(origin), nil
+	} else if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	entry := &cacheEntry{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}
+	if !strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		t.cache.store(key, entry)
+	}
+	return entry.response(origin), nil
+}
+
+// transportWrapper is implemented by the providers whose api requests can go through another transport
+type transportWrapper interface {
+	wrapTransport(wrap func(http.RoundTripper) http.RoundTripper)
+}
+
+// CachedSv is a provider whose api responses go through a Cache
+type CachedSv struct {
+	Sv
+	cache *Cache
+}
+
+// NewCachedSv makes the api requests of the provider go through the cache, the providers that can't are returned
+// as is
+func NewCachedSv(s Sv, cache *Cache) Sv {
+	if p, ok := s.(transportWrapper); ok {
+		p.wrapTransport(cache.Transport)
+		return CachedSv{Sv: s, cache: cache}
+	}
+	return s
+}
+
+// Fetch doesn't reach the remote offline
+func (c CachedSv) Fetch(remote string) error {
+	if c.cache.Offline {
+		return nil
+	}
+	return c.Sv.Fetch(remote)
+}
+
+// Unwrap returns the provider behind the cache, s itself when it isn't cached
+func Unwrap(s Sv) Sv {
+	if c, ok := s.(CachedSv); ok {
+		return c.Sv
+	}
+	return s
+}
@@ -0,0 +1,168 @@
+package sv
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/pterm/pterm"
+	"regexp"
+	"strings"
+)
+
+// ErrDirtyWorktree is returned when a checkout would lose local changes
+var ErrDirtyWorktree = errors.New("the worktree has local changes")
+
+// pullRequestHead tells where the head of a pull request is fetched from
+type pullRequestHead struct {
+	id string
+	// remote is the local remote of the repository
+	remote string
+	// remoteUrl is the repository holding the head, the remote when empty
+	remoteUrl string
+	// ref is the reference to fetch, a branch or a pull request ref like refs/pull/12/head
+	ref string
+	// branch is the local branch to create or update
+	branch string
+}
+
+// trackingRef is where the fetched head is kept: the remote-tracking branch for the branches of the remote, a ref
+// of its own otherwise
+func (h pullRequestHead) trackingRef() plumbing.ReferenceName {
+	if h.remoteUrl == "" && strings.HasPrefix(h.ref, "refs/heads/") {
+		return plumbing.NewRemoteReferenceName(h.remote, strings.TrimPrefix(h.ref, "refs/heads/"))
+	}
+	return plumbing.ReferenceName("refs/sv/pulls/" + h.id)
+}
+
+// fetchedFrom is the url of the repository holding the head, or the name of the remote
+func (h pullRequestHead) fetchedFrom() string {
+	if h.remoteUrl != "" {
+		return h.remoteUrl
+	}
+	return h.remote
+}
+
+// forkBranch is the local branch of a pull request coming from another repository, so that it doesn't clash with
+// the branches of the origin
+func forkBranch(id interface{}, branch string) string {
+	return fmt.Sprintf("pr-%v-%s", id, branch)
+}
+
+// isDirty ignores the untracked files, as git checkout does
+func isDirty(wt *git.Worktree) (bool, error) {
+	status, err := wt.Status()
+	if err != nil {
+		return false, err
+	}
+	for _, s := range status {
+		if (s.Worktree != git.Unmodified && s.Worktree != git.Untracked) || (s.Staging != git.Unmodified && s.Staging != git.Untracked) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fetchHead fetches the head with the ssh agent, then with git when it fails (https remotes, no agent...)
+func fetchHead(rep *git.Repository, localRepo string, sshKeySelector *regexp.Regexp, head pullRequestHead) error {
+	spec := config.RefSpec(fmt.Sprintf("+%s:%s", head.ref, head.trackingRef()))
+
+	err := func() error {
+		ag, err := agentAuth(sshKeySelector)
+		if err != nil {
+			return err
+		}
+		remote, err := rep.Remote(head.remote)
+		if head.remoteUrl != "" {
+			remote, err = git.NewRemote(rep.Storer, &config.RemoteConfig{Name: "sv", URLs: []string{head.remoteUrl}}), nil
+		}
+		if err != nil {
+			return err
+		}
+		return remote.Fetch(&git.FetchOptions{RefSpecs: []config.RefSpec{spec}, Auth: ag})
+	}()
+
+	if err == nil || errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil
+	}
+	pterm.Debug.Printfln("cannot fetch %s with the ssh agent, trying git: %v", head.ref, err)
+
+	return execGitFetch(localRepo, head.fetchedFrom(), spec.String())
+}
+
+// checkoutHead fetches the head of a pull request, points the local branch to it and switches to it. Unless force
+// is set, the worktree must be clean and the local branch must not have commits missing from the head.
+func checkoutHead(localRepo string, sshKeySelector *regexp.Regexp, head pullRequestHead, force bool) error {
+	rep, err := git.PlainOpen(localRepo)
+	if err != nil {
+		return err
+	}
+	wt, err := rep.Worktree()
+	if err != nil {
+		return err
+	}
+	if !force {
+		if dirty, err := isDirty(wt); err != nil {
+			return err
+		} else if dirty {
+			return ErrDirtyWorktree
+		}
+	}
+
+	if err := fetchHead(rep, localRepo, sshKeySelector, head); err != nil {
+		return fmt.Errorf("cannot fetch %s: %w", head.ref, err)
+	}
+	fetched, err := rep.Reference(head.trackingRef(), true)
+	if err != nil {
+		return err
+	}
+
+	branchRef := plumbing.NewBranchReferenceName(head.branch)
+	if current, err := rep.Reference(branchRef, true); err == nil && current.Hash() != fetched.Hash() && !force {
+		// Only fast-forwards, the local commits would be lost otherwise
+		if currentCommit, err := rep.CommitObject(current.Hash()); err != nil {
+			return err
+		} else if fetchedCommit, err := rep.CommitObject(fetched.Hash()); err != nil {
+			return err
+		} else if isAncestor, err := currentCommit.IsAncestor(fetchedCommit); err != nil {
+			return err
+		} else if !isAncestor {
+			return fmt.Errorf("the branch %s has commits that are not in the pull request", head.branch)
+		}
+	}
+	if err := rep.Storer.SetReference(plumbing.NewHashReference(branchRef, fetched.Hash())); err != nil {
+		return err
+	}
+
+	// The pull request refs can't be tracked, they're updated by checking out the pull request again
+	if plumbing.ReferenceName(head.ref).IsBranch() {
+		if cfg, err := rep.Config(); err != nil {
+			return err
+		} else {
+			cfg.Branches[head.branch] = &config.Branch{Name: head.branch, Remote: head.fetchedFrom(), Merge: plumbing.ReferenceName(head.ref)}
+			if err := rep.SetConfig(cfg); err != nil {
+				return err
+			}
+		}
+	}
+
+	return wt.Checkout(&git.CheckoutOptions{Branch: branchRef, Force: force})
+}
+
+// forkRemoteUrl is the url of a fork, built from the one of the remote by replacing the repository path
+func forkRemoteUrl(localRepo string, remote string, originPath string, forkPath string) (string, error) {
+	rep, err := git.PlainOpen(localRepo)
+	if err != nil {
+		return "", err
+	}
+	origin, err := rep.Remote(remote)
+	if err != nil {
+		return "", err
+	}
+	originUrl := origin.Config().URLs[0]
+	if idx := strings.Index(strings.ToLower(originUrl), strings.ToLower(originPath)); idx >= 0 {
+		return originUrl[:idx] + forkPath + originUrl[idx+len(originPath):], nil
+	}
+	return "", fmt.Errorf("cannot find %s in the %s url %s", originPath, remote, originUrl)
+}
@@ -0,0 +1,46 @@
+package sv_test
+
+import (
+	"github.com/vballestra/sv/sv"
+	"github.com/vballestra/sv/sv/giteafake"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckoutPullRequestFromTheRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is missing")
+	}
+	// Without the ssh agent, sv fetches with git
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	dir := t.TempDir()
+	remote, local := filepath.Join(dir, "remote.git"), filepath.Join(dir, "local")
+	git(t, dir, "init", "--bare", remote)
+	git(t, dir, "init", "-b", "main", local)
+	git(t, local, "remote", "add", "upstream", remote)
+	git(t, local, "commit", "--allow-empty", "-m", "first")
+	git(t, local, "push", "upstream", "main", "main:feature")
+
+	srv := giteafake.NewServer("token", giteafake.User{Id: 1, Login: "me"})
+	t.Cleanup(srv.Close)
+	srv.AddRepository("me", "repo", "main")
+	repo := &giteafake.Repository{Name: "repo", FullName: giteaRepo, Owner: giteaMe()}
+	srv.AddPullRequest(giteaRepo, giteafake.PullRequest{Title: "feature", User: giteaMe(),
+		Head: &giteafake.BranchRef{Label: "feature", Ref: "feature", Repo: repo}, Base: giteaBranch("main")})
+	provider := sv.NewGiteaSv("token", srv.ApiUrl(), srv.Client(), local, ".*", "me", "repo")
+
+	if branch, err := provider.CheckoutPullRequest("upstream", "1", false); err != nil {
+		t.Fatal(err)
+	} else if branch != "feature" {
+		t.Errorf("checked out %s", branch)
+	}
+	if head, fetched := git(t, local, "rev-parse", "HEAD"), git(t, remote, "rev-parse", "feature"); head != fetched {
+		t.Errorf("the branch is on %s, not on %s", head, fetched)
+	}
+	if upstream := git(t, local, "config", "branch.feature.remote"); upstream != "upstream" {
+		t.Errorf("the upstream remote is %s", upstream)
+	}
+	git(t, local, "rev-parse", "refs/remotes/upstream/feature")
+}
@@ -1,9 +1,26 @@
 package sv
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"github.com/antihax/optional"
 	"github.com/bluekeyes/go-gitdiff/gitdiff"
+	"github.com/briandowns/spinner"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/pterm/pterm"
+	sshagent "github.com/xanzy/ssh-agent"
+	ssh2 "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"net"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -11,11 +28,16 @@ type PullRequest interface {
 	GetBranch() Branch
 	GetId() interface{}
 	GetTitle() string
+	GetDescription() string
+	// GetUrl is the address of the pull request on the web
+	GetUrl() string
 	GetAuthor() Author
 	GetState() string
 	GetCreatedOn() time.Time
 	GetCommentsByLine() ([]Comment, map[string]map[int64][]Comment, error)
 	GetDiff() ([]*gitdiff.File, error)
+	// GetFileContent reads the file in the head of the pull request when isNew, in its merge base otherwise
+	GetFileContent(path string, isNew bool) (string, error)
 	GetBase() Branch
 	GetChecks() ([]Check, error)
 	GetReviews() ([]Review, error)
@@ -24,7 +46,15 @@ type PullRequest interface {
 	GetLastCommitId() string
 	GetPendingReview() (Review, error)
 	StartReview() (Review, error)
-	Merge() error
+	Merge(opts MergeOptions) error
+	// Close closes the pull request without merging it, Bitbucket declines it
+	Close() error
+	Reopen() error
+	IsDraft() bool
+	// SetDraft converts the pull request to a draft, or marks it ready for review
+	SetDraft(draft bool) error
+	Edit(args EditPullRequestArgs) error
+	CheckRunner
 }
 
 type Comment interface {
@@ -49,6 +79,32 @@ type Check interface {
 	GetUrl() string
 }
 
+// CheckRun is a check with its timing. The conclusion is one of SUCCESS, FAILURE, CANCELLED, SKIPPED or NEUTRAL,
+// it's empty until the check is completed.
+type CheckRun interface {
+	Check
+	GetConclusion() string
+	GetStartedAt() time.Time
+	GetCompletedAt() time.Time
+	CanRerun() bool
+	HasLog() bool
+}
+
+// CheckRunner gives the check runs of the last commit of a pull request
+type CheckRunner interface {
+	GetCheckRuns() ([]CheckRun, error)
+	// RerunChecks reruns the given checks, grouping them the way the provider does (workflow, pipeline...)
+	RerunChecks(checks []CheckRun) error
+	GetCheckLog(check CheckRun) ([]byte, error)
+}
+
+// ErrNotSupported is returned by the operations the provider doesn't offer
+var ErrNotSupported = errors.New("not supported by this provider")
+
+func IsFailedCheck(check CheckRun) bool {
+	return check.GetConclusion() == "FAILURE" || check.GetConclusion() == "CANCELLED"
+}
+
 type Review interface {
 	GetId() string
 	GetState() string
@@ -74,13 +130,94 @@ type Branch interface {
 }
 
 type Sv interface {
-	ListPullRequests(query string) (<-chan PullRequest, error)
+	ListPullRequests(filter PullRequestFilter) (<-chan PullRequest, error)
 	GetPullRequest(id string) (PullRequest, error)
 	PullRequestStatus() (<-chan PullRequestStatus, error)
-	Fetch() error
+	// Fetch updates the branches of the remote in the local repository
+	Fetch(remote string) error
 	GetRepositoryFullName() string
 	CreatePullRequest(args CreatePullRequestArgs) (PullRequestStatus, error)
+	// ResolveTitleAndDescription returns the title and the description CreatePullRequest uses when they aren't given,
+	// the branches missing locally are read from the remote
+	ResolveTitleAndDescription(remote string, title optional.String, description optional.String, headBranch string, baseBranch string) (string, string, error)
+	GetDefaultBranch() (string, error)
+	// ListCollaborators returns the logins of the users who can review the pull requests
+	ListCollaborators() ([]string, error)
+	// ListLabels returns the names of the labels of the repository, none on Bitbucket
+	ListLabels() ([]string, error)
+	// CheckoutPullRequest switches to a local branch on the head of the pull request and returns its name, the
+	// branches of the repository are fetched from the remote
+	CheckoutPullRequest(remote string, id string, force bool) (string, error)
 	GetCurrentBranch() (string, error)
+	// PushBranch pushes the local branch to the remote when it lacks some of its commits and sets its upstream, it
+	// returns whether it pushed. With force the remote branch is overwritten when it has diverged, after a rebase,
+	// provided it's still on the commit last fetched.
+	PushBranch(remote string, branch string, force bool) (bool, error)
+}
+
+// PullRequestFilter holds the provider-neutral criteria of ListPullRequests, the empty values don't filter.
+// Author and Reviewer take a login, or @me for the current user.
+type PullRequestFilter struct {
+	// Query is given as is to the provider: BBQL on Bitbucket, search syntax on GitHub, text search on the others
+	Query    string
+	Author   string
+	Reviewer string
+	Label    string
+	// State is open (the default), closed (without being merged), merged or all
+	State        string
+	Base         string
+	Head         string
+	Draft        optional.Bool
+	UpdatedSince time.Time
+	// MaxPages bounds the number of pages fetched, 0 for no limit
+	MaxPages int
+}
+
+// PullRequestStates are the accepted values of PullRequestFilter.State
+var PullRequestStates = []string{"open", "closed", "merged", "all"}
+
+const CurrentUser = "@me"
+
+// MergeOptions tells how a pull request is merged, the zero value merges it the way sv always did
+type MergeOptions struct {
+	// Method is one of MergeMethods, empty for the default of the provider
+	Method string
+	// Title and Body are the message of the resulting commit, the provider builds it when empty
+	Title string
+	Body  string
+	// DeleteBranch deletes the head branch once merged
+	DeleteBranch bool
+	// Auto enables the merge of the pull request once its checks pass, instead of merging it now
+	Auto bool
+}
+
+// MergeMethods are the accepted values of MergeOptions.Method
+var MergeMethods = []string{"merge", "squash", "rebase"}
+
+// message joins the title and the body, for the providers taking a single commit message
+func (o MergeOptions) message() string {
+	if o.Body == "" {
+		return o.Title
+	}
+	return strings.TrimSpace(o.Title + "\n\n" + o.Body)
+}
+
+// SetMessage splits a commit message in its title, the first line, and its body
+func (o *MergeOptions) SetMessage(message string) {
+	title, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	o.Title, o.Body = strings.TrimSpace(title), strings.TrimSpace(body)
+}
+
+// draftTitlePrefixes are the title prefixes making a draft on the providers without a draft flag
+var draftTitlePrefixes = regexp.MustCompile(`(?i)^\s*(\[draft]|\(draft\)|draft:|\[wip]|wip:)\s*`)
+
+// draftTitle adds the prefix to the title of a draft, or removes the draft prefixes
+func draftTitle(title string, draft bool, prefix string) string {
+	title = draftTitlePrefixes.ReplaceAllString(title, "")
+	if draft {
+		return prefix + title
+	}
+	return title
 }
 
 type CreatePullRequestArgs struct {
@@ -91,6 +228,26 @@ type CreatePullRequestArgs struct {
 	Labels              []string
 	Reviewers           []string
 	CreateMissingLabels bool
+	DefaultReviewers    bool
+	// Draft opens the pull request as a draft, with a title prefix on GitLab and Gitea
+	Draft bool
+	// Remote is the local remote the branches are pushed to, the branches missing locally are read from it
+	Remote string
+}
+
+// EditPullRequestArgs are the changes made by PullRequest.Edit, the unset and empty fields are left as they are.
+// The users are given by their login.
+type EditPullRequestArgs struct {
+	Title               optional.String
+	Description         optional.String
+	BaseBranch          optional.String
+	AddLabels           []string
+	RemoveLabels        []string
+	AddReviewers        []string
+	RemoveReviewers     []string
+	AddAssignees        []string
+	RemoveAssignees     []string
+	CreateMissingLabels bool
 }
 
 type PullRequestStatus interface {
@@ -107,11 +264,13 @@ type PullRequestStatus interface {
 	IsMine() bool
 }
 
-func execGitFetch() error {
+// execGitFetch runs git fetch in dir, the current directory when empty
+func execGitFetch(dir string, args ...string) error {
 	if path, err := exec.LookPath("git"); err != nil {
 		return err
 	} else {
-		cmd := exec.Command(path, "fetch")
+		cmd := exec.Command(path, append([]string{"fetch"}, args...)...)
+		cmd.Dir = dir
 		// cmd.Stdin = os.Stdin
 		// cmd.Stdout = os.Stdout
 		if err = cmd.Start(); err != nil {
@@ -134,12 +293,301 @@ func execGitFetch() error {
 
 }
 
-func ForceFetch(repo Sv) error {
-	if err := repo.Fetch(); err == nil {
+func ForceFetch(repo Sv, remote string) error {
+	if err := repo.Fetch(remote); err == nil {
 		return nil
-	} else if err := execGitFetch(); err == nil {
+	} else if err := execGitFetch("", remote); err == nil {
 		return nil
 	} else {
 		return err
 	}
 }
+
+func getCurrentBranch(localRepo string) (string, error) {
+	if rep, err := git.PlainOpen(localRepo); err != nil {
+		return "", err
+	} else if hd, err := rep.Head(); err != nil {
+		return "", err
+	} else if hd.Name().IsBranch() {
+		return hd.Name().Short(), nil
+	} else {
+		return "", fmt.Errorf("current reference '%v' is not a branch", hd.Name())
+	}
+}
+
+// Pull request templates looked for by the providers, relative to the root of the repository, the first one found
+// is used. The file names are matched ignoring the case, as GitHub does.
+var (
+	gitHubTemplates    = []string{".github/pull_request_template.md", "pull_request_template.md", "docs/pull_request_template.md"}
+	bitbucketTemplates = append([]string{".bitbucket/pull_request_template.md"}, gitHubTemplates...)
+	gitLabTemplates    = append([]string{".gitlab/merge_request_templates/default.md"}, gitHubTemplates...)
+	giteaTemplates     = append([]string{".gitea/pull_request_template.md"}, gitHubTemplates...)
+)
+
+// pullRequestTemplate reads the first template found in the work tree, empty when there is none
+func pullRequestTemplate(localRepo string, templates []string) string {
+	for _, template := range templates {
+		dir, name := filepath.Split(filepath.Join(localRepo, filepath.FromSlash(template)))
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.Type().IsRegular() && strings.EqualFold(e.Name(), name) {
+				if content, err := os.ReadFile(filepath.Join(dir, e.Name())); err != nil {
+					pterm.Debug.Printfln("cannot read the template %s: %v", e.Name(), err)
+				} else if text := strings.TrimSpace(string(content)); text != "" {
+					return text
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// branchCommit is the commit of the local branch, of the branch of the remote when there is no local one. The
+// upstream of the branch isn't needed, the branches that were never pushed have none.
+func branchCommit(rep *git.Repository, remote string, branch string) (*object.Commit, error) {
+	ref, err := rep.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		ref, err = rep.Reference(plumbing.NewRemoteReferenceName(remote, branch), true)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot find branch %s: %w", branch, err)
+	}
+	return rep.CommitObject(ref.Hash())
+}
+
+// resolveTitleAndDescription fills the title and the description that are not given: the title is the subject of
+// the first commit of the head, the description is the pull request template, the subjects of the other commits
+// otherwise
+func resolveTitleAndDescription(localRepo string, templates []string, remote string, titleOpt optional.String, descriptionOpt optional.String, headBranch string, baseBranch string) (string, string, error) {
+	template := pullRequestTemplate(localRepo, templates)
+	if titleOpt.IsSet() && (descriptionOpt.IsSet() || template != "") {
+		return titleOpt.Value(), descriptionOpt.Default(template), nil
+	}
+
+	// Check if we only have one commit
+	rep, err := git.PlainOpen(localRepo)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Get Head Commit
+	hdCommit, err := branchCommit(rep, remote, headBranch)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Get Base Commit
+	baCommit, err := branchCommit(rep, remote, baseBranch)
+	if err != nil {
+		return "", "", err
+	}
+
+	mbs, err := hdCommit.MergeBase(baCommit)
+	if err != nil {
+		return "", "", err
+	}
+	if len(mbs) != 1 {
+		return "", "", fmt.Errorf("cannot find unique common base between %v and %v", headBranch, baseBranch)
+	}
+	mb := mbs[0]
+	isValid := object.CommitFilter(func(commit *object.Commit) bool {
+		if commit.ID() == mb.ID() {
+			return false
+		} else if isAncestor, err := mb.IsAncestor(commit); err == nil {
+			return isAncestor
+		} else {
+			return false
+		}
+	})
+	isLimit := object.CommitFilter(func(c *object.Commit) bool {
+		return !isValid(c)
+	})
+
+	iter := object.NewFilterCommitIter(hdCommit, &isValid, &isLimit)
+	logs := make([]string, 0)
+	_ = iter.ForEach(func(commit *object.Commit) error {
+		logs = append(logs, strings.Split(commit.Message, "\n")[0])
+		return nil
+	})
+
+	lastIdx := len(logs) - 1
+	if lastIdx > 0 && template == "" {
+		return titleOpt.Default(logs[lastIdx]), descriptionOpt.Default(strings.Join(logs[0:lastIdx], "\n")), nil
+	} else if lastIdx >= 0 {
+		return titleOpt.Default(logs[lastIdx]), descriptionOpt.Default(template), nil
+	} else {
+		return "", "", fmt.Errorf("no commits from %v to %v, cannot infer pr title", baseBranch, headBranch)
+	}
+
+}
+
+// agentAuth authenticates with the ssh agent keys whose comment matches the selector (any key when the selector is nil)
+func agentAuth(sshKeySelector *regexp.Regexp) (*ssh.PublicKeysCallback, error) {
+	if !sshagent.Available() {
+		return nil, errors.New("please use ssh agent")
+	}
+
+	if a, _, err := sshagent.New(); err != nil {
+		return nil, fmt.Errorf("error creating SSH agent: %w", err)
+	} else if sigs, err := a.Signers(); err != nil {
+		return nil, fmt.Errorf("while getting signers : %w", err)
+	} else {
+		newSigs := make([]ssh2.Signer, 0)
+		for _, s := range sigs {
+			if k, ok := s.PublicKey().(*agent.Key); ok {
+				if sshKeySelector == nil || sshKeySelector.MatchString(k.Comment) {
+					newSigs = append(newSigs, s)
+				}
+			}
+		}
+
+		if len(newSigs) == 0 {
+			return nil, errors.New("couldn't find any suitable keys")
+		}
+
+		ag := &ssh.PublicKeysCallback{
+			User: "git",
+			Callback: func() ([]ssh2.Signer, error) {
+				return newSigs, nil
+			},
+		}
+
+		ag.HostKeyCallback = func(hostname string, remote net.Addr, key ssh2.PublicKey) error {
+			return nil
+		}
+		return ag, nil
+	}
+}
+
+// fetchWithAgent fetches the remote of the local repository using the ssh agent keys
+// whose comment matches the selector (any key when the selector is nil).
+func fetchWithAgent(localRepo string, remote string, sshKeySelector *regexp.Regexp) error {
+	rep, giterr := git.PlainOpen(localRepo)
+	if giterr != nil {
+		return giterr
+	}
+
+	ag, err := agentAuth(sshKeySelector)
+	if err != nil {
+		return fmt.Errorf("%w, won't try to fetch the repo '%s'", err, localRepo)
+	}
+
+	sp := spinner.New(spinner.CharSets[55], time.Millisecond*50, spinner.WithSuffix(fmt.Sprintf(" Updating repository")),
+		spinner.WithWriter(os.Stderr))
+	sp.Start()
+	err = rep.Fetch(&git.FetchOptions{RemoteName: remote, Auth: ag})
+	sp.Stop()
+
+	return err
+}
+
+type MissingCommitError struct {
+	missingHash plumbing.Hash
+	cause       error
+}
+
+func (m *MissingCommitError) Error() string {
+	return fmt.Sprintf("Cannot find commit hash: %s. Cause: %v", m.missingHash, m.cause.Error())
+}
+
+// diffFromLocalRepo computes the diff between the merge base of the two commits and the head one,
+// reading them from the local repository.
+func diffFromLocalRepo(localRepo string, baseSha string, headSha string) ([]*gitdiff.File, error) {
+	baseTree, brTree, err := diffTrees(localRepo, baseSha, headSha)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, _ := baseTree.Patch(brTree)
+	buf := &bytes.Buffer{}
+	if err := changes.Encode(buf); err != nil {
+		return nil, err
+	}
+
+	files, _, err5 := gitdiff.Parse(buf)
+	if err5 != nil {
+		return nil, err5
+	}
+
+	return files, nil
+
+}
+
+// diffTrees returns the tree of the merge base of the two commits and the tree of the head one, the commits being
+// full or abbreviated hashes known by the local repository
+func diffTrees(localRepo string, baseSha string, headSha string) (*object.Tree, *object.Tree, error) {
+	rep, giterr := git.PlainOpen(localRepo)
+	if giterr != nil {
+		return nil, nil, giterr
+	}
+
+	cBr, err := resolveCommit(rep, headSha)
+	if err != nil {
+		pterm.Debug.Println("Cannot get the pr branch head commit, do you need to update your local repo ?", err)
+		return nil, nil, err
+	}
+	cBase, err := resolveCommit(rep, baseSha)
+	if err != nil {
+		pterm.Debug.Println("Cannot get the base branch commit, do you need to update your local repo ?", err)
+		return nil, nil, err
+	}
+
+	merge, err := cBr.MergeBase(cBase)
+	if err != nil {
+		pterm.Debug.Println("Cannot find a merge base?!?")
+		return nil, nil, err
+	}
+	if len(merge) != 1 {
+		pterm.Debug.Printfln("More than one merge base ?!? %s", merge)
+		return nil, nil, errors.New(pterm.Sprintfln("More than one merge base : %d", len(merge)))
+	}
+
+	baseTree, err2 := merge[0].Tree()
+	if err2 != nil {
+		pterm.Debug.Println(err2)
+		return nil, nil, err2
+	}
+	brTree, err3 := cBr.Tree()
+	if err3 != nil {
+		pterm.Debug.Println(err3)
+		return nil, nil, err3
+	}
+	return baseTree, brTree, nil
+}
+
+// resolveCommit reads the commit of the hash, which may be abbreviated, a MissingCommitError when it isn't known
+func resolveCommit(rep *git.Repository, sha string) (*object.Commit, error) {
+	hash := plumbing.NewHash(sha)
+	if len(sha) < 40 {
+		if h, err := rep.ResolveRevision(plumbing.Revision(sha)); err != nil {
+			return nil, &MissingCommitError{hash, err}
+		} else {
+			hash = *h
+		}
+	}
+	if c, err := rep.CommitObject(hash); err != nil {
+		return nil, &MissingCommitError{hash, err}
+	} else {
+		return c, nil
+	}
+}
+
+// fileFromLocalRepo reads the file in the head commit when isNew, in the merge base of the two commits otherwise
+func fileFromLocalRepo(localRepo string, baseSha string, headSha string, path string, isNew bool) (string, error) {
+	baseTree, brTree, err := diffTrees(localRepo, baseSha, headSha)
+	if err != nil {
+		return "", err
+	}
+	tree := baseTree
+	if isNew {
+		tree = brTree
+	}
+	if f, err := tree.File(path); err != nil {
+		return "", fmt.Errorf("cannot read %s: %w", path, err)
+	} else {
+		return f.Contents()
+	}
+}
@@ -0,0 +1,1195 @@
+package sv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/antihax/optional"
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
+	"github.com/pterm/pterm"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const giteaPageSize = 50
+
+// GiteaApiUrl returns the REST endpoint of a Gitea (or Forgejo) instance
+func GiteaApiUrl(host string) string {
+	return fmt.Sprintf("https://%s/api/v1", host)
+}
+
+type GiteaSv struct {
+	ctx            context.Context
+	client         *restClient
+	owner          string
+	repo           string
+	localRepo      string
+	sshKeySelector *regexp.Regexp
+}
+
+// NewGiteaSv creates a Gitea provider for owner/name using the REST api at apiUrl. The http client can be
+// replaced, for instance to talk with the fake server of the giteafake package.
+func NewGiteaSv(token string, apiUrl string, httpClient *http.Client, repo string, sshKeyComment string, owner string, name string) Sv {
+	if re, err := regexp.Compile(sshKeyComment); err == nil {
+		return &GiteaSv{
+			ctx:            context.Background(),
+			client:         newRestClient(apiUrl, "Authorization", "token "+token, httpClient),
+			owner:          owner,
+			repo:           name,
+			localRepo:      repo,
+			sshKeySelector: re,
+		}
+	} else {
+		pterm.Fatal.Println("Error while compiling selector re '", sshKeyComment, "'", err)
+		panic(err)
+	}
+}
+
+// GiteaLogin returns the login of the token owner
+func GiteaLogin(token string, apiUrl string) (string, error) {
+	if user, err := NewGiteaSv(token, apiUrl, nil, "", ".*", "", "").(*GiteaSv).currentUser(); err != nil {
+		return "", err
+	} else {
+		return user.Login, nil
+	}
+}
+
+// giteaPages iterates over all the pages of a list, until a page isn't full
+func giteaPages[T any](ctx context.Context, c *restClient, path string, query url.Values) <-chan itemOrError[T] {
+	return giteaPagesMax[T](ctx, c, path, query, 0)
+}
+
+// giteaPagesMax stops after maxPages pages, 0 for no limit
+func giteaPagesMax[T any](ctx context.Context, c *restClient, path string, query url.Values, maxPages int) <-chan itemOrError[T] {
+	ch := make(chan itemOrError[T])
+
+	go func() {
+		q := url.Values{}
+		for k, v := range query {
+			q[k] = v
+		}
+		q.Set("limit", strconv.Itoa(giteaPageSize))
+
+		for page := 1; ; page++ {
+			q.Set("page", strconv.Itoa(page))
+			items := make([]T, 0)
+			if _, err := c.get(ctx, path, q, &items); err != nil {
+				ch <- itemOrError[T]{err: err}
+				break
+			}
+			for _, itm := range items {
+				ch <- itemOrError[T]{item: itm}
+			}
+			if len(items) < giteaPageSize || page == maxPages {
+				break
+			}
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+func giteaList[T any](ctx context.Context, c *restClient, path string, query url.Values) ([]T, error) {
+	return collectItems(giteaPages[T](ctx, c, path, query))
+}
+
+func (g *GiteaSv) repoPath(format string, args ...any) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(g.owner), url.PathEscape(g.repo)) + fmt.Sprintf(format, args...)
+}
+
+type giteaUser struct {
+	Id       int64  `json:"id"`
+	Login    string `json:"login"`
+	FullName string `json:"full_name"`
+}
+
+func (u *giteaUser) GetDisplayName() string {
+	if u == nil {
+		return ""
+	}
+	return u.Login
+}
+
+type giteaRepository struct {
+	Id            int64      `json:"id"`
+	Name          string     `json:"name"`
+	FullName      string     `json:"full_name"`
+	Owner         *giteaUser `json:"owner"`
+	DefaultBranch string     `json:"default_branch"`
+}
+
+type giteaBranchRef struct {
+	Label string           `json:"label"`
+	Ref   string           `json:"ref"`
+	Sha   string           `json:"sha"`
+	Repo  *giteaRepository `json:"repo"`
+}
+
+type giteaLabel struct {
+	Id    int64  `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type giteaPullRequest struct {
+	Id                 int64           `json:"id"`
+	Number             int64           `json:"number"`
+	Title              string          `json:"title"`
+	Body               string          `json:"body"`
+	State              string          `json:"state"`
+	Draft              bool            `json:"draft"`
+	Merged             bool            `json:"merged"`
+	User               *giteaUser      `json:"user"`
+	Labels             []giteaLabel    `json:"labels"`
+	RequestedReviewers []*giteaUser    `json:"requested_reviewers"`
+	Assignees          []*giteaUser    `json:"assignees"`
+	Head               *giteaBranchRef `json:"head"`
+	Base               *giteaBranchRef `json:"base"`
+	MergeBase          string          `json:"merge_base"`
+	HtmlUrl            string          `json:"html_url"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+func (p *giteaPullRequest) repositoryFullName() string {
+	if p.Base != nil && p.Base.Repo != nil {
+		return p.Base.Repo.FullName
+	}
+	return ""
+}
+
+func (g *GiteaSv) currentUser() (*giteaUser, error) {
+	user := &giteaUser{}
+	if _, err := g.client.get(g.ctx, "/user", nil, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+var giteaStates = map[string]string{
+	"":       "open",
+	"open":   "open",
+	"closed": "closed",
+	"merged": "closed",
+	"all":    "all",
+}
+
+// matches checks the criteria the pulls endpoint can't filter on, login being the current user
+func (p *giteaPullRequest) matches(filter PullRequestFilter, login string) bool {
+	userIs := func(u *giteaUser, name string) bool {
+		return u != nil && (strings.EqualFold(u.Login, name) || (name == CurrentUser && u.Login == login))
+	}
+
+	switch {
+	case filter.State == "closed" && p.Merged, filter.State == "merged" && !p.Merged:
+		return false
+	case filter.Query != "" && !strings.Contains(strings.ToLower(p.Title), strings.ToLower(filter.Query)):
+		return false
+	case filter.Author != "" && !userIs(p.User, filter.Author):
+		return false
+	case filter.Base != "" && (p.Base == nil || p.Base.Ref != filter.Base):
+		return false
+	case filter.Head != "" && (p.Head == nil || p.Head.Ref != filter.Head):
+		return false
+	case filter.Draft.IsSet() && p.Draft != filter.Draft.Value():
+		return false
+	case !filter.UpdatedSince.IsZero() && p.UpdatedAt.Before(filter.UpdatedSince):
+		return false
+	}
+
+	if filter.Label != "" {
+		found := false
+		for _, l := range p.Labels {
+			found = found || strings.EqualFold(l.Name, filter.Label)
+		}
+		if !found {
+			return false
+		}
+	}
+	if filter.Reviewer != "" {
+		found := false
+		for _, r := range p.RequestedReviewers {
+			found = found || userIs(r, filter.Reviewer)
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ListPullRequests filters on the state server side, the pulls endpoint has no other criteria
+func (g *GiteaSv) ListPullRequests(filter PullRequestFilter) (<-chan PullRequest, error) {
+	state, ok := giteaStates[filter.State]
+	if !ok {
+		return nil, fmt.Errorf("unknown state '%s'", filter.State)
+	}
+
+	// Check the repository is readable first, to report errors synchronously
+	if _, err := g.client.get(g.ctx, g.repoPath(""), nil, nil); err != nil {
+		return nil, err
+	}
+
+	login := ""
+	if filter.Author == CurrentUser || filter.Reviewer == CurrentUser {
+		if user, err := g.currentUser(); err != nil {
+			return nil, err
+		} else {
+			login = user.Login
+		}
+	}
+
+	res := make(chan PullRequest)
+	go func() {
+		for pr := range giteaPagesMax[giteaPullRequest](g.ctx, g.client, g.repoPath("/pulls"), url.Values{"state": {state}}, filter.MaxPages) {
+			if pr.err != nil {
+				pterm.Debug.Println(pr.err)
+				break
+			}
+			p := pr.item
+			if !p.matches(filter, login) {
+				continue
+			}
+			res <- GiteaPullRequest{&p, g}
+		}
+		close(res)
+	}()
+
+	return res, nil
+}
+
+func (g *GiteaSv) GetPullRequest(id string) (PullRequest, error) {
+	number, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	pr := &giteaPullRequest{}
+	if _, err := g.client.get(g.ctx, g.repoPath("/pulls/%d", number), nil, pr); err != nil {
+		return nil, err
+	}
+
+	return GiteaPullRequest{pr, g}, nil
+}
+
+type giteaIssue struct {
+	Number     int64 `json:"number"`
+	Repository struct {
+		Owner    string `json:"owner"`
+		Name     string `json:"name"`
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+func (g *GiteaSv) PullRequestStatus() (<-chan PullRequestStatus, error) {
+	if _, err := g.currentUser(); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan PullRequestStatus)
+	go func() {
+		// Get My PRs
+		for issue := range giteaPages[giteaIssue](g.ctx, g.client, "/repos/issues/search", url.Values{"type": {"pulls"}, "state": {"open"}, "created": {"true"}}) {
+			if issue.err != nil {
+				pterm.Debug.Printfln("cannot read authored pull requests: %v", issue.err)
+				break
+			}
+			if status, err := g.toPullRequestStatus(issue.item, true); err == nil {
+				ch <- status
+			} else {
+				pterm.Debug.Printfln("cannot read pull request %d: %v", issue.item.Number, err)
+			}
+		}
+
+		// Now get reviews as well
+		for issue := range giteaPages[giteaIssue](g.ctx, g.client, "/repos/issues/search", url.Values{"type": {"pulls"}, "state": {"open"}, "review_requested": {"true"}}) {
+			if issue.err != nil {
+				pterm.Debug.Printfln("cannot read pull requests to review: %v", issue.err)
+				break
+			}
+			if status, err := g.toPullRequestStatus(issue.item, false); err == nil {
+				ch <- status
+			} else {
+				pterm.Debug.Printfln("cannot read pull request %d: %v", issue.item.Number, err)
+			}
+		}
+
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+// toPullRequestStatus loads the pull request behind a search result, with its reviews and statuses
+func (g *GiteaSv) toPullRequestStatus(issue giteaIssue, isMine bool) (PullRequestStatus, error) {
+	base := fmt.Sprintf("/repos/%s/pulls/%d", issue.Repository.FullName, issue.Number)
+
+	pr := &giteaPullRequest{}
+	if _, err := g.client.get(g.ctx, base, nil, pr); err != nil {
+		return nil, err
+	}
+
+	reviews, err := giteaList[giteaReview](g.ctx, g.client, base+"/reviews", nil)
+	if err != nil {
+		pterm.Debug.Printfln("cannot read pull request %d reviews: %v", issue.Number, err)
+	}
+
+	statuses := make([]giteaStatus, 0)
+	if pr.Head != nil {
+		if res, err := giteaList[giteaStatus](g.ctx, g.client, fmt.Sprintf("/repos/%s/commits/%s/statuses", issue.Repository.FullName, pr.Head.Sha), nil); err == nil {
+			statuses = res
+		} else {
+			pterm.Debug.Printfln("cannot read pull request %d statuses: %v", issue.Number, err)
+		}
+	}
+
+	return GiteaPullRequestStatus{pr, reviews, statuses, isMine}, nil
+}
+
+func (g *GiteaSv) wrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	g.client.wrapTransport(wrap)
+}
+
+func (g *GiteaSv) Fetch(remote string) error {
+	return fetchWithAgent(g.localRepo, remote, g.sshKeySelector)
+}
+
+func (g *GiteaSv) PushBranch(remote string, branch string, force bool) (bool, error) {
+	return pushBranch(g.localRepo, g.sshKeySelector, remote, branch, force)
+}
+
+// CheckoutPullRequest fetches refs/pull/N/head, that works for the forks as well
+func (g *GiteaSv) CheckoutPullRequest(remote string, id string, force bool) (string, error) {
+	pr, err := g.GetPullRequest(id)
+	if err != nil {
+		return "", err
+	}
+	gpr := pr.(GiteaPullRequest)
+	if gpr.Head == nil {
+		return "", fmt.Errorf("the head of pull request %s is unknown", id)
+	}
+
+	head := pullRequestHead{id: id, remote: remote, ref: fmt.Sprintf("refs/heads/%s", gpr.Head.Ref), branch: gpr.Head.Ref}
+	if gpr.Head.Repo == nil || gpr.Head.Repo.FullName != gpr.repositoryFullName() {
+		head.ref, head.branch = fmt.Sprintf("refs/pull/%d/head", gpr.Number), forkBranch(gpr.Number, gpr.Head.Ref)
+	}
+	return head.branch, checkoutHead(g.localRepo, g.sshKeySelector, head, force)
+}
+
+func (g *GiteaSv) GetRepositoryFullName() string {
+	return fmt.Sprintf("%s/%s", g.owner, g.repo)
+}
+
+func (g *GiteaSv) GetCurrentBranch() (string, error) {
+	return getCurrentBranch(g.localRepo)
+}
+
+func (g *GiteaSv) GetDefaultBranch() (string, error) {
+	repo := &giteaRepository{}
+	if _, err := g.client.get(g.ctx, g.repoPath(""), nil, repo); err != nil {
+		return "", err
+	}
+	return repo.DefaultBranch, nil
+}
+
+func (g *GiteaSv) resolveHeadBranch(headBranch optional.String) (string, error) {
+	if headBranch.IsSet() {
+		return headBranch.Value(), nil
+	} else {
+		return g.GetCurrentBranch()
+	}
+}
+
+func (g *GiteaSv) resolveBaseBranch(baseBranch optional.String) (string, error) {
+	if baseBranch.IsSet() {
+		return baseBranch.Value(), nil
+	} else {
+		return g.GetDefaultBranch()
+	}
+}
+
+// resolveLabels returns the ids of the labels, the repository labels are created if allowed
+func (g *GiteaSv) resolveLabels(names []string, createMissing bool) ([]int64, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	existing, err := giteaList[giteaLabel](g.ctx, g.client, g.repoPath("/labels"), nil)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]int64)
+	for _, l := range existing {
+		byName[l.Name] = l.Id
+	}
+
+	ids := make([]int64, 0)
+	for _, name := range names {
+		if id, ok := byName[name]; ok {
+			ids = append(ids, id)
+		} else if !createMissing {
+			return nil, fmt.Errorf("label '%s' doesn't exist", name)
+		} else {
+			label := &giteaLabel{}
+			if _, err := g.client.post(g.ctx, g.repoPath("/labels"), giteaLabel{Name: name, Color: "#ededed"}, label); err != nil {
+				return nil, err
+			}
+			ids = append(ids, label.Id)
+		}
+	}
+	return ids, nil
+}
+
+func (g *GiteaSv) ResolveTitleAndDescription(remote string, titleOpt optional.String, descriptionOpt optional.String, headBranch string, baseBranch string) (string, string, error) {
+	return resolveTitleAndDescription(g.localRepo, giteaTemplates, remote, titleOpt, descriptionOpt, headBranch, baseBranch)
+}
+
+func (g *GiteaSv) ListCollaborators() ([]string, error) {
+	if users, err := giteaList[giteaUser](g.ctx, g.client, g.repoPath("/collaborators"), nil); err != nil {
+		return nil, err
+	} else {
+		logins := make([]string, 0, len(users))
+		for _, u := range users {
+			logins = append(logins, u.Login)
+		}
+		return logins, nil
+	}
+}
+
+func (g *GiteaSv) ListLabels() ([]string, error) {
+	if labels, err := giteaList[giteaLabel](g.ctx, g.client, g.repoPath("/labels"), nil); err != nil {
+		return nil, err
+	} else {
+		names := make([]string, 0, len(labels))
+		for _, l := range labels {
+			names = append(names, l.Name)
+		}
+		return names, nil
+	}
+}
+
+func (g *GiteaSv) CreatePullRequest(args CreatePullRequestArgs) (PullRequestStatus, error) {
+	if labelIds, err := g.resolveLabels(args.Labels, args.CreateMissingLabels); err != nil {
+		return nil, err
+	} else if headBranch, err := g.resolveHeadBranch(args.HeadBranch); err != nil {
+		return nil, err
+	} else if baseBranch, err := g.resolveBaseBranch(args.BaseBranch); err != nil {
+		return nil, err
+	} else if title, description, err := g.ResolveTitleAndDescription(args.Remote, args.Title, args.Description, headBranch, baseBranch); err != nil {
+		return nil, err
+	} else {
+		if args.Draft {
+			title = draftTitle(title, true, "WIP: ")
+		}
+		pr := &giteaPullRequest{}
+		if _, err := g.client.post(g.ctx, g.repoPath("/pulls"), map[string]interface{}{
+			"head":   headBranch,
+			"base":   baseBranch,
+			"title":  title,
+			"body":   description,
+			"labels": labelIds,
+		}, pr); err != nil {
+			return nil, err
+		}
+
+		if len(args.Reviewers) > 0 {
+			if _, err := g.client.post(g.ctx, g.repoPath("/pulls/%d/requested_reviewers", pr.Number), map[string][]string{
+				"reviewers": args.Reviewers,
+			}, nil); err != nil {
+				return nil, err
+			}
+		}
+
+		return GiteaPullRequestStatus{pr, nil, nil, true}, nil
+	}
+}
+
+type GiteaPullRequest struct {
+	*giteaPullRequest
+	sv *GiteaSv
+}
+
+func (g GiteaPullRequest) path(format string, args ...any) string {
+	return g.sv.repoPath("/pulls/%d", g.Number) + fmt.Sprintf(format, args...)
+}
+
+func (g GiteaPullRequest) GetBranch() Branch {
+	return GiteaBranch{g.Head}
+}
+
+func (g GiteaPullRequest) GetBase() Branch {
+	return GiteaBranch{g.Base}
+}
+
+type GiteaBranch struct {
+	*giteaBranchRef
+}
+
+func (g GiteaBranch) GetName() string {
+	if g.giteaBranchRef == nil {
+		return ""
+	}
+	return g.Ref
+}
+
+func (g GiteaPullRequest) GetId() interface{} {
+	return g.Number
+}
+
+func (g GiteaPullRequest) GetTitle() string {
+	return g.Title
+}
+
+func (g GiteaPullRequest) GetDescription() string {
+	return g.Body
+}
+
+func (g GiteaPullRequest) GetUrl() string {
+	return g.HtmlUrl
+}
+
+func (g GiteaPullRequest) GetAuthor() Author {
+	return g.User
+}
+
+func (g GiteaPullRequest) GetState() string {
+	return g.State
+}
+
+func (g GiteaPullRequest) GetCreatedOn() time.Time {
+	return g.CreatedAt
+}
+
+type giteaComment struct {
+	Id               int64      `json:"id"`
+	Body             string     `json:"body"`
+	User             *giteaUser `json:"user"`
+	CreatedAt        time.Time  `json:"created_at"`
+	Path             string     `json:"path,omitempty"`
+	CommitId         string     `json:"commit_id,omitempty"`
+	Position         int64      `json:"position,omitempty"`
+	OriginalPosition int64      `json:"original_position,omitempty"`
+	ReviewId         int64      `json:"pull_request_review_id,omitempty"`
+}
+
+// line follows the GitHub convention : lines on the new side are negative
+func (c *giteaComment) line() int64 {
+	if c.Position > 0 {
+		return -c.Position
+	}
+	return c.OriginalPosition
+}
+
+func (g GiteaPullRequest) getReviewComments() ([]giteaComment, error) {
+	reviews, err := giteaList[giteaReview](g.sv.ctx, g.sv.client, g.path("/reviews"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]giteaComment, 0)
+	for _, r := range reviews {
+		if r.CommentsCount == 0 {
+			continue
+		}
+		comments := make([]giteaComment, 0)
+		if _, err := g.sv.client.get(g.sv.ctx, g.path("/reviews/%d/comments", r.Id), nil, &comments); err != nil {
+			return nil, err
+		}
+		result = append(result, comments...)
+	}
+	return result, nil
+}
+
+func (g GiteaPullRequest) GetCommentsByLine() ([]Comment, map[string]map[int64][]Comment, error) {
+	prComments := make([]Comment, 0)
+	commentMap := make(map[string]map[int64][]Comment)
+
+	issueComments, err := giteaList[giteaComment](g.sv.ctx, g.sv.client, g.sv.repoPath("/issues/%d/comments", g.Number), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range issueComments {
+		prComments = append(prComments, GiteaComment{&issueComments[i], nil})
+	}
+
+	reviewComments, err := g.getReviewComments()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Gitea has no reply api, comments on the same line are a thread whose parent is the first one
+	for i := range reviewComments {
+		cmt := &reviewComments[i]
+		line := cmt.line()
+
+		byLine, ok := commentMap[cmt.Path]
+		if !ok {
+			byLine = make(map[int64][]Comment)
+			commentMap[cmt.Path] = byLine
+		}
+
+		var parent *giteaComment
+		if len(byLine[line]) > 0 {
+			parent = byLine[line][0].(GiteaComment).giteaComment
+		}
+		byLine[line] = append(byLine[line], GiteaComment{cmt, parent})
+	}
+
+	return prComments, commentMap, nil
+}
+
+type GiteaComment struct {
+	*giteaComment
+	parent *giteaComment
+}
+
+func (g GiteaComment) GetContent() CommentContent {
+	return g
+}
+
+func (g GiteaComment) GetRaw() string {
+	return g.Body
+}
+
+func (g GiteaComment) GetParentId() interface{} {
+	if g.parent != nil {
+		return g.parent.Id
+	}
+	return nil
+}
+
+func (g GiteaComment) GetId() interface{} {
+	return g.Id
+}
+
+func (g GiteaComment) GetUser() Author {
+	return g.User
+}
+
+func (g GiteaComment) GetCreatedOn() time.Time {
+	return g.CreatedAt
+}
+
+func (g GiteaComment) GetReactions() Reactions {
+	return make(Reactions)
+}
+
+func (g GiteaPullRequest) GetDiff() ([]*gitdiff.File, error) {
+	if g.Head == nil || len(g.MergeBase) == 0 {
+		return nil, fmt.Errorf("pull request %d has no merge base", g.Number)
+	}
+	return diffFromLocalRepo(g.sv.localRepo, g.MergeBase, g.Head.Sha)
+}
+
+func (g GiteaPullRequest) GetFileContent(path string, isNew bool) (string, error) {
+	if g.Head == nil || len(g.MergeBase) == 0 {
+		return "", fmt.Errorf("pull request %d has no merge base", g.Number)
+	}
+	return fileFromLocalRepo(g.sv.localRepo, g.MergeBase, g.Head.Sha, path, isNew)
+}
+
+type giteaStatus struct {
+	Id          int64     `json:"id"`
+	Status      string    `json:"status"`
+	TargetUrl   string    `json:"target_url"`
+	Description string    `json:"description"`
+	Context     string    `json:"context"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type GiteaCheck struct {
+	giteaStatus
+}
+
+func (g GiteaCheck) GetName() string {
+	return g.Context
+}
+
+func (g GiteaCheck) GetStatus() string {
+	return g.Status
+}
+
+func (g GiteaCheck) GetUrl() string {
+	return g.TargetUrl
+}
+
+func (g GiteaPullRequest) GetChecks() ([]Check, error) {
+	result := make([]Check, 0)
+	if g.Head == nil {
+		return result, nil
+	}
+
+	statuses, err := giteaList[giteaStatus](g.sv.ctx, g.sv.client, g.sv.repoPath("/commits/%s/statuses", g.Head.Sha), nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range latestGiteaStatuses(statuses) {
+		result = append(result, GiteaCheck{s})
+	}
+
+	return result, nil
+}
+
+var giteaStatusConclusions = map[string]string{
+	"success": "SUCCESS",
+	"failure": "FAILURE",
+	"error":   "FAILURE",
+	"warning": "NEUTRAL",
+}
+
+func (g GiteaCheck) GetConclusion() string {
+	return giteaStatusConclusions[g.Status]
+}
+
+// GetStartedAt is the creation of the status, Gitea doesn't keep the start of the build
+func (g GiteaCheck) GetStartedAt() time.Time {
+	return g.CreatedAt
+}
+
+func (g GiteaCheck) GetCompletedAt() time.Time {
+	if g.GetConclusion() == "" {
+		return time.Time{}
+	}
+	return g.CreatedAt
+}
+
+func (g GiteaCheck) CanRerun() bool {
+	return false
+}
+
+func (g GiteaCheck) HasLog() bool {
+	return false
+}
+
+// GetCheckRuns are the commit statuses, the only checks the Gitea api exposes
+func (g GiteaPullRequest) GetCheckRuns() ([]CheckRun, error) {
+	checks, err := g.GetChecks()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]CheckRun, 0, len(checks))
+	for _, c := range checks {
+		result = append(result, c.(GiteaCheck))
+	}
+	return result, nil
+}
+
+func (g GiteaPullRequest) RerunChecks(_ []CheckRun) error {
+	return ErrNotSupported
+}
+
+func (g GiteaPullRequest) GetCheckLog(_ CheckRun) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+// latestGiteaStatuses keeps the most recent status of each context, statuses are listed newest first
+func latestGiteaStatuses(statuses []giteaStatus) []giteaStatus {
+	seen := make(map[string]bool)
+	result := make([]giteaStatus, 0)
+	for _, s := range statuses {
+		if !seen[s.Context] {
+			seen[s.Context] = true
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+type giteaReview struct {
+	Id            int64      `json:"id"`
+	User          *giteaUser `json:"user"`
+	State         string     `json:"state"`
+	Body          string     `json:"body"`
+	CommitId      string     `json:"commit_id"`
+	Dismissed     bool       `json:"dismissed"`
+	Stale         bool       `json:"stale"`
+	CommentsCount int        `json:"comments_count"`
+	SubmittedAt   time.Time  `json:"submitted_at"`
+}
+
+func (g GiteaPullRequest) GetReviews() ([]Review, error) {
+	reviews, err := giteaList[giteaReview](g.sv.ctx, g.sv.client, g.path("/reviews"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Review, 0)
+	for i := range reviews {
+		result = append(result, GiteaReview{&reviews[i], g})
+	}
+	return result, nil
+}
+
+type giteaReviewComment struct {
+	Path        string `json:"path"`
+	Body        string `json:"body"`
+	NewPosition int    `json:"new_position,omitempty"`
+	OldPosition int    `json:"old_position,omitempty"`
+}
+
+func (g GiteaPullRequest) ReplyToComment(comment Comment, replyText string) (Comment, error) {
+	if c, ok := comment.(GiteaComment); ok {
+		if len(c.Path) == 0 {
+			// Not an inline comment, answer in the conversation
+			res := &giteaComment{}
+			if _, err := g.sv.client.post(g.sv.ctx, g.sv.repoPath("/issues/%d/comments", g.Number), map[string]string{"body": replyText}, res); err != nil {
+				return nil, err
+			}
+			return GiteaComment{res, nil}, nil
+		}
+
+		line := c.line()
+		isNew := line < 0
+		if isNew {
+			line = -line
+		}
+		parent := c.giteaComment
+		if c.parent != nil {
+			parent = c.parent
+		}
+		if reply, err := g.CreateComment(c.Path, c.CommitId, int(line), isNew, replyText); err != nil {
+			return nil, err
+		} else {
+			return GiteaComment{reply.(GiteaComment).giteaComment, parent}, nil
+		}
+	} else {
+		return nil, fmt.Errorf("illegal argument: not a gitea comment")
+	}
+}
+
+// CreateComment adds the comment to the pending review if there is one, otherwise it is posted right away
+func (g GiteaPullRequest) CreateComment(path string, commitId string, line int, isNew bool, body string) (Comment, error) {
+	c := giteaReviewComment{Path: path, Body: body}
+	if isNew {
+		c.NewPosition = line
+	} else {
+		c.OldPosition = line
+	}
+
+	if pending, err := g.findPendingReview(); err != nil {
+		return nil, err
+	} else if pending != nil {
+		res := &giteaComment{}
+		if _, err := g.sv.client.post(g.sv.ctx, g.path("/reviews/%d/comments", pending.Id), c, res); err != nil {
+			return nil, err
+		}
+		return GiteaComment{res, nil}, nil
+	}
+
+	review := &giteaReview{}
+	if _, err := g.sv.client.post(g.sv.ctx, g.path("/reviews"), map[string]interface{}{
+		"event":     "COMMENT",
+		"commit_id": commitId,
+		"comments":  []giteaReviewComment{c},
+	}, review); err != nil {
+		return nil, err
+	}
+
+	comments := make([]giteaComment, 0)
+	if _, err := g.sv.client.get(g.sv.ctx, g.path("/reviews/%d/comments", review.Id), nil, &comments); err != nil {
+		return nil, err
+	} else if len(comments) == 0 {
+		return nil, errors.New("bad response, the new review has no comments")
+	}
+	return GiteaComment{&comments[0], nil}, nil
+}
+
+func (g GiteaPullRequest) GetLastCommitId() string {
+	if g.Head == nil {
+		return ""
+	}
+	return g.Head.Sha
+}
+
+func (g GiteaPullRequest) findPendingReview() (*giteaReview, error) {
+	user, err := g.sv.currentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := giteaList[giteaReview](g.sv.ctx, g.sv.client, g.path("/reviews"), nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reviews {
+		if reviews[i].State == "PENDING" && reviews[i].User != nil && reviews[i].User.Id == user.Id {
+			return &reviews[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (g GiteaPullRequest) StartReview() (Review, error) {
+	if rev, err := g.GetPendingReview(); err != nil || rev != nil {
+		return rev, err
+	}
+
+	review := &giteaReview{}
+	if _, err := g.sv.client.post(g.sv.ctx, g.path("/reviews"), map[string]string{
+		"event":     "PENDING",
+		"commit_id": g.GetLastCommitId(),
+	}, review); err != nil {
+		return nil, err
+	}
+	return GiteaReview{review, g}, nil
+}
+
+func (g GiteaPullRequest) GetPendingReview() (Review, error) {
+	if rev, err := g.findPendingReview(); err != nil {
+		return nil, err
+	} else if rev != nil {
+		return GiteaReview{rev, g}, nil
+	}
+	// No error but no reviews
+	return nil, nil
+}
+
+var giteaMergeStyles = map[string]string{
+	"":       "merge",
+	"merge":  "merge",
+	"squash": "squash",
+	"rebase": "rebase",
+}
+
+func (g GiteaPullRequest) Merge(opts MergeOptions) error {
+	style, ok := giteaMergeStyles[opts.Method]
+	if !ok {
+		return fmt.Errorf("unknown merge method %s", opts.Method)
+	}
+	title := opts.Title
+	if title == "" {
+		title = g.Title
+	}
+	_, err := g.sv.client.post(g.sv.ctx, g.path("/merge"), map[string]interface{}{
+		"Do":                        style,
+		"head_commit_id":            g.GetLastCommitId(),
+		"MergeTitleField":           title,
+		"MergeMessageField":         opts.Body,
+		"delete_branch_after_merge": opts.DeleteBranch,
+		"merge_when_checks_succeed": opts.Auto,
+	}, nil)
+	return err
+}
+
+func (g GiteaPullRequest) Close() error {
+	_, err := g.sv.client.patch(g.sv.ctx, g.path(""), map[string]interface{}{"state": "closed"}, nil)
+	return err
+}
+
+func (g GiteaPullRequest) Reopen() error {
+	_, err := g.sv.client.patch(g.sv.ctx, g.path(""), map[string]interface{}{"state": "open"}, nil)
+	return err
+}
+
+// Edit updates the pull request, then its labels and its reviewers that have their own endpoints
+func (g GiteaPullRequest) Edit(args EditPullRequestArgs) error {
+	params := make(map[string]interface{})
+	if args.Title.IsSet() {
+		params["title"] = args.Title.Value()
+	}
+	if args.Description.IsSet() {
+		params["body"] = args.Description.Value()
+	}
+	if args.BaseBranch.IsSet() {
+		params["base"] = args.BaseBranch.Value()
+	}
+	if len(args.AddAssignees) > 0 || len(args.RemoveAssignees) > 0 {
+		skip := make(map[string]bool)
+		for _, login := range args.RemoveAssignees {
+			skip[login] = true
+		}
+		assignees := make([]string, 0)
+		for _, u := range g.Assignees {
+			assignees = append(assignees, u.Login)
+		}
+		logins := make([]string, 0)
+		for _, login := range append(assignees, args.AddAssignees...) {
+			if !skip[login] {
+				skip[login] = true
+				logins = append(logins, login)
+			}
+		}
+		params["assignees"] = logins
+	}
+	if len(params) > 0 {
+		if _, err := g.sv.client.patch(g.sv.ctx, g.path(""), params, nil); err != nil {
+			return err
+		}
+	}
+
+	if ids, err := g.sv.resolveLabels(args.AddLabels, args.CreateMissingLabels); err != nil {
+		return err
+	} else if len(ids) > 0 {
+		if _, err := g.sv.client.post(g.sv.ctx, g.sv.repoPath("/issues/%d/labels", g.Number), map[string][]int64{"labels": ids}, nil); err != nil {
+			return err
+		}
+	}
+	if ids, err := g.sv.resolveLabels(args.RemoveLabels, false); err != nil {
+		return err
+	} else {
+		for _, id := range ids {
+			if _, err := g.sv.client.delete(g.sv.ctx, g.sv.repoPath("/issues/%d/labels/%d", g.Number, id)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(args.AddReviewers) > 0 {
+		if _, err := g.sv.client.post(g.sv.ctx, g.path("/requested_reviewers"), map[string][]string{"reviewers": args.AddReviewers}, nil); err != nil {
+			return err
+		}
+	}
+	if len(args.RemoveReviewers) > 0 {
+		if _, err := g.sv.client.do(g.sv.ctx, http.MethodDelete, g.path("/requested_reviewers"), nil, map[string][]string{"reviewers": args.RemoveReviewers}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g GiteaPullRequest) IsDraft() bool {
+	return g.Draft
+}
+
+// SetDraft changes the title, Gitea tells the drafts by their prefix
+func (g GiteaPullRequest) SetDraft(draft bool) error {
+	_, err := g.sv.client.patch(g.sv.ctx, g.path(""), map[string]interface{}{"title": draftTitle(g.Title, draft, "WIP: ")}, nil)
+	return err
+}
+
+type GiteaReview struct {
+	*giteaReview
+	pr GiteaPullRequest
+}
+
+func (g GiteaReview) GetId() string {
+	return fmt.Sprintf("%d", g.Id)
+}
+
+func (g GiteaReview) GetState() string {
+	if g.Dismissed {
+		return "DISMISSED"
+	}
+	return g.State
+}
+
+func (g GiteaReview) GetAuthor() string {
+	return g.User.GetDisplayName()
+}
+
+func (g GiteaReview) GetSubmitedAt() time.Time {
+	return g.SubmittedAt
+}
+
+func (g GiteaReview) Dismiss() error {
+	_, err := g.pr.sv.client.post(g.pr.sv.ctx, g.pr.path("/reviews/%d/dismissals", g.Id), map[string]string{"message": ""}, nil)
+	return err
+}
+
+// submit sends a pending review with the given event
+func (g GiteaReview) submit(event string, comment *string) error {
+	body := ""
+	if comment != nil {
+		body = *comment
+	}
+	_, err := g.pr.sv.client.post(g.pr.sv.ctx, g.pr.path("/reviews/%d", g.Id), map[string]string{
+		"event": event,
+		"body":  body,
+	}, nil)
+	return err
+}
+
+func (g GiteaReview) Close(comment *string) error {
+	return g.submit("COMMENT", comment)
+}
+
+func (g GiteaReview) Approve(comment *string) error {
+	return g.submit("APPROVED", comment)
+}
+
+func (g GiteaReview) RequestChanges(comment *string) error {
+	return g.submit("REQUEST_CHANGES", comment)
+}
+
+func (g GiteaReview) Cancel() error {
+	_, err := g.pr.sv.client.delete(g.pr.sv.ctx, g.pr.path("/reviews/%d", g.Id))
+	return err
+}
+
+type GiteaPullRequestStatus struct {
+	*giteaPullRequest
+	reviews  []giteaReview
+	statuses []giteaStatus
+	isMine   bool
+}
+
+func (g GiteaPullRequestStatus) GetId() interface{} {
+	return g.Number
+}
+
+func (g GiteaPullRequestStatus) GetTitle() string {
+	return g.Title
+}
+
+func (g GiteaPullRequestStatus) GetStatus() string {
+	return g.State
+}
+
+func (g GiteaPullRequestStatus) GetBranchName() string {
+	return GiteaBranch{g.Head}.GetName()
+}
+
+func (g GiteaPullRequestStatus) GetBaseName() string {
+	return GiteaBranch{g.Base}.GetName()
+}
+
+func (g GiteaPullRequestStatus) GetReviews() []Review {
+	result := make([]Review, 0)
+	for i := range g.reviews {
+		// Reviews are read only from the status view, they don't need the pull request
+		result = append(result, GiteaReview{&g.reviews[i], GiteaPullRequest{}})
+	}
+	return result
+}
+
+var giteaStatusStates = map[string]string{
+	"success": "SUCCESS",
+	"failure": "ERROR",
+	"error":   "ERROR",
+	"warning": "ERROR",
+	"pending": "PENDING",
+}
+
+// GetChecksByStatus is empty: Gitea only has commit statuses, reported as contexts
+func (g GiteaPullRequestStatus) GetChecksByStatus() map[string]int {
+	return make(map[string]int)
+}
+
+func (g GiteaPullRequestStatus) GetContextByStatus() map[string]int {
+	states := make(map[string]int)
+	for _, s := range latestGiteaStatuses(g.statuses) {
+		state, ok := giteaStatusStates[s.Status]
+		if !ok {
+			state = strings.ToUpper(s.Status)
+		}
+		states[state] += 1
+	}
+	return states
+}
+
+func (g GiteaPullRequestStatus) GetAuthor() string {
+	return g.User.GetDisplayName()
+}
+
+func (g GiteaPullRequestStatus) GetRepository() string {
+	return g.repositoryFullName()
+}
+
+func (g GiteaPullRequestStatus) IsMine() bool {
+	return g.isMine
+}
@@ -0,0 +1,320 @@
+package sv_test
+
+import (
+	"errors"
+	"github.com/antihax/optional"
+	"github.com/vballestra/sv/sv"
+	"github.com/vballestra/sv/sv/giteafake"
+	"strings"
+	"testing"
+)
+
+const giteaRepo = "me/repo"
+
+func newGitea(t *testing.T) (*giteafake.Server, sv.Sv) {
+	srv := giteafake.NewServer("token", giteafake.User{Id: 1, Login: "me"})
+	t.Cleanup(srv.Close)
+	srv.AddUser("alice-token", giteafake.User{Id: 2, Login: "alice"})
+	srv.AddRepository("me", "repo", "main")
+	return srv, sv.NewGiteaSv("token", srv.ApiUrl(), srv.Client(), t.TempDir(), ".*", "me", "repo")
+}
+
+func giteaMe() *giteafake.User {
+	return &giteafake.User{Id: 1, Login: "me"}
+}
+
+func giteaAlice() *giteafake.User {
+	return &giteafake.User{Id: 2, Login: "alice"}
+}
+
+func giteaBranch(name string) *giteafake.BranchRef {
+	return &giteafake.BranchRef{Label: name, Ref: name, Sha: name + "-sha"}
+}
+
+func TestGiteaListPullRequests(t *testing.T) {
+	srv, provider := newGitea(t)
+	srv.AddPullRequest(giteaRepo, giteafake.PullRequest{Title: "mine", User: giteaMe(), Head: giteaBranch("a"), Base: giteaBranch("main")})
+	srv.AddPullRequest(giteaRepo, giteafake.PullRequest{Title: "WIP: hers", Draft: true, User: giteaAlice(), Head: giteaBranch("b"),
+		Base: giteaBranch("main"), RequestedReviewers: []*giteafake.User{giteaMe()}})
+	srv.AddPullRequest(giteaRepo, giteafake.PullRequest{Title: "merged", State: "closed", Merged: true, User: giteaMe(),
+		Head: giteaBranch("c"), Base: giteaBranch("main")})
+	srv.AddPullRequest(giteaRepo, giteafake.PullRequest{Title: "closed", State: "closed", User: giteaMe(),
+		Head: giteaBranch("d"), Base: giteaBranch("main")})
+
+	for _, test := range []struct {
+		name   string
+		filter sv.PullRequestFilter
+		want   string
+	}{
+		{"open by default", sv.PullRequestFilter{}, "mine,WIP: hers"},
+		{"merged", sv.PullRequestFilter{State: "merged"}, "merged"},
+		{"closed", sv.PullRequestFilter{State: "closed"}, "closed"},
+		{"all", sv.PullRequestFilter{State: "all"}, "mine,WIP: hers,merged,closed"},
+		{"author", sv.PullRequestFilter{Author: sv.CurrentUser}, "mine"},
+		{"reviewer", sv.PullRequestFilter{Reviewer: sv.CurrentUser}, "WIP: hers"},
+		{"drafts", sv.PullRequestFilter{Draft: optional.NewBool(true)}, "WIP: hers"},
+		{"query", sv.PullRequestFilter{State: "all", Query: "MERGED"}, "merged"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := strings.Join(collectPullRequests(t, provider, test.filter), ","); got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGiteaErrors(t *testing.T) {
+	srv, provider := newGitea(t)
+
+	wrongToken := sv.NewGiteaSv("wrong", srv.ApiUrl(), srv.Client(), t.TempDir(), ".*", "me", "repo")
+	var apiErr *sv.ApiError
+	if _, err := wrongToken.ListPullRequests(sv.PullRequestFilter{}); !errors.As(err, &apiErr) || apiErr.StatusCode != 401 {
+		t.Errorf("expected an authentication error, got %v", err)
+	}
+	if _, err := wrongToken.PullRequestStatus(); err == nil {
+		t.Error("the status should need a valid token")
+	}
+
+	missing := sv.NewGiteaSv("token", srv.ApiUrl(), srv.Client(), t.TempDir(), ".*", "me", "missing")
+	if _, err := missing.ListPullRequests(sv.PullRequestFilter{}); !errors.As(err, &apiErr) || apiErr.StatusCode != 404 {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if _, err := provider.GetPullRequest("42"); err == nil {
+		t.Error("reading a missing pull request should fail")
+	}
+	if _, err := provider.ListPullRequests(sv.PullRequestFilter{State: "unknown"}); err == nil {
+		t.Error("an unknown state should be refused")
+	}
+}
+
+func TestGiteaPullRequestStatus(t *testing.T) {
+	srv, provider := newGitea(t)
+	srv.AddPullRequest(giteaRepo, giteafake.PullRequest{Title: "mine", User: giteaMe(), Head: giteaBranch("a"), Base: giteaBranch("main")})
+	srv.AddStatus(giteaRepo, "a-sha", giteafake.Status{Status: "failure", Context: "ci"})
+	// The most recent status of a context wins
+	srv.AddStatus(giteaRepo, "a-sha", giteafake.Status{Status: "success", Context: "ci"})
+	srv.AddStatus(giteaRepo, "a-sha", giteafake.Status{Status: "pending", Context: "lint"})
+	reviewed := srv.AddPullRequest(giteaRepo, giteafake.PullRequest{Title: "to review", User: giteaAlice(), Head: giteaBranch("b"),
+		Base: giteaBranch("main"), RequestedReviewers: []*giteafake.User{giteaMe()}})
+	srv.AddReview(giteaRepo, reviewed, giteafake.Review{User: giteaMe(), State: "APPROVED"})
+	srv.AddPullRequest(giteaRepo, giteafake.PullRequest{Title: "not for me", User: giteaAlice(), Head: giteaBranch("c"), Base: giteaBranch("main")})
+
+	ch, err := provider.PullRequestStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := make([]sv.PullRequestStatus, 0)
+	for st := range ch {
+		statuses = append(statuses, st)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 pull requests, got %d", len(statuses))
+	}
+
+	if st := statuses[0]; st.GetTitle() != "mine" || !st.IsMine() || st.GetRepository() != giteaRepo || st.GetBranchName() != "a" {
+		t.Errorf("unexpected status %s %v %s %s", st.GetTitle(), st.IsMine(), st.GetRepository(), st.GetBranchName())
+	} else if contexts := st.GetContextByStatus(); contexts["SUCCESS"] != 1 || contexts["PENDING"] != 1 || contexts["ERROR"] != 0 {
+		t.Errorf("unexpected contexts %v", contexts)
+	}
+	if st := statuses[1]; st.GetTitle() != "to review" || st.IsMine() || st.GetAuthor() != "alice" {
+		t.Errorf("unexpected status %s %v %s", st.GetTitle(), st.IsMine(), st.GetAuthor())
+	} else if reviews := st.GetReviews(); len(reviews) != 1 || reviews[0].GetState() != "APPROVED" {
+		t.Errorf("expected the approval, got %v", reviews)
+	}
+}
+
+func TestGiteaCreatePullRequest(t *testing.T) {
+	srv, provider := newGitea(t)
+
+	args := sv.CreatePullRequestArgs{
+		HeadBranch:  optional.NewString("feature"),
+		Title:       optional.NewString("Add the feature"),
+		Description: optional.NewString("Details"),
+		Labels:      []string{"bug"},
+		Reviewers:   []string{"alice"},
+		Draft:       true,
+	}
+	if _, err := provider.CreatePullRequest(args); err == nil || !strings.Contains(err.Error(), "bug") {
+		t.Errorf("a missing label should be reported, got %v", err)
+	}
+
+	args.CreateMissingLabels = true
+	st, err := provider.CreatePullRequest(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.IsMine() || st.GetBaseName() != "main" {
+		t.Errorf("the default branch should be the base, got %s", st.GetBaseName())
+	}
+
+	pr, ok := srv.PullRequest(giteaRepo, st.GetId().(int64))
+	if !ok {
+		t.Fatal("the pull request wasn't stored")
+	}
+	if pr.Title != "WIP: Add the feature" || !pr.Draft || pr.Body != "Details" || pr.Head.Ref != "feature" {
+		t.Errorf("unexpected pull request %+v", pr)
+	}
+	if len(pr.Labels) != 1 || pr.Labels[0].Name != "bug" || len(pr.RequestedReviewers) != 1 || pr.RequestedReviewers[0].Login != "alice" {
+		t.Errorf("unexpected labels %v or reviewers %v", pr.Labels, pr.RequestedReviewers)
+	}
+	if collaborators, err := provider.ListCollaborators(); err != nil || strings.Join(collaborators, ",") != "alice" {
+		t.Errorf("unexpected collaborators %v %v", collaborators, err)
+	}
+}
+
+func TestGiteaComments(t *testing.T) {
+	srv, provider := newGitea(t)
+	number := srv.AddPullRequest(giteaRepo, giteafake.PullRequest{Title: "pr", User: giteaAlice(), Head: giteaBranch("a"), Base: giteaBranch("main")})
+	pr, err := provider.GetPullRequest("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a pending review, the comments are posted right away
+	newSide, err := pr.CreateComment("main.go", pr.GetLastCommitId(), 12, true, "on the new side")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pr.ReplyToComment(newSide, "a reply"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pr.CreateComment("main.go", pr.GetLastCommitId(), 3, false, "on the old side"); err != nil {
+		t.Fatal(err)
+	}
+	if reviews := srv.Reviews(giteaRepo, number); len(reviews) != 3 || reviews[0].State != "COMMENT" {
+		t.Errorf("expected a review per comment, got %v", reviews)
+	}
+
+	prComments, byLine, err := pr.GetCommentsByLine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prComments) != 0 {
+		t.Errorf("expected no general comments, got %v", prComments)
+	}
+	thread := byLine["main.go"][-12]
+	if len(thread) != 2 || thread[1].GetContent().GetRaw() != "a reply" || thread[1].GetParentId() != newSide.GetId() {
+		t.Errorf("expected the comment and its reply on the new line 12, got %v", thread)
+	}
+	if old := byLine["main.go"][3]; len(old) != 1 || old[0].GetUser().GetDisplayName() != "me" {
+		t.Errorf("expected the comment on the old line 3, got %v", old)
+	}
+}
+
+func TestGiteaReview(t *testing.T) {
+	srv, provider := newGitea(t)
+	number := srv.AddPullRequest(giteaRepo, giteafake.PullRequest{Title: "pr", User: giteaAlice(), Head: giteaBranch("a"), Base: giteaBranch("main")})
+	pr, err := provider.GetPullRequest("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rev, err := pr.StartReview()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, err := pr.StartReview(); err != nil || again.GetId() != rev.GetId() {
+		t.Errorf("the pending review should be reused, got %v %v", again, err)
+	}
+	// The comments go to the pending review
+	if _, err := pr.CreateComment("main.go", pr.GetLastCommitId(), 1, true, "in the review"); err != nil {
+		t.Fatal(err)
+	}
+	body := "changes needed"
+	if err := rev.RequestChanges(&body); err != nil {
+		t.Fatal(err)
+	}
+	if pending, err := pr.GetPendingReview(); err != nil || pending != nil {
+		t.Errorf("the review should be submitted, got %v %v", pending, err)
+	}
+
+	reviews := srv.Reviews(giteaRepo, number)
+	if len(reviews) != 1 || reviews[0].State != "REQUEST_CHANGES" || reviews[0].Body != body || reviews[0].CommentsCount != 1 {
+		t.Fatalf("unexpected reviews %+v", reviews)
+	}
+
+	submitted, err := pr.GetReviews()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := submitted[0].Dismiss(); err != nil {
+		t.Fatal(err)
+	}
+	if submitted, err = pr.GetReviews(); err != nil || submitted[0].GetState() != "DISMISSED" {
+		t.Errorf("the review should be dismissed, got %v %v", submitted, err)
+	}
+
+	// A cancelled review is deleted
+	if rev, err = pr.StartReview(); err != nil {
+		t.Fatal(err)
+	} else if err := rev.Cancel(); err != nil {
+		t.Fatal(err)
+	}
+	if reviews := srv.Reviews(giteaRepo, number); len(reviews) != 1 {
+		t.Errorf("the cancelled review should be deleted, got %v", reviews)
+	}
+}
+
+func TestGiteaMerge(t *testing.T) {
+	srv, provider := newGitea(t)
+	number := srv.AddPullRequest(giteaRepo, giteafake.PullRequest{Title: "pr", User: giteaMe(), Head: giteaBranch("a"), Base: giteaBranch("main")})
+	pr, err := provider.GetPullRequest("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pr.Merge(sv.MergeOptions{Method: "octopus"}); err == nil {
+		t.Error("an unknown method should be refused")
+	}
+	if err := pr.Merge(sv.MergeOptions{Auto: true}); err != nil {
+		t.Fatal(err)
+	} else if p, _ := srv.PullRequest(giteaRepo, number); p.Merged {
+		t.Error("the merge should wait for the checks")
+	}
+	if err := pr.Merge(sv.MergeOptions{Method: "squash"}); err != nil {
+		t.Fatal(err)
+	} else if p, _ := srv.PullRequest(giteaRepo, number); !p.Merged || p.State != "closed" {
+		t.Errorf("the pull request should be merged, got %s %v", p.State, p.Merged)
+	}
+
+	var apiErr *sv.ApiError
+	if err := pr.Merge(sv.MergeOptions{}); !errors.As(err, &apiErr) || apiErr.StatusCode != 405 {
+		t.Errorf("merging twice should fail, got %v", err)
+	}
+	if err := pr.Reopen(); err == nil {
+		t.Error("a merged pull request can't be reopened")
+	}
+}
+
+func TestGiteaCloseReopenAndDraft(t *testing.T) {
+	srv, provider := newGitea(t)
+	number := srv.AddPullRequest(giteaRepo, giteafake.PullRequest{Title: "pr", User: giteaMe(), Head: giteaBranch("a"), Base: giteaBranch("main")})
+	pr, err := provider.GetPullRequest("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pr.Close(); err != nil {
+		t.Fatal(err)
+	} else if p, _ := srv.PullRequest(giteaRepo, number); p.State != "closed" {
+		t.Errorf("expected closed, got %s", p.State)
+	}
+	if err := pr.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if p, _ := srv.PullRequest(giteaRepo, number); p.State != "open" {
+		t.Errorf("expected open, got %s", p.State)
+	}
+
+	if err := pr.SetDraft(true); err != nil {
+		t.Fatal(err)
+	}
+	if pr, err = provider.GetPullRequest("1"); err != nil {
+		t.Fatal(err)
+	} else if !pr.IsDraft() || pr.GetTitle() != "WIP: pr" {
+		t.Errorf("expected a draft, got %s", pr.GetTitle())
+	}
+	if err := pr.SetDraft(false); err != nil {
+		t.Fatal(err)
+	} else if p, _ := srv.PullRequest(giteaRepo, number); p.Draft || p.Title != "pr" {
+		t.Errorf("expected ready for review, got %s", p.Title)
+	}
+}
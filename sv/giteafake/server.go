@@ -0,0 +1,809 @@
+// Package giteafake is an in-memory Gitea server, implementing the part of the REST api used by sv.GiteaSv.
+// It allows exercising the provider offline:
+//
+//	srv := giteafake.NewServer("token", giteafake.User{Id: 1, Login: "me"})
+//	defer srv.Close()
+//	srv.AddRepository("me", "repo", "main")
+//	provider := sv.NewGiteaSv("token", srv.ApiUrl(), srv.Client(), ".", ".*", "me", "repo")
+package giteafake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type User struct {
+	Id       int64  `json:"id"`
+	Login    string `json:"login"`
+	FullName string `json:"full_name"`
+}
+
+type Repository struct {
+	Id            int64  `json:"id"`
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Owner         *User  `json:"owner"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+type BranchRef struct {
+	Label string      `json:"label"`
+	Ref   string      `json:"ref"`
+	Sha   string      `json:"sha"`
+	Repo  *Repository `json:"repo"`
+}
+
+type Label struct {
+	Id    int64  `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type PullRequest struct {
+	Id                 int64      `json:"id"`
+	Number             int64      `json:"number"`
+	Title              string     `json:"title"`
+	Body               string     `json:"body"`
+	State              string     `json:"state"`
+	Draft              bool       `json:"draft"`
+	Merged             bool       `json:"merged"`
+	User               *User      `json:"user"`
+	Labels             []Label    `json:"labels"`
+	RequestedReviewers []*User    `json:"requested_reviewers"`
+	Assignees          []*User    `json:"assignees"`
+	Head               *BranchRef `json:"head"`
+	Base               *BranchRef `json:"base"`
+	MergeBase          string     `json:"merge_base"`
+	HtmlUrl            string     `json:"html_url"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+type Review struct {
+	Id            int64     `json:"id"`
+	User          *User     `json:"user"`
+	State         string    `json:"state"`
+	Body          string    `json:"body"`
+	CommitId      string    `json:"commit_id"`
+	Dismissed     bool      `json:"dismissed"`
+	CommentsCount int       `json:"comments_count"`
+	SubmittedAt   time.Time `json:"submitted_at"`
+
+	comments []*Comment
+}
+
+type Comment struct {
+	Id               int64     `json:"id"`
+	Body             string    `json:"body"`
+	User             *User     `json:"user"`
+	CreatedAt        time.Time `json:"created_at"`
+	Path             string    `json:"path,omitempty"`
+	CommitId         string    `json:"commit_id,omitempty"`
+	Position         int64     `json:"position,omitempty"`
+	OriginalPosition int64     `json:"original_position,omitempty"`
+	ReviewId         int64     `json:"pull_request_review_id,omitempty"`
+}
+
+type Status struct {
+	Id          int64     `json:"id"`
+	Status      string    `json:"status"`
+	TargetUrl   string    `json:"target_url"`
+	Description string    `json:"description"`
+	Context     string    `json:"context"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type repository struct {
+	*Repository
+	labels   []*Label
+	pulls    []*pullRequest
+	statuses map[string][]*Status
+}
+
+type pullRequest struct {
+	*PullRequest
+	repo     *repository
+	reviews  []*Review
+	comments []*Comment
+}
+
+// Server is the fake Gitea instance, all its methods are safe for concurrent use
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	nextId int64
+	tokens map[string]*User
+	users  map[string]*User
+	repos  map[string]*repository
+}
+
+// NewServer starts a server where token authenticates user
+func NewServer(token string, user User) *Server {
+	s := &Server{
+		tokens: make(map[string]*User),
+		users:  make(map[string]*User),
+		repos:  make(map[string]*repository),
+	}
+	s.AddUser(token, user)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// ApiUrl is the base url to give to sv.NewGiteaSv
+func (s *Server) ApiUrl() string {
+	return s.URL + "/api/v1"
+}
+
+func (s *Server) newId() int64 {
+	s.nextId += 1
+	return s.nextId
+}
+
+// AddUser registers another user, authenticated by token if not empty
+func (s *Server) AddUser(token string, user User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := user
+	s.users[u.Login] = &u
+	if len(token) > 0 {
+		s.tokens[token] = &u
+	}
+}
+
+func (s *Server) AddRepository(owner string, name string, defaultBranch string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.users[owner]
+	if !ok {
+		o = &User{Id: s.newId(), Login: owner}
+		s.users[owner] = o
+	}
+	fullName := owner + "/" + name
+	s.repos[fullName] = &repository{
+		Repository: &Repository{Id: s.newId(), Name: name, FullName: fullName, Owner: o, DefaultBranch: defaultBranch},
+		statuses:   make(map[string][]*Status),
+	}
+}
+
+// AddPullRequest stores pr in the repository, its number is assigned when zero and returned
+func (s *Server) AddPullRequest(fullName string, pr PullRequest) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addPullRequest(s.repos[fullName], pr).Number
+}
+
+func (s *Server) addPullRequest(repo *repository, pr PullRequest) *pullRequest {
+	p := pr
+	p.Id = s.newId()
+	if p.Number == 0 {
+		p.Number = int64(len(repo.pulls) + 1)
+	}
+	if len(p.State) == 0 {
+		p.State = "open"
+	}
+	if len(p.HtmlUrl) == 0 {
+		p.HtmlUrl = fmt.Sprintf("%s/%s/pulls/%d", s.URL, repo.FullName, p.Number)
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = time.Now()
+	}
+	if p.UpdatedAt.IsZero() {
+		p.UpdatedAt = p.CreatedAt
+	}
+	if p.Head != nil {
+		p.Head.Repo = repo.Repository
+	}
+	if p.Base != nil {
+		p.Base.Repo = repo.Repository
+	}
+	res := &pullRequest{PullRequest: &p, repo: repo}
+	repo.pulls = append(repo.pulls, res)
+	return res
+}
+
+// AddStatus adds a commit status on sha, the most recent status comes first as with Gitea
+func (s *Server) AddStatus(fullName string, sha string, status Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := status
+	st.Id = s.newId()
+	if st.CreatedAt.IsZero() {
+		st.CreatedAt = time.Now()
+	}
+	repo := s.repos[fullName]
+	repo.statuses[sha] = append([]*Status{&st}, repo.statuses[sha]...)
+}
+
+// AddReview adds a submitted review, with its inline comments
+func (s *Server) AddReview(fullName string, number int64, review Review, comments ...Comment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pr := s.findPull(s.repos[fullName], number); pr != nil {
+		r := review
+		r.Id = s.newId()
+		for i := range comments {
+			c := comments[i]
+			c.Id = s.newId()
+			c.ReviewId = r.Id
+			if c.User == nil {
+				c.User = r.User
+			}
+			r.comments = append(r.comments, &c)
+		}
+		r.CommentsCount = len(r.comments)
+		pr.reviews = append(pr.reviews, &r)
+	}
+}
+
+// PullRequest returns a copy of the pull request, as currently stored
+func (s *Server) PullRequest(fullName string, number int64) (PullRequest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pr := s.findPull(s.repos[fullName], number); pr != nil {
+		return *pr.PullRequest, true
+	}
+	return PullRequest{}, false
+}
+
+// Reviews returns copies of the reviews of the pull request
+func (s *Server) Reviews(fullName string, number int64) []Review {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Review, 0)
+	if pr := s.findPull(s.repos[fullName], number); pr != nil {
+		for _, r := range pr.reviews {
+			res = append(res, *r)
+		}
+	}
+	return res
+}
+
+func (s *Server) findPull(repo *repository, number int64) *pullRequest {
+	if repo == nil {
+		return nil
+	}
+	for _, p := range repo.pulls {
+		if p.Number == number {
+			return p
+		}
+	}
+	return nil
+}
+
+func writeJson(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if body != nil {
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJson(w, status, map[string]string{"message": fmt.Sprintf(format, args...)})
+}
+
+// paginate applies the page and limit parameters
+func paginate[T any](r *http.Request, items []T) []T {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 30
+	}
+	start := (page - 1) * limit
+	if start >= len(items) {
+		return make([]T, 0)
+	}
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "token ")]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "token is required")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case path == "/user":
+		writeJson(w, http.StatusOK, user)
+	case path == "/repos/issues/search":
+		s.searchIssues(w, r, user)
+	case len(parts) >= 3 && parts[0] == "repos":
+		repo, ok := s.repos[parts[1]+"/"+parts[2]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "repository %s/%s not found", parts[1], parts[2])
+			return
+		}
+		s.serveRepository(w, r, user, repo, parts[3:])
+	default:
+		writeError(w, http.StatusNotFound, "no route for %s", path)
+	}
+}
+
+func (s *Server) serveRepository(w http.ResponseWriter, r *http.Request, user *User, repo *repository, parts []string) {
+	switch {
+	case len(parts) == 0:
+		writeJson(w, http.StatusOK, repo.Repository)
+	case len(parts) == 1 && parts[0] == "labels" && r.Method == http.MethodGet:
+		writeJson(w, http.StatusOK, paginate(r, repo.labels))
+	case len(parts) == 1 && parts[0] == "labels" && r.Method == http.MethodPost:
+		label := &Label{}
+		if err := json.NewDecoder(r.Body).Decode(label); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "%v", err)
+			return
+		}
+		label.Id = s.newId()
+		repo.labels = append(repo.labels, label)
+		writeJson(w, http.StatusCreated, label)
+	case len(parts) == 1 && parts[0] == "collaborators" && r.Method == http.MethodGet:
+		// Every user but the owner collaborates
+		users := make([]*User, 0)
+		for _, u := range s.users {
+			if u.Login != repo.Owner.Login {
+				users = append(users, u)
+			}
+		}
+		sort.Slice(users, func(i, j int) bool { return users[i].Login < users[j].Login })
+		writeJson(w, http.StatusOK, paginate(r, users))
+	case len(parts) == 3 && parts[0] == "commits" && parts[2] == "statuses":
+		writeJson(w, http.StatusOK, paginate(r, repo.statuses[parts[1]]))
+	case len(parts) == 3 && parts[0] == "issues" && parts[2] == "comments":
+		number, _ := strconv.ParseInt(parts[1], 10, 64)
+		pr := s.findPull(repo, number)
+		if pr == nil {
+			writeError(w, http.StatusNotFound, "issue %s not found", parts[1])
+		} else if r.Method == http.MethodPost {
+			c := &Comment{}
+			if err := json.NewDecoder(r.Body).Decode(c); err != nil {
+				writeError(w, http.StatusUnprocessableEntity, "%v", err)
+				return
+			}
+			c.Id, c.User, c.CreatedAt = s.newId(), user, time.Now()
+			pr.comments = append(pr.comments, c)
+			writeJson(w, http.StatusCreated, c)
+		} else {
+			writeJson(w, http.StatusOK, paginate(r, pr.comments))
+		}
+	case len(parts) >= 3 && parts[0] == "issues" && parts[2] == "labels":
+		number, _ := strconv.ParseInt(parts[1], 10, 64)
+		if pr := s.findPull(repo, number); pr == nil {
+			writeError(w, http.StatusNotFound, "issue %s not found", parts[1])
+		} else {
+			s.serveIssueLabels(w, r, repo, pr, parts[3:])
+		}
+	case len(parts) == 1 && parts[0] == "pulls" && r.Method == http.MethodGet:
+		state := r.URL.Query().Get("state")
+		pulls := make([]*PullRequest, 0)
+		for _, p := range repo.pulls {
+			if state == "" || state == "all" || p.State == state {
+				pulls = append(pulls, p.PullRequest)
+			}
+		}
+		writeJson(w, http.StatusOK, paginate(r, pulls))
+	case len(parts) == 1 && parts[0] == "pulls" && r.Method == http.MethodPost:
+		s.createPull(w, r, user, repo)
+	case len(parts) >= 2 && parts[0] == "pulls":
+		number, _ := strconv.ParseInt(parts[1], 10, 64)
+		if pr := s.findPull(repo, number); pr == nil {
+			writeError(w, http.StatusNotFound, "pull request %s not found", parts[1])
+		} else {
+			s.servePull(w, r, user, pr, parts[2:])
+		}
+	default:
+		writeError(w, http.StatusNotFound, "no route for %s", r.URL.Path)
+	}
+}
+
+func (s *Server) serveIssueLabels(w http.ResponseWriter, r *http.Request, repo *repository, pr *pullRequest, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		opts := struct {
+			Labels []int64 `json:"labels"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "%v", err)
+			return
+		}
+		for _, id := range opts.Labels {
+			found := false
+			for _, l := range repo.labels {
+				if l.Id == id {
+					found = true
+					if !hasLabel(pr.Labels, id) {
+						pr.Labels = append(pr.Labels, *l)
+					}
+				}
+			}
+			if !found {
+				writeError(w, http.StatusUnprocessableEntity, "label %d doesn't exist", id)
+				return
+			}
+		}
+		writeJson(w, http.StatusOK, pr.Labels)
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		id, _ := strconv.ParseInt(parts[0], 10, 64)
+		labels := make([]Label, 0)
+		for _, l := range pr.Labels {
+			if l.Id != id {
+				labels = append(labels, l)
+			}
+		}
+		pr.Labels = labels
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "no route for %s", r.URL.Path)
+	}
+}
+
+func hasLabel(labels []Label, id int64) bool {
+	for _, l := range labels {
+		if l.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// isDraftTitle tells the drafts by the prefix of their title, as Gitea does
+func isDraftTitle(title string) bool {
+	return strings.HasPrefix(strings.ToUpper(title), "WIP:") || strings.HasPrefix(strings.ToUpper(title), "[WIP]")
+}
+
+func (s *Server) createPull(w http.ResponseWriter, r *http.Request, user *User, repo *repository) {
+	opts := struct {
+		Head   string  `json:"head"`
+		Base   string  `json:"base"`
+		Title  string  `json:"title"`
+		Body   string  `json:"body"`
+		Labels []int64 `json:"labels"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "%v", err)
+		return
+	}
+
+	labels := make([]Label, 0)
+	for _, id := range opts.Labels {
+		for _, l := range repo.labels {
+			if l.Id == id {
+				labels = append(labels, *l)
+			}
+		}
+	}
+
+	pr := s.addPullRequest(repo, PullRequest{
+		Title:  opts.Title,
+		Body:   opts.Body,
+		Draft:  isDraftTitle(opts.Title),
+		User:   user,
+		Labels: labels,
+		Head:   &BranchRef{Label: opts.Head, Ref: opts.Head},
+		Base:   &BranchRef{Label: opts.Base, Ref: opts.Base},
+	})
+	writeJson(w, http.StatusCreated, pr.PullRequest)
+}
+
+func (s *Server) servePull(w http.ResponseWriter, r *http.Request, user *User, pr *pullRequest, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodPatch:
+		opts := struct {
+			Title     *string   `json:"title"`
+			Body      *string   `json:"body"`
+			Base      *string   `json:"base"`
+			State     *string   `json:"state"`
+			Assignees *[]string `json:"assignees"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "%v", err)
+			return
+		}
+		if opts.Title != nil {
+			pr.Title = *opts.Title
+			pr.Draft = isDraftTitle(pr.Title)
+		}
+		if opts.State != nil {
+			if pr.Merged {
+				writeError(w, http.StatusPreconditionFailed, "pull request is merged")
+				return
+			}
+			pr.State = *opts.State
+		}
+		if opts.Body != nil {
+			pr.Body = *opts.Body
+		}
+		if opts.Base != nil {
+			pr.Base = &BranchRef{Label: *opts.Base, Ref: *opts.Base, Repo: pr.Base.Repo}
+		}
+		if opts.Assignees != nil {
+			assignees := make([]*User, 0)
+			for _, login := range *opts.Assignees {
+				if u, ok := s.users[login]; ok {
+					assignees = append(assignees, u)
+				} else {
+					writeError(w, http.StatusUnprocessableEntity, "user %s doesn't exist", login)
+					return
+				}
+			}
+			pr.Assignees = assignees
+		}
+		pr.UpdatedAt = time.Now()
+		writeJson(w, http.StatusCreated, pr.PullRequest)
+	case len(parts) == 0:
+		writeJson(w, http.StatusOK, pr.PullRequest)
+	case len(parts) == 1 && parts[0] == "merge" && r.Method == http.MethodPost:
+		if pr.State != "open" {
+			writeError(w, http.StatusMethodNotAllowed, "pull request is closed")
+			return
+		}
+		opts := struct {
+			Do                     string `json:"Do"`
+			MergeWhenChecksSucceed bool   `json:"merge_when_checks_succeed"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "%v", err)
+			return
+		}
+		switch opts.Do {
+		case "merge", "rebase", "rebase-merge", "squash", "manually-merged":
+		default:
+			writeError(w, http.StatusUnprocessableEntity, "unknown merge style %s", opts.Do)
+			return
+		}
+		if opts.MergeWhenChecksSucceed {
+			// The fake has no checks running, the pull request stays open
+			writeJson(w, http.StatusCreated, nil)
+			return
+		}
+		pr.State, pr.Merged, pr.UpdatedAt = "closed", true, time.Now()
+		writeJson(w, http.StatusOK, nil)
+	case len(parts) == 1 && parts[0] == "requested_reviewers" && r.Method == http.MethodPost:
+		opts := struct {
+			Reviewers []string `json:"reviewers"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "%v", err)
+			return
+		}
+		for _, login := range opts.Reviewers {
+			if u, ok := s.users[login]; ok {
+				pr.RequestedReviewers = append(pr.RequestedReviewers, u)
+			} else {
+				writeError(w, http.StatusUnprocessableEntity, "user %s doesn't exist", login)
+				return
+			}
+		}
+		writeJson(w, http.StatusCreated, make([]Review, 0))
+	case len(parts) == 1 && parts[0] == "requested_reviewers" && r.Method == http.MethodDelete:
+		opts := struct {
+			Reviewers []string `json:"reviewers"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "%v", err)
+			return
+		}
+		removed := make(map[string]bool)
+		for _, login := range opts.Reviewers {
+			removed[login] = true
+		}
+		reviewers := make([]*User, 0)
+		for _, u := range pr.RequestedReviewers {
+			if !removed[u.Login] {
+				reviewers = append(reviewers, u)
+			}
+		}
+		pr.RequestedReviewers = reviewers
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 1 && parts[0] == "reviews" && r.Method == http.MethodGet:
+		// Pending reviews are only visible to their author
+		reviews := make([]*Review, 0)
+		for _, rev := range pr.reviews {
+			if rev.State != "PENDING" || rev.User.Id == user.Id {
+				reviews = append(reviews, rev)
+			}
+		}
+		writeJson(w, http.StatusOK, paginate(r, reviews))
+	case len(parts) == 1 && parts[0] == "reviews" && r.Method == http.MethodPost:
+		s.createReview(w, r, user, pr)
+	case len(parts) >= 2 && parts[0] == "reviews":
+		id, _ := strconv.ParseInt(parts[1], 10, 64)
+		for i, rev := range pr.reviews {
+			if rev.Id == id {
+				s.serveReview(w, r, user, pr, i, parts[2:])
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "review %s not found", parts[1])
+	default:
+		writeError(w, http.StatusNotFound, "no route for %s", r.URL.Path)
+	}
+}
+
+type reviewComment struct {
+	Path        string `json:"path"`
+	Body        string `json:"body"`
+	NewPosition int64  `json:"new_position"`
+	OldPosition int64  `json:"old_position"`
+}
+
+func (s *Server) toComment(user *User, rev *Review, c reviewComment) *Comment {
+	return &Comment{
+		Id:               s.newId(),
+		Body:             c.Body,
+		User:             user,
+		CreatedAt:        time.Now(),
+		Path:             c.Path,
+		CommitId:         rev.CommitId,
+		Position:         c.NewPosition,
+		OriginalPosition: c.OldPosition,
+		ReviewId:         rev.Id,
+	}
+}
+
+func (s *Server) createReview(w http.ResponseWriter, r *http.Request, user *User, pr *pullRequest) {
+	opts := struct {
+		Event    string          `json:"event"`
+		Body     string          `json:"body"`
+		CommitId string          `json:"commit_id"`
+		Comments []reviewComment `json:"comments"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "%v", err)
+		return
+	}
+
+	state := opts.Event
+	if state == "" {
+		state = "PENDING"
+	}
+	if state == "PENDING" {
+		for _, rev := range pr.reviews {
+			if rev.State == "PENDING" && rev.User.Id == user.Id {
+				writeError(w, http.StatusUnprocessableEntity, "user has already a pending review")
+				return
+			}
+		}
+	}
+
+	rev := &Review{Id: s.newId(), User: user, State: state, Body: opts.Body, CommitId: opts.CommitId}
+	if state != "PENDING" {
+		rev.SubmittedAt = time.Now()
+	}
+	for _, c := range opts.Comments {
+		rev.comments = append(rev.comments, s.toComment(user, rev, c))
+	}
+	rev.CommentsCount = len(rev.comments)
+	pr.reviews = append(pr.reviews, rev)
+	writeJson(w, http.StatusOK, rev)
+}
+
+func (s *Server) serveReview(w http.ResponseWriter, r *http.Request, user *User, pr *pullRequest, idx int, parts []string) {
+	rev := pr.reviews[idx]
+	// Pending reviews are only visible to their author
+	if rev.State == "PENDING" && rev.User.Id != user.Id {
+		writeError(w, http.StatusNotFound, "review %d not found", rev.Id)
+		return
+	}
+
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		writeJson(w, http.StatusOK, rev)
+	case len(parts) == 0 && r.Method == http.MethodDelete:
+		pr.reviews = append(pr.reviews[:idx], pr.reviews[idx+1:]...)
+		writeJson(w, http.StatusNoContent, nil)
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		opts := struct {
+			Event string `json:"event"`
+			Body  string `json:"body"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "%v", err)
+			return
+		} else if rev.State != "PENDING" {
+			writeError(w, http.StatusUnprocessableEntity, "review %d is already submitted", rev.Id)
+			return
+		}
+		rev.State, rev.Body, rev.SubmittedAt = opts.Event, opts.Body, time.Now()
+		writeJson(w, http.StatusOK, rev)
+	case len(parts) == 1 && parts[0] == "comments" && r.Method == http.MethodGet:
+		comments := make([]*Comment, len(rev.comments))
+		copy(comments, rev.comments)
+		sort.Slice(comments, func(i, j int) bool { return comments[i].Id < comments[j].Id })
+		writeJson(w, http.StatusOK, comments)
+	case len(parts) == 1 && parts[0] == "comments" && r.Method == http.MethodPost:
+		c := reviewComment{}
+		if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "%v", err)
+			return
+		}
+		cmt := s.toComment(user, rev, c)
+		rev.comments = append(rev.comments, cmt)
+		rev.CommentsCount = len(rev.comments)
+		writeJson(w, http.StatusOK, cmt)
+	case len(parts) == 1 && parts[0] == "dismissals" && r.Method == http.MethodPost:
+		rev.Dismissed = true
+		writeJson(w, http.StatusOK, rev)
+	default:
+		writeError(w, http.StatusNotFound, "no route for %s", r.URL.Path)
+	}
+}
+
+type issue struct {
+	Number     int64  `json:"number"`
+	Title      string `json:"title"`
+	Repository struct {
+		Owner    string `json:"owner"`
+		Name     string `json:"name"`
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+func (s *Server) searchIssues(w http.ResponseWriter, r *http.Request, user *User) {
+	q := r.URL.Query()
+	state := q.Get("state")
+
+	names := make([]string, 0, len(s.repos))
+	for name := range s.repos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	issues := make([]issue, 0)
+	for _, name := range names {
+		repo := s.repos[name]
+		for _, p := range repo.pulls {
+			if state != "" && state != "all" && p.State != state {
+				continue
+			}
+			if q.Get("created") == "true" && p.User.Id != user.Id {
+				continue
+			}
+			if q.Get("review_requested") == "true" && !isRequested(p, user) {
+				continue
+			}
+			itm := issue{Number: p.Number, Title: p.Title}
+			itm.Repository.Owner = repo.Owner.Login
+			itm.Repository.Name = repo.Name
+			itm.Repository.FullName = repo.FullName
+			issues = append(issues, itm)
+		}
+	}
+
+	writeJson(w, http.StatusOK, paginate(r, issues))
+}
+
+func isRequested(pr *pullRequest, user *User) bool {
+	for _, u := range pr.RequestedReviewers {
+		if u.Id == user.Id {
+			return true
+		}
+	}
+	return false
+}
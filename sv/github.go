@@ -3,28 +3,18 @@ package sv
 
 import "C"
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"github.com/Khan/genqlient/graphql"
 	"github.com/antihax/optional"
 	"github.com/bluekeyes/go-gitdiff/gitdiff"
-	"github.com/briandowns/spinner"
 	"github.com/cli/cli/v2/api"
-	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing"
-	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	gh "github.com/google/go-github/v43/github"
 	"github.com/pterm/pterm"
 	"github.com/shurcooL/githubv4"
 	"github.com/vballestra/sv/sv/gh_utils"
-	sshagent "github.com/xanzy/ssh-agent"
-	ssh2 "golang.org/x/crypto/ssh"
-	"golang.org/x/crypto/ssh/agent"
 	"golang.org/x/oauth2"
-	"net"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -40,7 +30,6 @@ type GitHubSv struct {
 	repo           string
 	tc             *http.Client
 	localRepo      string
-	host           string
 	sshKeySelector *regexp.Regexp
 }
 
@@ -67,7 +56,7 @@ func toUserIdsChan(repo *GitHubSv, logins []string) <-chan idOrError {
 	return ch
 }
 
-func toLabelIdsChan(repo *GitHubSv, labels []string) <-chan idOrError {
+func toLabelIdsChan(repo *GitHubSv, labels []string, createMissing bool) <-chan idOrError {
 	ch := make(chan idOrError)
 
 	go func() {
@@ -83,6 +72,9 @@ func toLabelIdsChan(repo *GitHubSv, labels []string) <-chan idOrError {
 					break
 				} else if resp.Repository.Label != nil {
 					ch <- idOrError{string: resp.Repository.Label.Id}
+				} else if !createMissing {
+					ch <- idOrError{error: fmt.Errorf("label '%s' doesn't exist", label)}
+					break
 				} else if resp2, err := createLabel(repo.ctx, label, nil, "a0a0a0", repoId); err != nil {
 					ch <- idOrError{error: err}
 					break
@@ -103,9 +95,9 @@ func toUserIds(repo *GitHubSv, logins []string) ([]string, error) {
 	})
 }
 
-func toLabelIds(repo *GitHubSv, labels []string) ([]string, error) {
+func toLabelIds(repo *GitHubSv, labels []string, createMissing bool) ([]string, error) {
 	return toIds(func() <-chan idOrError {
-		return toLabelIdsChan(repo, labels)
+		return toLabelIdsChan(repo, labels, createMissing)
 	})
 }
 
@@ -146,87 +138,45 @@ func (g *GitHubSv) resolveBaseBranch(baseBranch optional.String) (string, error)
 	}
 }
 
-func (g *GitHubSv) resolveTitleAndDescription(titleOpt optional.String, descriptionOpt optional.String, headBranch string, baseBranch string) (string, string, error) {
-	if titleOpt.IsSet() && descriptionOpt.IsSet() {
-		return titleOpt.Value(), descriptionOpt.Value(), nil
-	}
-
-	// Check if we only have one commit
-	rep, err := git.PlainOpen(g.localRepo)
-	if err != nil {
-		return "", "", err
-	}
-
-	// Get Head Commit
-	br, err := rep.Branch(headBranch)
-	if err != nil {
-		return "", "", err
-	}
-	hdRef, err := rep.Reference(br.Merge, true)
-	if err != nil {
-		return "", "", err
-	}
-	hdCommit, err := rep.CommitObject(hdRef.Hash())
-	if err != nil {
-		return "", "", err
-	}
+func (g *GitHubSv) ResolveTitleAndDescription(remote string, titleOpt optional.String, descriptionOpt optional.String, headBranch string, baseBranch string) (string, string, error) {
+	return resolveTitleAndDescription(g.localRepo, gitHubTemplates, remote, titleOpt, descriptionOpt, headBranch, baseBranch)
+}
 
-	// Get Base Commit
-	ba, err := rep.Branch(baseBranch)
-	if err != nil {
-		return "", "", err
-	}
-	baRef, err := rep.Reference(ba.Merge, true)
-	if err != nil {
-		return "", "", err
-	}
-	baCommit, err := rep.CommitObject(baRef.Hash())
-	if err != nil {
-		return "", "", err
+func (g *GitHubSv) ListCollaborators() ([]string, error) {
+	logins := make([]string, 0)
+	opts := &gh.ListCollaboratorsOptions{ListOptions: gh.ListOptions{Page: 1, PerPage: 100}}
+	for opts.Page != 0 {
+		users, resp, err := g.client.Repositories.ListCollaborators(g.ctx, g.owner, g.repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			logins = append(logins, u.GetLogin())
+		}
+		opts.Page = resp.NextPage
 	}
+	return logins, nil
+}
 
-	mbs, err := hdCommit.MergeBase(baCommit)
-	if err != nil {
-		return "", "", err
-	}
-	if len(mbs) != 1 {
-		return "", "", fmt.Errorf("cannot find unique common base between %v and %v", headBranch, baseBranch)
-	}
-	mb := mbs[0]
-	isValid := object.CommitFilter(func(commit *object.Commit) bool {
-		if commit.ID() == mb.ID() {
-			return false
-		} else if isAncestor, err := mb.IsAncestor(commit); err == nil {
-			return isAncestor
-		} else {
-			return false
+func (g *GitHubSv) ListLabels() ([]string, error) {
+	names := make([]string, 0)
+	opts := &gh.ListOptions{Page: 1, PerPage: 100}
+	for opts.Page != 0 {
+		labels, resp, err := g.client.Issues.ListLabels(g.ctx, g.owner, g.repo, opts)
+		if err != nil {
+			return nil, err
 		}
-	})
-	isLimit := object.CommitFilter(func(c *object.Commit) bool {
-		return !isValid(c)
-	})
-
-	iter := object.NewFilterCommitIter(hdCommit, &isValid, &isLimit)
-	logs := make([]string, 0)
-	_ = iter.ForEach(func(commit *object.Commit) error {
-		logs = append(logs, strings.Split(commit.Message, "\n")[0])
-		return nil
-	})
-
-	lastIdx := len(logs) - 1
-	if lastIdx > 0 {
-		return titleOpt.Default(logs[lastIdx]), descriptionOpt.Default(strings.Join(logs[0:lastIdx], "\n")), nil
-	} else if lastIdx == 0 {
-		return titleOpt.Default(logs[lastIdx]), "", nil
-	} else {
-		return "", "", fmt.Errorf("no commits from %v to %v, cannot infer pr title", baseBranch, headBranch)
+		for _, l := range labels {
+			names = append(names, l.GetName())
+		}
+		opts.Page = resp.NextPage
 	}
-
+	return names, nil
 }
 
 func (g *GitHubSv) CreatePullRequest(args CreatePullRequestArgs) (PullRequestStatus, error) {
 	// First get the repository id
-	if labelIds, err := toLabelIds(g, args.Labels); err != nil {
+	if labelIds, err := toLabelIds(g, args.Labels, args.CreateMissingLabels); err != nil {
 		return nil, err
 	} else if reviewerIds, err := toUserIds(g, args.Reviewers); err != nil {
 		return nil, err
@@ -236,9 +186,9 @@ func (g *GitHubSv) CreatePullRequest(args CreatePullRequestArgs) (PullRequestSta
 		return nil, err
 	} else if baseBranch, err := g.resolveBaseBranch(args.BaseBranch); err != nil {
 		return nil, err
-	} else if title, description, err := g.resolveTitleAndDescription(args.Title, args.Description, headBranch, baseBranch); err != nil {
+	} else if title, description, err := g.ResolveTitleAndDescription(args.Remote, args.Title, args.Description, headBranch, baseBranch); err != nil {
 		return nil, err
-	} else if resp2, err := createPullRequest(g.ctx, resp.Repository.Id, headBranch, baseBranch, title, &description); err != nil {
+	} else if resp2, err := createPullRequest(g.ctx, resp.Repository.Id, headBranch, baseBranch, title, &description, &args.Draft); err != nil {
 		return nil, err
 	} else {
 		pr := resp2.CreatePullRequest.PullRequest.singleStatusPullRequest
@@ -415,67 +365,34 @@ func (g GitHubPullRequestStatusWrapper) GetContextByStatus() map[string]int {
 }
 
 func (g *GitHubSv) GetCurrentBranch() (string, error) {
-	if rep, err := git.PlainOpen(g.localRepo); err != nil {
-		return "", err
-	} else if hd, err := rep.Head(); err != nil {
-		return "", err
-	} else if hd.Name().IsBranch() {
-		return hd.Name().Short(), nil
-	} else {
-		return "", fmt.Errorf("current reference '%v' is not a branch", hd.Name())
-	}
+	return getCurrentBranch(g.localRepo)
 }
 
-func (g *GitHubSv) Fetch() error {
-	rep, giterr := git.PlainOpen(g.localRepo)
-	if giterr != nil {
-		return giterr
-	}
-	if sshagent.Available() {
-
-		if a, _, err := sshagent.New(); err != nil {
-			return fmt.Errorf("error creating SSH agent: %w", err)
-		} else if sigs, err := a.Signers(); err != nil {
-			return fmt.Errorf("while getting signers : %w", err)
-		} else {
-			newSigs := make([]ssh2.Signer, 0)
-			for _, s := range sigs {
-				if k, ok := s.PublicKey().(*agent.Key); ok {
-					if g.sshKeySelector.MatchString(k.Comment) {
-						newSigs = append(newSigs, s)
-					}
-				}
-			}
-
-			if len(newSigs) > 0 {
-				ag := &ssh.PublicKeysCallback{
-					User: "git",
-					Callback: func() ([]ssh2.Signer, error) {
-						return newSigs, nil
-					},
-				}
-
-				ag.HostKeyCallback = func(hostname string, remote net.Addr, key ssh2.PublicKey) error {
-					return nil
-				}
+func (g *GitHubSv) Fetch(remote string) error {
+	return fetchWithAgent(g.localRepo, remote, g.sshKeySelector)
+}
 
-				sp := spinner.New(spinner.CharSets[55], time.Millisecond*50, spinner.WithSuffix(fmt.Sprintf(" Updating repository")))
-				sp.Start()
-				err = rep.Fetch(&git.FetchOptions{RemoteName: "origin", Auth: ag})
-				sp.Stop()
+func (g *GitHubSv) PushBranch(remote string, branch string, force bool) (bool, error) {
+	return pushBranch(g.localRepo, g.sshKeySelector, remote, branch, force)
+}
 
-				return err
+// CheckoutPullRequest fetches refs/pull/N/head, that works for the forks as well
+func (g *GitHubSv) CheckoutPullRequest(remote string, id string, force bool) (string, error) {
+	pr, err := g.GetPullRequest(id)
+	if err != nil {
+		return "", err
+	}
+	ghPr := pr.(GitHubPullRequest)
 
-			} else {
-				return fmt.Errorf("couldn't find any suitable keys, won't try to fetch the repo '%s'", g.localRepo)
-			}
-		}
-	} else {
-		return errors.New("please use ssh agent")
+	head := pullRequestHead{id: id, remote: remote, ref: fmt.Sprintf("refs/heads/%s", ghPr.GetHead().GetRef()), branch: ghPr.GetHead().GetRef()}
+	if ghPr.GetHead().GetRepo().GetFullName() != ghPr.PullRequest.GetBase().GetRepo().GetFullName() {
+		head.ref, head.branch = fmt.Sprintf("refs/pull/%d/head", ghPr.GetNumber()), forkBranch(ghPr.GetNumber(), head.branch)
 	}
+	return head.branch, checkoutHead(g.localRepo, g.sshKeySelector, head, force)
 }
 
 const githubDefaultHost = "github.com"
+const githubDefaultApiUrl = "https://api.github.com/"
 const githubDefaultGraphQLUrl = "https://api.github.com/graphql"
 
 type GitHubExtendedHttpClient struct {
@@ -497,20 +414,48 @@ func (g *GitHubSv) GetPullRequest(id string) (PullRequest, error) {
 	return GitHubPullRequest{pr, g}, nil
 }
 
-func NewGitHubSv(token string, repo string, sshKeyComment string, owner string, name string) Sv {
+// GitHubApiUrls returns the REST and GraphQL endpoints of a host, which is either github.com or a GitHub
+// Enterprise Server
+func GitHubApiUrls(host string) (string, string) {
+	if host == "" || host == githubDefaultHost {
+		return githubDefaultApiUrl, githubDefaultGraphQLUrl
+	}
+	return fmt.Sprintf("https://%s/api/v3/", host), fmt.Sprintf("https://%s/api/graphql", host)
+}
+
+// wrapTransport wraps the transport under the oauth2 one, so that the requests are seen with their token
+func (g *GitHubSv) wrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	if t, ok := g.tc.Transport.(*oauth2.Transport); ok {
+		t.Base = wrap(t.Base)
+	} else {
+		g.tc.Transport = wrap(g.tc.Transport)
+	}
+}
+
+// NewGitHubSv creates a GitHub provider talking to the REST api at apiUrl and to the GraphQL one at graphQLUrl,
+// see GitHubApiUrls for the default ones.
+func NewGitHubSv(token string, apiUrl string, graphQLUrl string, repo string, sshKeyComment string, owner string, name string) Sv {
 	ctx := context.Background()
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
 	tc := oauth2.NewClient(ctx, ts)
 
-	cl := gh.NewClient(tc)
+	var cl *gh.Client
+	if apiUrl == githubDefaultApiUrl {
+		cl = gh.NewClient(tc)
+	} else if c, err := gh.NewEnterpriseClient(apiUrl, strings.Replace(apiUrl, "/api/v3", "/api/uploads", 1), tc); err != nil {
+		pterm.Fatal.Println("Invalid GitHub api url '", apiUrl, "'", err)
+		panic(err)
+	} else {
+		cl = c
+	}
 
 	// Reading owner and repo from local workspace remote
 
 	if re, err := regexp.Compile(sshKeyComment); err == nil {
 
-		cl2 := graphql.NewClient(githubDefaultGraphQLUrl, GitHubExtendedHttpClient{tc})
+		cl2 := graphql.NewClient(graphQLUrl, GitHubExtendedHttpClient{tc})
 		ctx = gh_utils.InitContext(ctx, cl2)
 
 		return &GitHubSv{
@@ -521,7 +466,6 @@ func NewGitHubSv(token string, repo string, sshKeyComment string, owner string,
 			tc:             tc,
 			localRepo:      repo,
 			gqlClient:      api.NewClientFromHTTP(tc),
-			host:           githubDefaultHost,
 			sshKeySelector: re,
 		}
 	} else {
@@ -530,8 +474,33 @@ func NewGitHubSv(token string, repo string, sshKeyComment string, owner string,
 	}
 }
 
-func (g *GitHubSv) ListPullRequests(_ string) (<-chan PullRequest, error) {
-	opts := &gh.PullRequestListOptions{}
+// GitHubLogin returns the login of the token owner
+func GitHubLogin(token string, apiUrl string, graphQLUrl string) (string, error) {
+	return NewGitHubSv(token, apiUrl, graphQLUrl, "", ".*", "", "").(*GitHubSv).currentLogin()
+}
+
+// ListPullRequests uses the pulls endpoint when it's enough, the search otherwise
+func (g *GitHubSv) ListPullRequests(filter PullRequestFilter) (<-chan PullRequest, error) {
+	if filter.Author != "" || filter.Reviewer != "" || filter.Label != "" || filter.Query != "" || filter.Draft.IsSet() ||
+		!filter.UpdatedSince.IsZero() {
+		return g.searchPullRequests(githubSearchQuery(g.owner, g.repo, filter), filter.MaxPages)
+	}
+
+	opts := &gh.PullRequestListOptions{State: "open", Base: filter.Base}
+	switch filter.State {
+	case "", "open":
+	case "closed", "merged", "all":
+		opts.State = filter.State
+		if filter.State != "all" {
+			opts.State = "closed"
+		}
+	default:
+		return nil, fmt.Errorf("unknown state '%s'", filter.State)
+	}
+	if filter.Head != "" {
+		// The head is user:branch
+		opts.Head = fmt.Sprintf("%s:%s", g.owner, filter.Head)
+	}
 	opts.Page = 1
 	if res, resp, err := g.client.PullRequests.List(g.ctx, g.owner, g.repo, opts); err != nil {
 		return nil, err
@@ -542,8 +511,15 @@ func (g *GitHubSv) ListPullRequests(_ string) (<-chan PullRequest, error) {
 		go func() {
 			for len(res) > 0 && err == nil {
 				for _, pr := range res {
+					// The pulls endpoint mixes the merged and the closed ones
+					if (filter.State == "closed" && pr.MergedAt != nil) || (filter.State == "merged" && pr.MergedAt == nil) {
+						continue
+					}
 					ch <- GitHubPullRequest{pr, g}
 				}
+				if filter.MaxPages > 0 && opts.Page >= filter.MaxPages {
+					break
+				}
 				opts.Page += 1
 				res, _, err = g.client.PullRequests.List(g.ctx, g.owner, g.repo, opts)
 			}
@@ -554,13 +530,245 @@ func (g *GitHubSv) ListPullRequests(_ string) (<-chan PullRequest, error) {
 	}
 }
 
+// githubSearchQuery translates the filter in the GitHub search syntax
+func githubSearchQuery(owner string, repo string, filter PullRequestFilter) string {
+	terms := []string{fmt.Sprintf("repo:%s/%s", owner, repo), "is:pr"}
+	switch filter.State {
+	case "", "open":
+		terms = append(terms, "is:open")
+	case "closed":
+		terms = append(terms, "is:closed", "is:unmerged")
+	case "merged":
+		terms = append(terms, "is:merged")
+	}
+	if filter.Author != "" {
+		terms = append(terms, "author:"+filter.Author)
+	}
+	if filter.Reviewer != "" {
+		terms = append(terms, "review-requested:"+filter.Reviewer)
+	}
+	if filter.Label != "" {
+		terms = append(terms, fmt.Sprintf("label:%q", filter.Label))
+	}
+	if filter.Base != "" {
+		terms = append(terms, "base:"+filter.Base)
+	}
+	if filter.Head != "" {
+		terms = append(terms, "head:"+filter.Head)
+	}
+	if filter.Draft.IsSet() {
+		terms = append(terms, fmt.Sprintf("draft:%t", filter.Draft.Value()))
+	}
+	if !filter.UpdatedSince.IsZero() {
+		terms = append(terms, "updated:>="+filter.UpdatedSince.UTC().Format(time.RFC3339))
+	}
+	if filter.Query != "" {
+		terms = append(terms, filter.Query)
+	}
+	return strings.Join(terms, " ")
+}
+
+// searchPullRequests runs a search, the pull requests being read with their branches in the same query
+func (g *GitHubSv) searchPullRequests(query string, maxPages int) (<-chan PullRequest, error) {
+	rs, err := pullRequestSearch(g.ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan PullRequest)
+	go func() {
+		for pages := 1; ; pages++ {
+			for _, node := range rs.Search.Nodes {
+				if pr, ok := (*node).(*pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest); ok {
+					ch <- GitHubPullRequest{githubListedPullRequest(pr.listedPullRequest), g}
+				}
+			}
+			if !rs.Search.PageInfo.HasNextPage || (maxPages > 0 && pages >= maxPages) {
+				break
+			}
+			if rs, err = pullRequestSearch(g.ctx, query, rs.Search.PageInfo.EndCursor); err != nil {
+				pterm.Debug.Printfln("cannot read the next pull requests: %v", err)
+				break
+			}
+		}
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+// githubListedPullRequest fills the REST pull request with what the search read
+func githubListedPullRequest(l listedPullRequest) *gh.PullRequest {
+	state := "closed"
+	if l.State == PullRequestStateOpen {
+		state = "open"
+	}
+	pr := &gh.PullRequest{
+		NodeID:    gh.String(l.Id),
+		Number:    gh.Int(l.Number),
+		Title:     gh.String(l.Title),
+		Body:      gh.String(l.Body),
+		State:     gh.String(state),
+		Draft:     gh.Bool(l.IsDraft),
+		Merged:    gh.Bool(l.State == PullRequestStateMerged),
+		HTMLURL:   gh.String(l.Url),
+		CreatedAt: &l.CreatedAt,
+		UpdatedAt: &l.UpdatedAt,
+		ClosedAt:  l.ClosedAt,
+		MergedAt:  l.MergedAt,
+		Base:      &gh.PullRequestBranch{Ref: gh.String(l.BaseRefName), SHA: gh.String(l.BaseRefOid)},
+		Head:      &gh.PullRequestBranch{Ref: gh.String(l.HeadRefName), SHA: gh.String(l.HeadRefOid)},
+	}
+	// The repositories are gone when they have been deleted
+	if l.BaseRepository != nil {
+		pr.Base.Repo = githubListedRepository(l.BaseRepository.listedRepository)
+	}
+	if l.HeadRepository != nil {
+		pr.Head.Repo = githubListedRepository(l.HeadRepository.listedRepository)
+	}
+	if l.DatabaseId != nil {
+		pr.ID = gh.Int64(int64(*l.DatabaseId))
+	}
+	if l.Author != nil {
+		pr.User = &gh.User{Login: gh.String((*l.Author).GetLogin())}
+	}
+	return pr
+}
+
+// githubListedRepository is the repository of a branch of a listed pull request
+func githubListedRepository(r listedRepository) *gh.Repository {
+	return &gh.Repository{Name: gh.String(r.Name), FullName: gh.String(r.NameWithOwner),
+		Owner: &gh.User{Login: gh.String(r.Owner.GetLogin())}}
+}
+
 type GitHubPullRequest struct {
 	*gh.PullRequest
 	sv *GitHubSv
 }
 
-func (g GitHubPullRequest) Merge() error {
-	_, err := mergePullRequest(g.sv.ctx, g.GetNodeID())
+var githubMergeMethods = map[string]PullRequestMergeMethod{
+	// sv always squashed on GitHub
+	"":       PullRequestMergeMethodSquash,
+	"merge":  PullRequestMergeMethodMerge,
+	"squash": PullRequestMergeMethodSquash,
+	"rebase": PullRequestMergeMethodRebase,
+}
+
+func (g GitHubPullRequest) Merge(opts MergeOptions) error {
+	method, ok := githubMergeMethods[opts.Method]
+	if !ok {
+		return fmt.Errorf("unknown merge method %s", opts.Method)
+	}
+	var headline, body *string
+	if opts.Title != "" {
+		headline = &opts.Title
+	}
+	if opts.Body != "" {
+		body = &opts.Body
+	}
+
+	if opts.Auto {
+		// The branch is deleted by GitHub when the repository is set up so, it can't be asked for each merge
+		if opts.DeleteBranch {
+			return fmt.Errorf("cannot delete the branch after an auto-merge: %w", ErrNotSupported)
+		}
+		_, err := enablePullRequestAutoMerge(g.sv.ctx, g.GetNodeID(), method, headline, body)
+		return err
+	}
+
+	headOid := g.GetHead().GetSHA()
+	if _, err := mergePullRequest(g.sv.ctx, g.GetNodeID(), method, headline, body, &headOid); err != nil {
+		return err
+	} else if opts.DeleteBranch {
+		// The head may be in a fork
+		repo := g.GetHead().GetRepo()
+		if repo == nil {
+			return fmt.Errorf("cannot delete the branch %s, its repository is gone", g.GetHead().GetRef())
+		}
+		_, err := g.sv.client.Git.DeleteRef(g.sv.ctx, repo.GetOwner().GetLogin(), repo.GetName(), "heads/"+g.GetHead().GetRef())
+		return err
+	}
+	return nil
+}
+
+func (g GitHubPullRequest) Close() error {
+	_, err := closePullRequest(g.sv.ctx, g.GetNodeID())
+	return err
+}
+
+func (g GitHubPullRequest) Reopen() error {
+	_, err := reopenPullRequest(g.sv.ctx, g.GetNodeID())
+	return err
+}
+
+// Edit changes the fields with the REST api, and the labels with GraphQL that creates the missing ones
+func (g GitHubPullRequest) Edit(args EditPullRequestArgs) error {
+	update, changed := &gh.PullRequest{}, false
+	if args.Title.IsSet() {
+		update.Title, changed = gh.String(args.Title.Value()), true
+	}
+	if args.Description.IsSet() {
+		update.Body, changed = gh.String(args.Description.Value()), true
+	}
+	if args.BaseBranch.IsSet() {
+		update.Base, changed = &gh.PullRequestBranch{Ref: gh.String(args.BaseBranch.Value())}, true
+	}
+	if changed {
+		if _, _, err := g.sv.client.PullRequests.Edit(g.sv.ctx, g.sv.owner, g.sv.repo, g.GetNumber(), update); err != nil {
+			return err
+		}
+	}
+
+	if len(args.AddLabels) > 0 {
+		if ids, err := toLabelIds(g.sv, args.AddLabels, args.CreateMissingLabels); err != nil {
+			return err
+		} else if _, err := addLabelsToPullRequest(g.sv.ctx, g.GetNodeID(), ids); err != nil {
+			return err
+		}
+	}
+	if len(args.RemoveLabels) > 0 {
+		if ids, err := toLabelIds(g.sv, args.RemoveLabels, false); err != nil {
+			return err
+		} else if _, err := removeLabelsFromPullRequest(g.sv.ctx, g.GetNodeID(), ids); err != nil {
+			return err
+		}
+	}
+
+	if len(args.AddReviewers) > 0 {
+		if _, _, err := g.sv.client.PullRequests.RequestReviewers(g.sv.ctx, g.sv.owner, g.sv.repo, g.GetNumber(), gh.ReviewersRequest{Reviewers: args.AddReviewers}); err != nil {
+			return err
+		}
+	}
+	if len(args.RemoveReviewers) > 0 {
+		if _, err := g.sv.client.PullRequests.RemoveReviewers(g.sv.ctx, g.sv.owner, g.sv.repo, g.GetNumber(), gh.ReviewersRequest{Reviewers: args.RemoveReviewers}); err != nil {
+			return err
+		}
+	}
+
+	if len(args.AddAssignees) > 0 {
+		if _, _, err := g.sv.client.Issues.AddAssignees(g.sv.ctx, g.sv.owner, g.sv.repo, g.GetNumber(), args.AddAssignees); err != nil {
+			return err
+		}
+	}
+	if len(args.RemoveAssignees) > 0 {
+		if _, _, err := g.sv.client.Issues.RemoveAssignees(g.sv.ctx, g.sv.owner, g.sv.repo, g.GetNumber(), args.RemoveAssignees); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g GitHubPullRequest) IsDraft() bool {
+	return g.GetDraft()
+}
+
+func (g GitHubPullRequest) SetDraft(draft bool) error {
+	var err error
+	if draft {
+		_, err = convertPullRequestToDraft(g.sv.ctx, g.GetNodeID())
+	} else {
+		_, err = markPullRequestReadyForReview(g.sv.ctx, g.GetNodeID())
+	}
 	return err
 }
 
@@ -1224,75 +1432,59 @@ func (g GitHubCommentWrapper) GetCreatedOn() time.Time {
 	return *g.CreatedAt
 }
 
-type MissingCommitError struct {
-	missingHash plumbing.Hash
-	cause       error
+func (g GitHubPullRequest) GetDiff() ([]*gitdiff.File, error) {
+	return diffFromLocalRepo(g.sv.localRepo, *g.Base.SHA, *g.Head.SHA)
 }
 
-func (m *MissingCommitError) Error() string {
-	return fmt.Sprintf("Cannot find commit hash: %s. Cause: %v", m.missingHash, m.cause.Error())
+func (g GitHubPullRequest) GetFileContent(path string, isNew bool) (string, error) {
+	return fileFromLocalRepo(g.sv.localRepo, *g.Base.SHA, *g.Head.SHA, path, isNew)
 }
 
-func (g GitHubPullRequest) GetDiff() ([]*gitdiff.File, error) {
-	rep, giterr := git.PlainOpen(g.sv.localRepo)
-	if giterr != nil {
-		return nil, giterr
-	}
-
-	refBaseHash := plumbing.NewHash(*g.Base.SHA)
-	refBrHash := plumbing.NewHash(*g.Head.SHA)
-
-	cBr, err := rep.CommitObject(refBrHash)
-	if err != nil {
-		pterm.Debug.Println("Cannot get the pr branch head commit, do you need to update your local repo ?", err)
-		return nil, &MissingCommitError{refBrHash, err}
-	}
-	cBase, err := rep.CommitObject(refBaseHash)
-	if err != nil {
-		pterm.Debug.Println("Cannot get the base branch commit, do you need to update your local repo ?", err)
-		return nil, &MissingCommitError{refBaseHash, err}
-	}
-
-	merge, err := cBr.MergeBase(cBase)
-	if err != nil {
-		pterm.Debug.Println("Cannot find a merge base?!?")
-		return nil, err
-	}
-	if len(merge) != 1 {
-		pterm.Debug.Printfln("More than one merge base ?!? %s", merge)
-		return nil, errors.New(pterm.Sprintfln("More than one merge base : %d", len(merge)))
-	}
-
-	baseTree, err2 := merge[0].Tree()
-	if err2 != nil {
-		pterm.Debug.Println(err2)
-		return nil, err2
-	}
-	brTree, err3 := cBr.Tree()
-	if err3 != nil {
-		pterm.Debug.Println(err3)
-		return nil, err3
-	}
-
-	changes, _ := baseTree.Patch(brTree)
-	buf := &bytes.Buffer{}
-	if err := changes.Encode(buf); err != nil {
-		return nil, err
+// GetViewedFiles reads the files marked as viewed on GitHub. GitHub dismisses the mark of a file changed since it was
+// viewed, such a file isn't returned.
+func (g GitHubPullRequest) GetViewedFiles() (map[string]bool, error) {
+	viewed := make(map[string]bool)
+	var after *string
+	for {
+		if rs, err := pullRequestViewedFiles(g.sv.ctx, g.sv.repo, g.sv.owner, g.GetNumber(), after); err != nil {
+			return nil, err
+		} else {
+			files := rs.Repository.PullRequest.Files
+			for _, f := range files.Nodes {
+				if f.ViewerViewedState == FileViewedStateViewed {
+					viewed[f.Path] = true
+				}
+			}
+			if !files.PageInfo.HasNextPage {
+				return viewed, nil
+			}
+			after = files.PageInfo.EndCursor
+		}
 	}
+}
 
-	files, _, err5 := gitdiff.Parse(buf)
-	if err5 != nil {
-		return nil, err5
+func (g GitHubPullRequest) SetFileViewed(path string, viewed bool) error {
+	if viewed {
+		_, err := markFileAsViewed(g.sv.ctx, g.GetNodeID(), path)
+		return err
+	} else {
+		_, err := unmarkFileAsViewed(g.sv.ctx, g.GetNodeID(), path)
+		return err
 	}
-
-	return files, nil
-
 }
 
 func (g GitHubPullRequest) GetBase() Branch {
 	return GitHubBranch{g.Base}
 }
 
+func (g GitHubPullRequest) GetDescription() string {
+	return g.GetBody()
+}
+
+func (g GitHubPullRequest) GetUrl() string {
+	return g.GetHTMLURL()
+}
+
 func (g GitHubPullRequest) GetBranch() Branch {
 	return GitHubBranch{g.Head}
 }
@@ -0,0 +1,212 @@
+package sv
+
+import (
+	"errors"
+	"fmt"
+	gh "github.com/google/go-github/v43/github"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var githubConclusions = map[string]string{
+	"success":         "SUCCESS",
+	"failure":         "FAILURE",
+	"timed_out":       "FAILURE",
+	"startup_failure": "FAILURE",
+	"error":           "FAILURE",
+	"cancelled":       "CANCELLED",
+	"skipped":         "SKIPPED",
+	"neutral":         "NEUTRAL",
+	"stale":           "NEUTRAL",
+	"action_required": "NEUTRAL",
+}
+
+// logClient downloads the logs of the Actions jobs, their signed urls aren't served by the api client
+var logClient = &http.Client{Timeout: 2 * time.Minute}
+
+// GitHubRestCheckRun is a check run of the REST api, the GraphQL one lacks the ids of the Actions jobs
+type GitHubRestCheckRun struct {
+	*gh.CheckRun
+}
+
+func (g GitHubRestCheckRun) GetStatus() string {
+	return strings.ToUpper(g.CheckRun.GetStatus())
+}
+
+func (g GitHubRestCheckRun) GetUrl() string {
+	return g.GetHTMLURL()
+}
+
+func (g GitHubRestCheckRun) GetConclusion() string {
+	return githubConclusions[g.CheckRun.GetConclusion()]
+}
+
+func (g GitHubRestCheckRun) GetStartedAt() time.Time {
+	return g.CheckRun.GetStartedAt().Time
+}
+
+func (g GitHubRestCheckRun) GetCompletedAt() time.Time {
+	return g.CheckRun.GetCompletedAt().Time
+}
+
+// isAction tells whether the check run is an Actions job, the job and the check run share their id
+func (g GitHubRestCheckRun) isAction() bool {
+	return g.GetApp().GetSlug() == "github-actions"
+}
+
+// CanRerun tells whether the check run is an Actions job, the suites of the other apps can only be requested again
+// by the app itself
+func (g GitHubRestCheckRun) CanRerun() bool {
+	return g.isAction()
+}
+
+func (g GitHubRestCheckRun) HasLog() bool {
+	return g.isAction()
+}
+
+// GitHubStatusCheck is a commit status, they can't be rerun from GitHub
+type GitHubStatusCheck struct {
+	*gh.RepoStatus
+}
+
+func (g GitHubStatusCheck) GetName() string {
+	return g.GetContext()
+}
+
+func (g GitHubStatusCheck) GetStatus() string {
+	return strings.ToUpper(g.GetState())
+}
+
+func (g GitHubStatusCheck) GetUrl() string {
+	return g.GetTargetURL()
+}
+
+func (g GitHubStatusCheck) GetConclusion() string {
+	return githubConclusions[g.GetState()]
+}
+
+func (g GitHubStatusCheck) GetStartedAt() time.Time {
+	return g.GetCreatedAt()
+}
+
+func (g GitHubStatusCheck) GetCompletedAt() time.Time {
+	if g.GetConclusion() == "" {
+		return time.Time{}
+	}
+	return g.GetUpdatedAt()
+}
+
+func (g GitHubStatusCheck) CanRerun() bool {
+	return false
+}
+
+func (g GitHubStatusCheck) HasLog() bool {
+	return false
+}
+
+func (g GitHubPullRequest) GetCheckRuns() ([]CheckRun, error) {
+	sha := g.GetHead().GetSHA()
+	result := make([]CheckRun, 0)
+
+	opts := &gh.ListCheckRunsOptions{ListOptions: gh.ListOptions{PerPage: 100}}
+	for {
+		runs, resp, err := g.sv.client.Checks.ListCheckRunsForRef(g.sv.ctx, g.sv.owner, g.sv.repo, sha, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range runs.CheckRuns {
+			result = append(result, GitHubRestCheckRun{r})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	// The combined status keeps the last status of each context
+	statusOpts := &gh.ListOptions{PerPage: 100}
+	for {
+		combined, resp, err := g.sv.client.Repositories.GetCombinedStatus(g.sv.ctx, g.sv.owner, g.sv.repo, sha, statusOpts)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range combined.Statuses {
+			result = append(result, GitHubStatusCheck{s})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		statusOpts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
+
+// checksError tells that GitHub refused the operation on the checks, the token lacking the permission or the run
+// not being rerunnable anymore
+func checksError(err error) error {
+	var e *gh.ErrorResponse
+	if errors.As(err, &e) && e.Response != nil &&
+		(e.Response.StatusCode == http.StatusForbidden || e.Response.StatusCode == http.StatusUnprocessableEntity) {
+		return fmt.Errorf("%s: %w", e.Message, ErrNotSupported)
+	}
+	return err
+}
+
+// RerunChecks reruns the failed jobs of the workflow runs of the Actions checks
+func (g GitHubPullRequest) RerunChecks(checks []CheckRun) error {
+	workflowRuns := make(map[int64]bool)
+
+	for _, c := range checks {
+		run, ok := c.(GitHubRestCheckRun)
+		if !ok || !run.isAction() {
+			return fmt.Errorf("cannot rerun %s: %w", c.GetName(), ErrNotSupported)
+		}
+		job, _, err := g.sv.client.Actions.GetWorkflowJobByID(g.sv.ctx, g.sv.owner, g.sv.repo, run.GetID())
+		if err != nil {
+			return checksError(err)
+		}
+		workflowRuns[job.GetRunID()] = true
+	}
+
+	for id := range workflowRuns {
+		// Not wrapped by go-github yet
+		req, err := g.sv.client.NewRequest("POST", fmt.Sprintf("repos/%s/%s/actions/runs/%d/rerun-failed-jobs", g.sv.owner, g.sv.repo, id), nil)
+		if err != nil {
+			return err
+		}
+		if _, err := g.sv.client.Do(g.sv.ctx, req, nil); err != nil {
+			return checksError(err)
+		}
+	}
+	return nil
+}
+
+func (g GitHubPullRequest) GetCheckLog(check CheckRun) ([]byte, error) {
+	run, ok := check.(GitHubRestCheckRun)
+	if !ok || !run.isAction() {
+		return nil, ErrNotSupported
+	}
+
+	logUrl, _, err := g.sv.client.Actions.GetWorkflowJobLogs(g.sv.ctx, g.sv.owner, g.sv.repo, run.GetID(), true)
+	if err != nil {
+		return nil, checksError(err)
+	}
+
+	// The logs are redirected to a signed url, that doesn't take the api credentials
+	req, err := http.NewRequestWithContext(g.sv.ctx, "GET", logUrl.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := logClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("cannot download the log of %s, status code = %d", run.GetName(), resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
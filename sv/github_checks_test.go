@@ -0,0 +1,85 @@
+package sv_test
+
+import (
+	"errors"
+	"fmt"
+	gh "github.com/google/go-github/v43/github"
+	"github.com/vballestra/sv/sv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newGitHubChecks serves a pull request whose Actions job 5 can't be rerun, and whose job 6 has a log
+func newGitHubChecks(t *testing.T) sv.PullRequest {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("/api/v3/repos/me/repo/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"number": 1, "head": {"sha": "abc"}}`)
+	})
+	mux.HandleFunc("/api/v3/repos/me/repo/actions/jobs/5", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 5, "run_id": 50}`)
+	})
+	mux.HandleFunc("/api/v3/repos/me/repo/actions/runs/50/rerun-failed-jobs", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "Resource not accessible by integration"}`)
+	})
+	mux.HandleFunc("/api/v3/repos/me/repo/actions/jobs/6/logs", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, srv.URL+"/signed/6", http.StatusFound)
+	})
+	mux.HandleFunc("/signed/6", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("the signed url received the api credentials")
+		}
+		fmt.Fprint(w, "the log")
+	})
+
+	provider := sv.NewGitHubSv("token", srv.URL+"/api/v3/", srv.URL+"/api/graphql", t.TempDir(), ".*", "me", "repo")
+	pr, err := provider.GetPullRequest("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pr
+}
+
+func actionsRun(id int64) sv.GitHubRestCheckRun {
+	return sv.GitHubRestCheckRun{CheckRun: &gh.CheckRun{ID: gh.Int64(id), Name: gh.String("build"),
+		App: &gh.App{Slug: gh.String("github-actions")}}}
+}
+
+func TestGitHubCanRerun(t *testing.T) {
+	other := sv.GitHubRestCheckRun{CheckRun: &gh.CheckRun{ID: gh.Int64(7), App: &gh.App{Slug: gh.String("circleci")}}}
+	if !actionsRun(5).CanRerun() || other.CanRerun() {
+		t.Error("only the Actions runs can be rerun")
+	}
+	if (sv.GitHubStatusCheck{RepoStatus: &gh.RepoStatus{}}).CanRerun() {
+		t.Error("the commit statuses can't be rerun")
+	}
+}
+
+func TestGitHubRerunChecks(t *testing.T) {
+	pr := newGitHubChecks(t)
+
+	other := sv.GitHubRestCheckRun{CheckRun: &gh.CheckRun{ID: gh.Int64(7), Name: gh.String("ci"),
+		App: &gh.App{Slug: gh.String("circleci")}}}
+	if err := pr.RerunChecks([]sv.CheckRun{other}); !errors.Is(err, sv.ErrNotSupported) {
+		t.Errorf("rerun of another app = %v, want ErrNotSupported", err)
+	}
+	if err := pr.RerunChecks([]sv.CheckRun{actionsRun(5)}); !errors.Is(err, sv.ErrNotSupported) {
+		t.Errorf("forbidden rerun = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestGitHubCheckLog(t *testing.T) {
+	pr := newGitHubChecks(t)
+
+	log, err := pr.GetCheckLog(actionsRun(6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(log) != "the log" {
+		t.Errorf("log = %q", log)
+	}
+}
@@ -3676,6 +3676,18 @@ const (
 	DiffSideRight DiffSide = "RIGHT"
 )
 
+// The possible viewed states of a file .
+type FileViewedState string
+
+const (
+	// The file has new changes since last viewed.
+	FileViewedStateDismissed FileViewedState = "DISMISSED"
+	// The file has not been marked as viewed.
+	FileViewedStateUnviewed FileViewedState = "UNVIEWED"
+	// The file has been marked as viewed.
+	FileViewedStateViewed FileViewedState = "VIEWED"
+)
+
 // GetChecksAndStatusRepository includes the requested fields of the GraphQL type Repository.
 // The GraphQL type's documentation follows.
 //
@@ -4204,6 +4216,18 @@ func (v *NextPageInfo) GetHasNextPage() bool { return v.HasNextPage }
 // GetEndCursor returns NextPageInfo.EndCursor, and is useful for accessing the field via an interface.
 func (v *NextPageInfo) GetEndCursor() *string { return v.EndCursor }
 
+// Represents available types of methods to use when merging a pull request.
+type PullRequestMergeMethod string
+
+const (
+	// Add all commits from the head branch to the base branch with a merge commit.
+	PullRequestMergeMethodMerge PullRequestMergeMethod = "MERGE"
+	// Add all commits from the head branch onto the base branch individually.
+	PullRequestMergeMethodRebase PullRequestMergeMethod = "REBASE"
+	// Combine all commits from the head branch into a single commit in the base branch.
+	PullRequestMergeMethodSquash PullRequestMergeMethod = "SQUASH"
+)
+
 // The possible events to perform on a pull request review.
 type PullRequestReviewEvent string
 
@@ -5478,6 +5502,18 @@ func (v *__PullRequestsListInput) GetOwner() string { return v.Owner }
 // GetNumber returns __PullRequestsListInput.Number, and is useful for accessing the field via an interface.
 func (v *__PullRequestsListInput) GetNumber() int { return v.Number }
 
+// __addLabelsToPullRequestInput is used internally by genqlient
+type __addLabelsToPullRequestInput struct {
+	Id     string   `json:"id"`
+	Labels []string `json:"labels"`
+}
+
+// GetId returns __addLabelsToPullRequestInput.Id, and is useful for accessing the field via an interface.
+func (v *__addLabelsToPullRequestInput) GetId() string { return v.Id }
+
+// GetLabels returns __addLabelsToPullRequestInput.Labels, and is useful for accessing the field via an interface.
+func (v *__addLabelsToPullRequestInput) GetLabels() []string { return v.Labels }
+
 // __cancelReviewInput is used internally by genqlient
 type __cancelReviewInput struct {
 	RevId string `json:"revId"`
@@ -5486,6 +5522,14 @@ type __cancelReviewInput struct {
 // GetRevId returns __cancelReviewInput.RevId, and is useful for accessing the field via an interface.
 func (v *__cancelReviewInput) GetRevId() string { return v.RevId }
 
+// __closePullRequestInput is used internally by genqlient
+type __closePullRequestInput struct {
+	PrId string `json:"prId"`
+}
+
+// GetPrId returns __closePullRequestInput.PrId, and is useful for accessing the field via an interface.
+func (v *__closePullRequestInput) GetPrId() string { return v.PrId }
+
 // __closeReviewInput is used internally by genqlient
 type __closeReviewInput struct {
 	RevId string `json:"revId"`
@@ -5510,6 +5554,14 @@ func (v *__closeReviewWithEventInput) GetEvent() PullRequestReviewEvent { return
 // GetComment returns __closeReviewWithEventInput.Comment, and is useful for accessing the field via an interface.
 func (v *__closeReviewWithEventInput) GetComment() *string { return v.Comment }
 
+// __convertPullRequestToDraftInput is used internally by genqlient
+type __convertPullRequestToDraftInput struct {
+	PrId string `json:"prId"`
+}
+
+// GetPrId returns __convertPullRequestToDraftInput.PrId, and is useful for accessing the field via an interface.
+func (v *__convertPullRequestToDraftInput) GetPrId() string { return v.PrId }
+
 // __createLabelInput is used internally by genqlient
 type __createLabelInput struct {
 	Name        string  `json:"name"`
@@ -5537,6 +5589,7 @@ type __createPullRequestInput struct {
 	BaseBranch  string  `json:"baseBranch"`
 	Title       string  `json:"title"`
 	Description *string `json:"description"`
+	Draft       *bool   `json:"draft"`
 }
 
 // GetRepoId returns __createPullRequestInput.RepoId, and is useful for accessing the field via an interface.
@@ -5554,6 +5607,9 @@ func (v *__createPullRequestInput) GetTitle() string { return v.Title }
 // GetDescription returns __createPullRequestInput.Description, and is useful for accessing the field via an interface.
 func (v *__createPullRequestInput) GetDescription() *string { return v.Description }
 
+// GetDraft returns __createPullRequestInput.Draft, and is useful for accessing the field via an interface.
+func (v *__createPullRequestInput) GetDraft() *bool { return v.Draft }
+
 // __currentPendingReviewInput is used internally by genqlient
 type __currentPendingReviewInput struct {
 	PrId   string  `json:"prId"`
@@ -5602,6 +5658,26 @@ func (v *__editPullRequestReviewersInput) GetId() string { return v.Id }
 // GetReviewers returns __editPullRequestReviewersInput.Reviewers, and is useful for accessing the field via an interface.
 func (v *__editPullRequestReviewersInput) GetReviewers() []string { return v.Reviewers }
 
+// __enablePullRequestAutoMergeInput is used internally by genqlient
+type __enablePullRequestAutoMergeInput struct {
+	PrId     string                 `json:"prId"`
+	Method   PullRequestMergeMethod `json:"method"`
+	Headline *string                `json:"headline"`
+	Body     *string                `json:"body"`
+}
+
+// GetPrId returns __enablePullRequestAutoMergeInput.PrId, and is useful for accessing the field via an interface.
+func (v *__enablePullRequestAutoMergeInput) GetPrId() string { return v.PrId }
+
+// GetMethod returns __enablePullRequestAutoMergeInput.Method, and is useful for accessing the field via an interface.
+func (v *__enablePullRequestAutoMergeInput) GetMethod() PullRequestMergeMethod { return v.Method }
+
+// GetHeadline returns __enablePullRequestAutoMergeInput.Headline, and is useful for accessing the field via an interface.
+func (v *__enablePullRequestAutoMergeInput) GetHeadline() *string { return v.Headline }
+
+// GetBody returns __enablePullRequestAutoMergeInput.Body, and is useful for accessing the field via an interface.
+func (v *__enablePullRequestAutoMergeInput) GetBody() *string { return v.Body }
+
 // __getLabelByNameInput is used internally by genqlient
 type __getLabelByNameInput struct {
 	Label string `json:"label"`
@@ -5626,14 +5702,50 @@ type __getUserIdByLoginInput struct {
 // GetLogin returns __getUserIdByLoginInput.Login, and is useful for accessing the field via an interface.
 func (v *__getUserIdByLoginInput) GetLogin() string { return v.Login }
 
+// __markFileAsViewedInput is used internally by genqlient
+type __markFileAsViewedInput struct {
+	PrId string `json:"prId"`
+	Path string `json:"path"`
+}
+
+// GetPrId returns __markFileAsViewedInput.PrId, and is useful for accessing the field via an interface.
+func (v *__markFileAsViewedInput) GetPrId() string { return v.PrId }
+
+// GetPath returns __markFileAsViewedInput.Path, and is useful for accessing the field via an interface.
+func (v *__markFileAsViewedInput) GetPath() string { return v.Path }
+
+// __markPullRequestReadyForReviewInput is used internally by genqlient
+type __markPullRequestReadyForReviewInput struct {
+	PrId string `json:"prId"`
+}
+
+// GetPrId returns __markPullRequestReadyForReviewInput.PrId, and is useful for accessing the field via an interface.
+func (v *__markPullRequestReadyForReviewInput) GetPrId() string { return v.PrId }
+
 // __mergePullRequestInput is used internally by genqlient
 type __mergePullRequestInput struct {
-	PrId string `json:"prId"`
+	PrId     string                 `json:"prId"`
+	Method   PullRequestMergeMethod `json:"method"`
+	Headline *string                `json:"headline"`
+	Body     *string                `json:"body"`
+	HeadOid  *string                `json:"headOid"`
 }
 
 // GetPrId returns __mergePullRequestInput.PrId, and is useful for accessing the field via an interface.
 func (v *__mergePullRequestInput) GetPrId() string { return v.PrId }
 
+// GetMethod returns __mergePullRequestInput.Method, and is useful for accessing the field via an interface.
+func (v *__mergePullRequestInput) GetMethod() PullRequestMergeMethod { return v.Method }
+
+// GetHeadline returns __mergePullRequestInput.Headline, and is useful for accessing the field via an interface.
+func (v *__mergePullRequestInput) GetHeadline() *string { return v.Headline }
+
+// GetBody returns __mergePullRequestInput.Body, and is useful for accessing the field via an interface.
+func (v *__mergePullRequestInput) GetBody() *string { return v.Body }
+
+// GetHeadOid returns __mergePullRequestInput.HeadOid, and is useful for accessing the field via an interface.
+func (v *__mergePullRequestInput) GetHeadOid() *string { return v.HeadOid }
+
 // __newReviewInput is used internally by genqlient
 type __newReviewInput struct {
 	PrId string `json:"prId"`
@@ -5662,6 +5774,18 @@ func (v *__pullRequestCommentsInput) GetName() string { return v.Name }
 // GetCommentAfter returns __pullRequestCommentsInput.CommentAfter, and is useful for accessing the field via an interface.
 func (v *__pullRequestCommentsInput) GetCommentAfter() *string { return v.CommentAfter }
 
+// __pullRequestSearchInput is used internally by genqlient
+type __pullRequestSearchInput struct {
+	PrQuery string  `json:"prQuery"`
+	After   *string `json:"after"`
+}
+
+// GetPrQuery returns __pullRequestSearchInput.PrQuery, and is useful for accessing the field via an interface.
+func (v *__pullRequestSearchInput) GetPrQuery() string { return v.PrQuery }
+
+// GetAfter returns __pullRequestSearchInput.After, and is useful for accessing the field via an interface.
+func (v *__pullRequestSearchInput) GetAfter() *string { return v.After }
+
 // __pullRequestThreadsInput is used internally by genqlient
 type __pullRequestThreadsInput struct {
 	Number       int     `json:"number"`
@@ -5682,6 +5806,46 @@ func (v *__pullRequestThreadsInput) GetName() string { return v.Name }
 // GetCommentAfter returns __pullRequestThreadsInput.CommentAfter, and is useful for accessing the field via an interface.
 func (v *__pullRequestThreadsInput) GetCommentAfter() *string { return v.CommentAfter }
 
+// __pullRequestViewedFilesInput is used internally by genqlient
+type __pullRequestViewedFilesInput struct {
+	Name   string  `json:"name"`
+	Owner  string  `json:"owner"`
+	Number int     `json:"number"`
+	After  *string `json:"after"`
+}
+
+// GetName returns __pullRequestViewedFilesInput.Name, and is useful for accessing the field via an interface.
+func (v *__pullRequestViewedFilesInput) GetName() string { return v.Name }
+
+// GetOwner returns __pullRequestViewedFilesInput.Owner, and is useful for accessing the field via an interface.
+func (v *__pullRequestViewedFilesInput) GetOwner() string { return v.Owner }
+
+// GetNumber returns __pullRequestViewedFilesInput.Number, and is useful for accessing the field via an interface.
+func (v *__pullRequestViewedFilesInput) GetNumber() int { return v.Number }
+
+// GetAfter returns __pullRequestViewedFilesInput.After, and is useful for accessing the field via an interface.
+func (v *__pullRequestViewedFilesInput) GetAfter() *string { return v.After }
+
+// __removeLabelsFromPullRequestInput is used internally by genqlient
+type __removeLabelsFromPullRequestInput struct {
+	Id     string   `json:"id"`
+	Labels []string `json:"labels"`
+}
+
+// GetId returns __removeLabelsFromPullRequestInput.Id, and is useful for accessing the field via an interface.
+func (v *__removeLabelsFromPullRequestInput) GetId() string { return v.Id }
+
+// GetLabels returns __removeLabelsFromPullRequestInput.Labels, and is useful for accessing the field via an interface.
+func (v *__removeLabelsFromPullRequestInput) GetLabels() []string { return v.Labels }
+
+// __reopenPullRequestInput is used internally by genqlient
+type __reopenPullRequestInput struct {
+	PrId string `json:"prId"`
+}
+
+// GetPrId returns __reopenPullRequestInput.PrId, and is useful for accessing the field via an interface.
+func (v *__reopenPullRequestInput) GetPrId() string { return v.PrId }
+
 // __replyToInput is used internally by genqlient
 type __replyToInput struct {
 	RevId     string `json:"revId"`
@@ -5750,6 +5914,43 @@ type __singleStatusInput struct {
 // GetIds returns __singleStatusInput.Ids, and is useful for accessing the field via an interface.
 func (v *__singleStatusInput) GetIds() []string { return v.Ids }
 
+// __unmarkFileAsViewedInput is used internally by genqlient
+type __unmarkFileAsViewedInput struct {
+	PrId string `json:"prId"`
+	Path string `json:"path"`
+}
+
+// GetPrId returns __unmarkFileAsViewedInput.PrId, and is useful for accessing the field via an interface.
+func (v *__unmarkFileAsViewedInput) GetPrId() string { return v.PrId }
+
+// GetPath returns __unmarkFileAsViewedInput.Path, and is useful for accessing the field via an interface.
+func (v *__unmarkFileAsViewedInput) GetPath() string { return v.Path }
+
+// addLabelsToPullRequestAddLabelsToLabelableAddLabelsToLabelablePayload includes the requested fields of the GraphQL type AddLabelsToLabelablePayload.
+// The GraphQL type's documentation follows.
+//
+// Autogenerated return type of AddLabelsToLabelable
+type addLabelsToPullRequestAddLabelsToLabelableAddLabelsToLabelablePayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationId *string `json:"clientMutationId"`
+}
+
+// GetClientMutationId returns addLabelsToPullRequestAddLabelsToLabelableAddLabelsToLabelablePayload.ClientMutationId, and is useful for accessing the field via an interface.
+func (v *addLabelsToPullRequestAddLabelsToLabelableAddLabelsToLabelablePayload) GetClientMutationId() *string {
+	return v.ClientMutationId
+}
+
+// addLabelsToPullRequestResponse is returned by addLabelsToPullRequest on success.
+type addLabelsToPullRequestResponse struct {
+	// Adds labels to a labelable object.
+	AddLabelsToLabelable *addLabelsToPullRequestAddLabelsToLabelableAddLabelsToLabelablePayload `json:"addLabelsToLabelable"`
+}
+
+// GetAddLabelsToLabelable returns addLabelsToPullRequestResponse.AddLabelsToLabelable, and is useful for accessing the field via an interface.
+func (v *addLabelsToPullRequestResponse) GetAddLabelsToLabelable() *addLabelsToPullRequestAddLabelsToLabelableAddLabelsToLabelablePayload {
+	return v.AddLabelsToLabelable
+}
+
 // cancelReviewDeletePullRequestReviewDeletePullRequestReviewPayload includes the requested fields of the GraphQL type DeletePullRequestReviewPayload.
 // The GraphQL type's documentation follows.
 //
@@ -5775,6 +5976,31 @@ func (v *cancelReviewResponse) GetDeletePullRequestReview() *cancelReviewDeleteP
 	return v.DeletePullRequestReview
 }
 
+// closePullRequestClosePullRequestClosePullRequestPayload includes the requested fields of the GraphQL type ClosePullRequestPayload.
+// The GraphQL type's documentation follows.
+//
+// Autogenerated return type of ClosePullRequest
+type closePullRequestClosePullRequestClosePullRequestPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationId *string `json:"clientMutationId"`
+}
+
+// GetClientMutationId returns closePullRequestClosePullRequestClosePullRequestPayload.ClientMutationId, and is useful for accessing the field via an interface.
+func (v *closePullRequestClosePullRequestClosePullRequestPayload) GetClientMutationId() *string {
+	return v.ClientMutationId
+}
+
+// closePullRequestResponse is returned by closePullRequest on success.
+type closePullRequestResponse struct {
+	// Close a pull request.
+	ClosePullRequest *closePullRequestClosePullRequestClosePullRequestPayload `json:"closePullRequest"`
+}
+
+// GetClosePullRequest returns closePullRequestResponse.ClosePullRequest, and is useful for accessing the field via an interface.
+func (v *closePullRequestResponse) GetClosePullRequest() *closePullRequestClosePullRequestClosePullRequestPayload {
+	return v.ClosePullRequest
+}
+
 // closeReviewResponse is returned by closeReview on success.
 type closeReviewResponse struct {
 	// Submits a pending pull request review.
@@ -5825,6 +6051,31 @@ func (v *closeReviewWithEventSubmitPullRequestReviewSubmitPullRequestReviewPaylo
 	return v.ClientMutationId
 }
 
+// convertPullRequestToDraftConvertPullRequestToDraftConvertPullRequestToDraftPayload includes the requested fields of the GraphQL type ConvertPullRequestToDraftPayload.
+// The GraphQL type's documentation follows.
+//
+// Autogenerated return type of ConvertPullRequestToDraft
+type convertPullRequestToDraftConvertPullRequestToDraftConvertPullRequestToDraftPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationId *string `json:"clientMutationId"`
+}
+
+// GetClientMutationId returns convertPullRequestToDraftConvertPullRequestToDraftConvertPullRequestToDraftPayload.ClientMutationId, and is useful for accessing the field via an interface.
+func (v *convertPullRequestToDraftConvertPullRequestToDraftConvertPullRequestToDraftPayload) GetClientMutationId() *string {
+	return v.ClientMutationId
+}
+
+// convertPullRequestToDraftResponse is returned by convertPullRequestToDraft on success.
+type convertPullRequestToDraftResponse struct {
+	// Converts a pull request to draft
+	ConvertPullRequestToDraft *convertPullRequestToDraftConvertPullRequestToDraftConvertPullRequestToDraftPayload `json:"convertPullRequestToDraft"`
+}
+
+// GetConvertPullRequestToDraft returns convertPullRequestToDraftResponse.ConvertPullRequestToDraft, and is useful for accessing the field via an interface.
+func (v *convertPullRequestToDraftResponse) GetConvertPullRequestToDraft() *convertPullRequestToDraftConvertPullRequestToDraftConvertPullRequestToDraftPayload {
+	return v.ConvertPullRequestToDraft
+}
+
 // createLabelCreateLabelCreateLabelPayload includes the requested fields of the GraphQL type CreateLabelPayload.
 // The GraphQL type's documentation follows.
 //
@@ -12880,6 +13131,31 @@ func (v *editPullRequestUpdatePullRequestUpdatePullRequestPayloadPullRequest) __
 	return &retval, nil
 }
 
+// enablePullRequestAutoMergeEnablePullRequestAutoMergeEnablePullRequestAutoMergePayload includes the requested fields of the GraphQL type EnablePullRequestAutoMergePayload.
+// The GraphQL type's documentation follows.
+//
+// Autogenerated return type of EnablePullRequestAutoMerge
+type enablePullRequestAutoMergeEnablePullRequestAutoMergeEnablePullRequestAutoMergePayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationId *string `json:"clientMutationId"`
+}
+
+// GetClientMutationId returns enablePullRequestAutoMergeEnablePullRequestAutoMergeEnablePullRequestAutoMergePayload.ClientMutationId, and is useful for accessing the field via an interface.
+func (v *enablePullRequestAutoMergeEnablePullRequestAutoMergeEnablePullRequestAutoMergePayload) GetClientMutationId() *string {
+	return v.ClientMutationId
+}
+
+// enablePullRequestAutoMergeResponse is returned by enablePullRequestAutoMerge on success.
+type enablePullRequestAutoMergeResponse struct {
+	// Enable the default auto-merge on a pull request.
+	EnablePullRequestAutoMerge *enablePullRequestAutoMergeEnablePullRequestAutoMergeEnablePullRequestAutoMergePayload `json:"enablePullRequestAutoMerge"`
+}
+
+// GetEnablePullRequestAutoMerge returns enablePullRequestAutoMergeResponse.EnablePullRequestAutoMerge, and is useful for accessing the field via an interface.
+func (v *enablePullRequestAutoMergeResponse) GetEnablePullRequestAutoMerge() *enablePullRequestAutoMergeEnablePullRequestAutoMergeEnablePullRequestAutoMergePayload {
+	return v.EnablePullRequestAutoMerge
+}
+
 // getLabelByNameRepository includes the requested fields of the GraphQL type Repository.
 // The GraphQL type's documentation follows.
 //
@@ -12988,151 +13264,186 @@ type getUserIdByLoginUser struct {
 // GetId returns getUserIdByLoginUser.Id, and is useful for accessing the field via an interface.
 func (v *getUserIdByLoginUser) GetId() string { return v.Id }
 
-// mergePullRequestMergePullRequestMergePullRequestPayload includes the requested fields of the GraphQL type MergePullRequestPayload.
+// listedPullRequest includes the GraphQL fields of PullRequest requested by the fragment listedPullRequest.
 // The GraphQL type's documentation follows.
 //
-// Autogenerated return type of MergePullRequest
-type mergePullRequestMergePullRequestMergePullRequestPayload struct {
-	// A unique identifier for the client performing the mutation.
-	ClientMutationId *string `json:"clientMutationId"`
+// A repository pull request.
+type listedPullRequest struct {
+	Id string `json:"id"`
+	// Identifies the primary key from the database.
+	DatabaseId *int `json:"databaseId"`
+	// Identifies the pull request number.
+	Number int `json:"number"`
+	// Identifies the pull request title.
+	Title string `json:"title"`
+	// The body as Markdown.
+	Body string `json:"body"`
+	// Identifies the state of the pull request.
+	State PullRequestState `json:"state"`
+	// Identifies if the pull request is a draft.
+	IsDraft bool `json:"isDraft"`
+	// The HTTP URL for this pull request.
+	Url string `json:"url"`
+	// Identifies the date and time when the object was created.
+	CreatedAt time.Time `json:"createdAt"`
+	// Identifies the date and time when the object was last updated.
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Identifies the date and time when the object was closed.
+	ClosedAt *time.Time `json:"closedAt"`
+	// The date and time that the pull request was merged.
+	MergedAt *time.Time `json:"mergedAt"`
+	// The actor who authored the comment.
+	Author *listedPullRequestAuthorActor `json:"-"`
+	// Identifies the name of the base Ref associated with the pull request, even if the ref has been deleted.
+	BaseRefName string `json:"baseRefName"`
+	// Identifies the oid of the base ref associated with the pull request, even if the ref has been deleted.
+	BaseRefOid string `json:"baseRefOid"`
+	// The repository associated with this pull request's base Ref.
+	BaseRepository *listedPullRequestBaseRepository `json:"baseRepository"`
+	// Identifies the name of the head Ref associated with the pull request, even if the ref has been deleted.
+	HeadRefName string `json:"headRefName"`
+	// Identifies the oid of the head ref associated with the pull request, even if the ref has been deleted.
+	HeadRefOid string `json:"headRefOid"`
+	// The repository associated with this pull request's head Ref.
+	HeadRepository *listedPullRequestHeadRepository `json:"headRepository"`
 }
 
-// GetClientMutationId returns mergePullRequestMergePullRequestMergePullRequestPayload.ClientMutationId, and is useful for accessing the field via an interface.
-func (v *mergePullRequestMergePullRequestMergePullRequestPayload) GetClientMutationId() *string {
-	return v.ClientMutationId
-}
+// GetId returns listedPullRequest.Id, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetId() string { return v.Id }
 
-// mergePullRequestResponse is returned by mergePullRequest on success.
-type mergePullRequestResponse struct {
-	// Merge a pull request.
-	MergePullRequest *mergePullRequestMergePullRequestMergePullRequestPayload `json:"mergePullRequest"`
-}
+// GetDatabaseId returns listedPullRequest.DatabaseId, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetDatabaseId() *int { return v.DatabaseId }
 
-// GetMergePullRequest returns mergePullRequestResponse.MergePullRequest, and is useful for accessing the field via an interface.
-func (v *mergePullRequestResponse) GetMergePullRequest() *mergePullRequestMergePullRequestMergePullRequestPayload {
-	return v.MergePullRequest
-}
+// GetNumber returns listedPullRequest.Number, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetNumber() int { return v.Number }
 
-// myLoginResponse is returned by myLogin on success.
-type myLoginResponse struct {
-	// The currently authenticated user.
-	Viewer myLoginViewerUser `json:"viewer"`
-}
+// GetTitle returns listedPullRequest.Title, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetTitle() string { return v.Title }
 
-// GetViewer returns myLoginResponse.Viewer, and is useful for accessing the field via an interface.
-func (v *myLoginResponse) GetViewer() myLoginViewerUser { return v.Viewer }
+// GetBody returns listedPullRequest.Body, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetBody() string { return v.Body }
 
-// myLoginViewerUser includes the requested fields of the GraphQL type User.
-// The GraphQL type's documentation follows.
-//
-// A user is an individual's account on GitHub that owns repositories and can make new content.
-type myLoginViewerUser struct {
-	// The username used to login.
-	Login string `json:"login"`
-}
+// GetState returns listedPullRequest.State, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetState() PullRequestState { return v.State }
 
-// GetLogin returns myLoginViewerUser.Login, and is useful for accessing the field via an interface.
-func (v *myLoginViewerUser) GetLogin() string { return v.Login }
+// GetIsDraft returns listedPullRequest.IsDraft, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetIsDraft() bool { return v.IsDraft }
 
-// newReviewAddPullRequestReviewAddPullRequestReviewPayload includes the requested fields of the GraphQL type AddPullRequestReviewPayload.
-// The GraphQL type's documentation follows.
-//
-// Autogenerated return type of AddPullRequestReview
-type newReviewAddPullRequestReviewAddPullRequestReviewPayload struct {
-	// The newly created pull request review.
-	PullRequestReview *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview `json:"pullRequestReview"`
-}
+// GetUrl returns listedPullRequest.Url, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetUrl() string { return v.Url }
 
-// GetPullRequestReview returns newReviewAddPullRequestReviewAddPullRequestReviewPayload.PullRequestReview, and is useful for accessing the field via an interface.
-func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayload) GetPullRequestReview() *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview {
-	return v.PullRequestReview
-}
+// GetCreatedAt returns listedPullRequest.CreatedAt, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetCreatedAt() time.Time { return v.CreatedAt }
 
-// newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview includes the requested fields of the GraphQL type PullRequestReview.
-// The GraphQL type's documentation follows.
-//
-// A review object for a given pull request.
-type newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview struct {
-	ReviewInfo `json:"-"`
-}
+// GetUpdatedAt returns listedPullRequest.UpdatedAt, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetUpdatedAt() time.Time { return v.UpdatedAt }
 
-// GetState returns newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.State, and is useful for accessing the field via an interface.
-func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) GetState() PullRequestReviewState {
-	return v.ReviewInfo.State
-}
+// GetClosedAt returns listedPullRequest.ClosedAt, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetClosedAt() *time.Time { return v.ClosedAt }
 
-// GetId returns newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.Id, and is useful for accessing the field via an interface.
-func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) GetId() string {
-	return v.ReviewInfo.CommonCommentInfoPullRequestReview.Id
-}
+// GetMergedAt returns listedPullRequest.MergedAt, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetMergedAt() *time.Time { return v.MergedAt }
 
-// GetAuthor returns newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.Author, and is useful for accessing the field via an interface.
-func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) GetAuthor() *CommonCommentInfoAuthorActor {
-	return v.ReviewInfo.CommonCommentInfoPullRequestReview.Author
-}
+// GetAuthor returns listedPullRequest.Author, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetAuthor() *listedPullRequestAuthorActor { return v.Author }
 
-// GetRaw returns newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.Raw, and is useful for accessing the field via an interface.
-func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) GetRaw() string {
-	return v.ReviewInfo.CommonCommentInfoPullRequestReview.Raw
-}
+// GetBaseRefName returns listedPullRequest.BaseRefName, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetBaseRefName() string { return v.BaseRefName }
 
-// GetBodyText returns newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.BodyText, and is useful for accessing the field via an interface.
-func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) GetBodyText() string {
-	return v.ReviewInfo.CommonCommentInfoPullRequestReview.BodyText
-}
+// GetBaseRefOid returns listedPullRequest.BaseRefOid, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetBaseRefOid() string { return v.BaseRefOid }
 
-// GetBodyHTML returns newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.BodyHTML, and is useful for accessing the field via an interface.
-func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) GetBodyHTML() string {
-	return v.ReviewInfo.CommonCommentInfoPullRequestReview.BodyHTML
+// GetBaseRepository returns listedPullRequest.BaseRepository, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetBaseRepository() *listedPullRequestBaseRepository {
+	return v.BaseRepository
 }
 
-// GetCreatedAt returns newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.CreatedAt, and is useful for accessing the field via an interface.
-func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) GetCreatedAt() time.Time {
-	return v.ReviewInfo.CommonCommentInfoPullRequestReview.CreatedAt
+// GetHeadRefName returns listedPullRequest.HeadRefName, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetHeadRefName() string { return v.HeadRefName }
+
+// GetHeadRefOid returns listedPullRequest.HeadRefOid, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetHeadRefOid() string { return v.HeadRefOid }
+
+// GetHeadRepository returns listedPullRequest.HeadRepository, and is useful for accessing the field via an interface.
+func (v *listedPullRequest) GetHeadRepository() *listedPullRequestHeadRepository {
+	return v.HeadRepository
 }
 
-func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) UnmarshalJSON(b []byte) error {
+func (v *listedPullRequest) UnmarshalJSON(b []byte) error {
 
 	if string(b) == "null" {
 		return nil
 	}
 
 	var firstPass struct {
-		*newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview
+		*listedPullRequest
+		Author json.RawMessage `json:"author"`
 		graphql.NoUnmarshalJSON
 	}
-	firstPass.newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview = v
+	firstPass.listedPullRequest = v
 
 	err := json.Unmarshal(b, &firstPass)
 	if err != nil {
 		return err
 	}
 
-	err = json.Unmarshal(
-		b, &v.ReviewInfo)
-	if err != nil {
-		return err
+	{
+		dst := &v.Author
+		src := firstPass.Author
+		if len(src) != 0 && string(src) != "null" {
+			*dst = new(listedPullRequestAuthorActor)
+			err = __unmarshallistedPullRequestAuthorActor(
+				src, *dst)
+			if err != nil {
+				return fmt.Errorf(
+					"Unable to unmarshal listedPullRequest.Author: %w", err)
+			}
+		}
 	}
 	return nil
 }
 
-type __premarshalnewReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview struct {
-	State PullRequestReviewState `json:"state"`
-
+type __premarshallistedPullRequest struct {
 	Id string `json:"id"`
 
-	Author json.RawMessage `json:"author"`
+	DatabaseId *int `json:"databaseId"`
 
-	Raw string `json:"raw"`
+	Number int `json:"number"`
 
-	BodyText string `json:"bodyText"`
+	Title string `json:"title"`
 
-	BodyHTML string `json:"bodyHTML"`
+	Body string `json:"body"`
+
+	State PullRequestState `json:"state"`
+
+	IsDraft bool `json:"isDraft"`
+
+	Url string `json:"url"`
 
 	CreatedAt time.Time `json:"createdAt"`
-}
 
-func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) MarshalJSON() ([]byte, error) {
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	ClosedAt *time.Time `json:"closedAt"`
+
+	MergedAt *time.Time `json:"mergedAt"`
+
+	Author json.RawMessage `json:"author"`
+
+	BaseRefName string `json:"baseRefName"`
+
+	BaseRefOid string `json:"baseRefOid"`
+
+	BaseRepository *listedPullRequestBaseRepository `json:"baseRepository"`
+
+	HeadRefName string `json:"headRefName"`
+
+	HeadRefOid string `json:"headRefOid"`
+
+	HeadRepository *listedPullRequestHeadRepository `json:"headRepository"`
+}
+
+func (v *listedPullRequest) MarshalJSON() ([]byte, error) {
 	premarshaled, err := v.__premarshalJSON()
 	if err != nil {
 		return nil, err
@@ -13140,246 +13451,1719 @@ func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestRevi
 	return json.Marshal(premarshaled)
 }
 
-func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) __premarshalJSON() (*__premarshalnewReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview, error) {
-	var retval __premarshalnewReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview
+func (v *listedPullRequest) __premarshalJSON() (*__premarshallistedPullRequest, error) {
+	var retval __premarshallistedPullRequest
 
-	retval.State = v.ReviewInfo.State
-	retval.Id = v.ReviewInfo.CommonCommentInfoPullRequestReview.Id
+	retval.Id = v.Id
+	retval.DatabaseId = v.DatabaseId
+	retval.Number = v.Number
+	retval.Title = v.Title
+	retval.Body = v.Body
+	retval.State = v.State
+	retval.IsDraft = v.IsDraft
+	retval.Url = v.Url
+	retval.CreatedAt = v.CreatedAt
+	retval.UpdatedAt = v.UpdatedAt
+	retval.ClosedAt = v.ClosedAt
+	retval.MergedAt = v.MergedAt
 	{
 
 		dst := &retval.Author
-		src := v.ReviewInfo.CommonCommentInfoPullRequestReview.Author
+		src := v.Author
 		if src != nil {
 			var err error
-			*dst, err = __marshalCommonCommentInfoAuthorActor(
+			*dst, err = __marshallistedPullRequestAuthorActor(
 				src)
 			if err != nil {
 				return nil, fmt.Errorf(
-					"Unable to marshal newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.ReviewInfo.CommonCommentInfoPullRequestReview.Author: %w", err)
+					"Unable to marshal listedPullRequest.Author: %w", err)
 			}
 		}
 	}
-	retval.Raw = v.ReviewInfo.CommonCommentInfoPullRequestReview.Raw
-	retval.BodyText = v.ReviewInfo.CommonCommentInfoPullRequestReview.BodyText
-	retval.BodyHTML = v.ReviewInfo.CommonCommentInfoPullRequestReview.BodyHTML
-	retval.CreatedAt = v.ReviewInfo.CommonCommentInfoPullRequestReview.CreatedAt
+	retval.BaseRefName = v.BaseRefName
+	retval.BaseRefOid = v.BaseRefOid
+	retval.BaseRepository = v.BaseRepository
+	retval.HeadRefName = v.HeadRefName
+	retval.HeadRefOid = v.HeadRefOid
+	retval.HeadRepository = v.HeadRepository
 	return &retval, nil
 }
 
-// newReviewResponse is returned by newReview on success.
-type newReviewResponse struct {
-	// Adds a review to a Pull Request.
-	AddPullRequestReview *newReviewAddPullRequestReviewAddPullRequestReviewPayload `json:"addPullRequestReview"`
+// listedPullRequestAuthorActor includes the requested fields of the GraphQL interface Actor.
+//
+// listedPullRequestAuthorActor is implemented by the following types:
+// listedPullRequestAuthorBot
+// listedPullRequestAuthorEnterpriseUserAccount
+// listedPullRequestAuthorMannequin
+// listedPullRequestAuthorOrganization
+// listedPullRequestAuthorUser
+// The GraphQL type's documentation follows.
+//
+// Represents an object which can take actions on GitHub. Typically a User or Bot.
+type listedPullRequestAuthorActor interface {
+	implementsGraphQLInterfacelistedPullRequestAuthorActor()
+	// GetTypename returns the receiver's concrete GraphQL type-name (see interface doc for possible values).
+	GetTypename() *string
+	// GetLogin returns the interface-field "login" from its implementation.
+	// The GraphQL interface field's documentation follows.
+	//
+	// The username of the actor.
+	GetLogin() string
 }
 
-// GetAddPullRequestReview returns newReviewResponse.AddPullRequestReview, and is useful for accessing the field via an interface.
-func (v *newReviewResponse) GetAddPullRequestReview() *newReviewAddPullRequestReviewAddPullRequestReviewPayload {
-	return v.AddPullRequestReview
+func (v *listedPullRequestAuthorBot) implementsGraphQLInterfacelistedPullRequestAuthorActor() {}
+func (v *listedPullRequestAuthorEnterpriseUserAccount) implementsGraphQLInterfacelistedPullRequestAuthorActor() {
+}
+func (v *listedPullRequestAuthorMannequin) implementsGraphQLInterfacelistedPullRequestAuthorActor() {}
+func (v *listedPullRequestAuthorOrganization) implementsGraphQLInterfacelistedPullRequestAuthorActor() {
 }
+func (v *listedPullRequestAuthorUser) implementsGraphQLInterfacelistedPullRequestAuthorActor() {}
 
-// pullRequestCommentsRepository includes the requested fields of the GraphQL type Repository.
-// The GraphQL type's documentation follows.
-//
-// A repository contains the content for a project.
-type pullRequestCommentsRepository struct {
-	// Returns a single pull request from the current repository by number.
-	PullRequest *pullRequestCommentsRepositoryPullRequest `json:"pullRequest"`
+func __unmarshallistedPullRequestAuthorActor(b []byte, v *listedPullRequestAuthorActor) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	var tn struct {
+		TypeName string `json:"__typename"`
+	}
+	err := json.Unmarshal(b, &tn)
+	if err != nil {
+		return err
+	}
+
+	switch tn.TypeName {
+	case "Bot":
+		*v = new(listedPullRequestAuthorBot)
+		return json.Unmarshal(b, *v)
+	case "EnterpriseUserAccount":
+		*v = new(listedPullRequestAuthorEnterpriseUserAccount)
+		return json.Unmarshal(b, *v)
+	case "Mannequin":
+		*v = new(listedPullRequestAuthorMannequin)
+		return json.Unmarshal(b, *v)
+	case "Organization":
+		*v = new(listedPullRequestAuthorOrganization)
+		return json.Unmarshal(b, *v)
+	case "User":
+		*v = new(listedPullRequestAuthorUser)
+		return json.Unmarshal(b, *v)
+	case "":
+		return fmt.Errorf(
+			"response was missing Actor.__typename")
+	default:
+		return fmt.Errorf(
+			`unexpected concrete type for listedPullRequestAuthorActor: "%v"`, tn.TypeName)
+	}
 }
 
-// GetPullRequest returns pullRequestCommentsRepository.PullRequest, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsRepository) GetPullRequest() *pullRequestCommentsRepositoryPullRequest {
-	return v.PullRequest
+func __marshallistedPullRequestAuthorActor(v *listedPullRequestAuthorActor) ([]byte, error) {
+
+	var typename string
+	switch v := (*v).(type) {
+	case *listedPullRequestAuthorBot:
+		typename = "Bot"
+
+		result := struct {
+			TypeName string `json:"__typename"`
+			*listedPullRequestAuthorBot
+		}{typename, v}
+		return json.Marshal(result)
+	case *listedPullRequestAuthorEnterpriseUserAccount:
+		typename = "EnterpriseUserAccount"
+
+		result := struct {
+			TypeName string `json:"__typename"`
+			*listedPullRequestAuthorEnterpriseUserAccount
+		}{typename, v}
+		return json.Marshal(result)
+	case *listedPullRequestAuthorMannequin:
+		typename = "Mannequin"
+
+		result := struct {
+			TypeName string `json:"__typename"`
+			*listedPullRequestAuthorMannequin
+		}{typename, v}
+		return json.Marshal(result)
+	case *listedPullRequestAuthorOrganization:
+		typename = "Organization"
+
+		result := struct {
+			TypeName string `json:"__typename"`
+			*listedPullRequestAuthorOrganization
+		}{typename, v}
+		return json.Marshal(result)
+	case *listedPullRequestAuthorUser:
+		typename = "User"
+
+		result := struct {
+			TypeName string `json:"__typename"`
+			*listedPullRequestAuthorUser
+		}{typename, v}
+		return json.Marshal(result)
+	case nil:
+		return []byte("null"), nil
+	default:
+		return nil, fmt.Errorf(
+			`unexpected concrete type for listedPullRequestAuthorActor: "%T"`, v)
+	}
 }
 
-// pullRequestCommentsRepositoryPullRequest includes the requested fields of the GraphQL type PullRequest.
+// listedPullRequestAuthorBot includes the requested fields of the GraphQL type Bot.
 // The GraphQL type's documentation follows.
 //
-// A repository pull request.
-type pullRequestCommentsRepositoryPullRequest struct {
-	// A list of comments associated with the pull request.
-	Comments pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection `json:"comments"`
+// A special type of user which takes actions on behalf of GitHub Apps.
+type listedPullRequestAuthorBot struct {
+	Typename *string `json:"__typename"`
+	// The username of the actor.
+	Login string `json:"login"`
 }
 
-// GetComments returns pullRequestCommentsRepositoryPullRequest.Comments, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsRepositoryPullRequest) GetComments() pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection {
-	return v.Comments
-}
+// GetTypename returns listedPullRequestAuthorBot.Typename, and is useful for accessing the field via an interface.
+func (v *listedPullRequestAuthorBot) GetTypename() *string { return v.Typename }
 
-// pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection includes the requested fields of the GraphQL type IssueCommentConnection.
+// GetLogin returns listedPullRequestAuthorBot.Login, and is useful for accessing the field via an interface.
+func (v *listedPullRequestAuthorBot) GetLogin() string { return v.Login }
+
+// listedPullRequestAuthorEnterpriseUserAccount includes the requested fields of the GraphQL type EnterpriseUserAccount.
 // The GraphQL type's documentation follows.
 //
-// The connection type for IssueComment.
-type pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection struct {
-	// Information to aid in pagination.
-	PageInfo pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo `json:"pageInfo"`
-	// Identifies the total count of items in the connection.
-	TotalCount int `json:"totalCount"`
-	// A list of nodes.
-	Nodes []*pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment `json:"nodes"`
+// An account for a user who is an admin of an enterprise or a member of an enterprise through one or more organizations.
+type listedPullRequestAuthorEnterpriseUserAccount struct {
+	Typename *string `json:"__typename"`
+	// The username of the actor.
+	Login string `json:"login"`
 }
 
-// GetPageInfo returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection.PageInfo, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection) GetPageInfo() pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo {
-	return v.PageInfo
+// GetTypename returns listedPullRequestAuthorEnterpriseUserAccount.Typename, and is useful for accessing the field via an interface.
+func (v *listedPullRequestAuthorEnterpriseUserAccount) GetTypename() *string { return v.Typename }
+
+// GetLogin returns listedPullRequestAuthorEnterpriseUserAccount.Login, and is useful for accessing the field via an interface.
+func (v *listedPullRequestAuthorEnterpriseUserAccount) GetLogin() string { return v.Login }
+
+// listedPullRequestAuthorMannequin includes the requested fields of the GraphQL type Mannequin.
+// The GraphQL type's documentation follows.
+//
+// A placeholder user for attribution of imported data on GitHub.
+type listedPullRequestAuthorMannequin struct {
+	Typename *string `json:"__typename"`
+	// The username of the actor.
+	Login string `json:"login"`
 }
 
-// GetTotalCount returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection.TotalCount, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection) GetTotalCount() int {
-	return v.TotalCount
+// GetTypename returns listedPullRequestAuthorMannequin.Typename, and is useful for accessing the field via an interface.
+func (v *listedPullRequestAuthorMannequin) GetTypename() *string { return v.Typename }
+
+// GetLogin returns listedPullRequestAuthorMannequin.Login, and is useful for accessing the field via an interface.
+func (v *listedPullRequestAuthorMannequin) GetLogin() string { return v.Login }
+
+// listedPullRequestAuthorOrganization includes the requested fields of the GraphQL type Organization.
+// The GraphQL type's documentation follows.
+//
+// An account on GitHub, with one or more owners, that has repositories, members and teams.
+type listedPullRequestAuthorOrganization struct {
+	Typename *string `json:"__typename"`
+	// The username of the actor.
+	Login string `json:"login"`
 }
 
-// GetNodes returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection.Nodes, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection) GetNodes() []*pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment {
-	return v.Nodes
+// GetTypename returns listedPullRequestAuthorOrganization.Typename, and is useful for accessing the field via an interface.
+func (v *listedPullRequestAuthorOrganization) GetTypename() *string { return v.Typename }
+
+// GetLogin returns listedPullRequestAuthorOrganization.Login, and is useful for accessing the field via an interface.
+func (v *listedPullRequestAuthorOrganization) GetLogin() string { return v.Login }
+
+// listedPullRequestAuthorUser includes the requested fields of the GraphQL type User.
+// The GraphQL type's documentation follows.
+//
+// A user is an individual's account on GitHub that owns repositories and can make new content.
+type listedPullRequestAuthorUser struct {
+	Typename *string `json:"__typename"`
+	// The username of the actor.
+	Login string `json:"login"`
 }
 
-// pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment includes the requested fields of the GraphQL type IssueComment.
+// GetTypename returns listedPullRequestAuthorUser.Typename, and is useful for accessing the field via an interface.
+func (v *listedPullRequestAuthorUser) GetTypename() *string { return v.Typename }
+
+// GetLogin returns listedPullRequestAuthorUser.Login, and is useful for accessing the field via an interface.
+func (v *listedPullRequestAuthorUser) GetLogin() string { return v.Login }
+
+// listedPullRequestBaseRepository includes the requested fields of the GraphQL type Repository.
 // The GraphQL type's documentation follows.
 //
-// Represents a comment on an Issue.
-type pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment struct {
-	CommentInfoIssueComment `json:"-"`
+// A repository contains the content for a project.
+type listedPullRequestBaseRepository struct {
+	listedRepository `json:"-"`
 }
 
-// GetId returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.Id, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) GetId() string {
-	return v.CommentInfoIssueComment.CommonCommentInfoIssueComment.Id
+// GetName returns listedPullRequestBaseRepository.Name, and is useful for accessing the field via an interface.
+func (v *listedPullRequestBaseRepository) GetName() string { return v.listedRepository.Name }
+
+// GetNameWithOwner returns listedPullRequestBaseRepository.NameWithOwner, and is useful for accessing the field via an interface.
+func (v *listedPullRequestBaseRepository) GetNameWithOwner() string {
+	return v.listedRepository.NameWithOwner
 }
 
-// GetAuthor returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.Author, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) GetAuthor() *CommonCommentInfoAuthorActor {
-	return v.CommentInfoIssueComment.CommonCommentInfoIssueComment.Author
+// GetOwner returns listedPullRequestBaseRepository.Owner, and is useful for accessing the field via an interface.
+func (v *listedPullRequestBaseRepository) GetOwner() listedRepositoryOwner {
+	return v.listedRepository.Owner
 }
 
-// GetRaw returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.Raw, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) GetRaw() string {
-	return v.CommentInfoIssueComment.CommonCommentInfoIssueComment.Raw
+func (v *listedPullRequestBaseRepository) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		return nil
+	}
+
+	var firstPass struct {
+		*listedPullRequestBaseRepository
+		graphql.NoUnmarshalJSON
+	}
+	firstPass.listedPullRequestBaseRepository = v
+
+	err := json.Unmarshal(b, &firstPass)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(
+		b, &v.listedRepository)
+	if err != nil {
+		return err
+	}
+	return nil
 }
 
-// GetBodyText returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.BodyText, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) GetBodyText() string {
-	return v.CommentInfoIssueComment.CommonCommentInfoIssueComment.BodyText
+type __premarshallistedPullRequestBaseRepository struct {
+	Name string `json:"name"`
+
+	NameWithOwner string `json:"nameWithOwner"`
+
+	Owner json.RawMessage `json:"owner"`
 }
 
-// GetBodyHTML returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.BodyHTML, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) GetBodyHTML() string {
-	return v.CommentInfoIssueComment.CommonCommentInfoIssueComment.BodyHTML
+func (v *listedPullRequestBaseRepository) MarshalJSON() ([]byte, error) {
+	premarshaled, err := v.__premarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(premarshaled)
 }
 
-// GetCreatedAt returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.CreatedAt, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) GetCreatedAt() time.Time {
-	return v.CommentInfoIssueComment.CommonCommentInfoIssueComment.CreatedAt
+func (v *listedPullRequestBaseRepository) __premarshalJSON() (*__premarshallistedPullRequestBaseRepository, error) {
+	var retval __premarshallistedPullRequestBaseRepository
+
+	retval.Name = v.listedRepository.Name
+	retval.NameWithOwner = v.listedRepository.NameWithOwner
+	{
+
+		dst := &retval.Owner
+		src := v.listedRepository.Owner
+		var err error
+		*dst, err = __marshallistedRepositoryOwner(
+			&src)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"Unable to marshal listedPullRequestBaseRepository.listedRepository.Owner: %w", err)
+		}
+	}
+	return &retval, nil
+}
+
+// listedPullRequestHeadRepository includes the requested fields of the GraphQL type Repository.
+// The GraphQL type's documentation follows.
+//
+// A repository contains the content for a project.
+type listedPullRequestHeadRepository struct {
+	listedRepository `json:"-"`
+}
+
+// GetName returns listedPullRequestHeadRepository.Name, and is useful for accessing the field via an interface.
+func (v *listedPullRequestHeadRepository) GetName() string { return v.listedRepository.Name }
+
+// GetNameWithOwner returns listedPullRequestHeadRepository.NameWithOwner, and is useful for accessing the field via an interface.
+func (v *listedPullRequestHeadRepository) GetNameWithOwner() string {
+	return v.listedRepository.NameWithOwner
+}
+
+// GetOwner returns listedPullRequestHeadRepository.Owner, and is useful for accessing the field via an interface.
+func (v *listedPullRequestHeadRepository) GetOwner() listedRepositoryOwner {
+	return v.listedRepository.Owner
+}
+
+func (v *listedPullRequestHeadRepository) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		return nil
+	}
+
+	var firstPass struct {
+		*listedPullRequestHeadRepository
+		graphql.NoUnmarshalJSON
+	}
+	firstPass.listedPullRequestHeadRepository = v
+
+	err := json.Unmarshal(b, &firstPass)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(
+		b, &v.listedRepository)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type __premarshallistedPullRequestHeadRepository struct {
+	Name string `json:"name"`
+
+	NameWithOwner string `json:"nameWithOwner"`
+
+	Owner json.RawMessage `json:"owner"`
+}
+
+func (v *listedPullRequestHeadRepository) MarshalJSON() ([]byte, error) {
+	premarshaled, err := v.__premarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(premarshaled)
+}
+
+func (v *listedPullRequestHeadRepository) __premarshalJSON() (*__premarshallistedPullRequestHeadRepository, error) {
+	var retval __premarshallistedPullRequestHeadRepository
+
+	retval.Name = v.listedRepository.Name
+	retval.NameWithOwner = v.listedRepository.NameWithOwner
+	{
+
+		dst := &retval.Owner
+		src := v.listedRepository.Owner
+		var err error
+		*dst, err = __marshallistedRepositoryOwner(
+			&src)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"Unable to marshal listedPullRequestHeadRepository.listedRepository.Owner: %w", err)
+		}
+	}
+	return &retval, nil
+}
+
+// listedRepository includes the GraphQL fields of Repository requested by the fragment listedRepository.
+// The GraphQL type's documentation follows.
+//
+// A repository contains the content for a project.
+type listedRepository struct {
+	// The name of the repository.
+	Name string `json:"name"`
+	// The repository's name with owner.
+	NameWithOwner string `json:"nameWithOwner"`
+	// The User owner of the repository.
+	Owner listedRepositoryOwner `json:"-"`
+}
+
+// GetName returns listedRepository.Name, and is useful for accessing the field via an interface.
+func (v *listedRepository) GetName() string { return v.Name }
+
+// GetNameWithOwner returns listedRepository.NameWithOwner, and is useful for accessing the field via an interface.
+func (v *listedRepository) GetNameWithOwner() string { return v.NameWithOwner }
+
+// GetOwner returns listedRepository.Owner, and is useful for accessing the field via an interface.
+func (v *listedRepository) GetOwner() listedRepositoryOwner { return v.Owner }
+
+func (v *listedRepository) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		return nil
+	}
+
+	var firstPass struct {
+		*listedRepository
+		Owner json.RawMessage `json:"owner"`
+		graphql.NoUnmarshalJSON
+	}
+	firstPass.listedRepository = v
+
+	err := json.Unmarshal(b, &firstPass)
+	if err != nil {
+		return err
+	}
+
+	{
+		dst := &v.Owner
+		src := firstPass.Owner
+		if len(src) != 0 && string(src) != "null" {
+			err = __unmarshallistedRepositoryOwner(
+				src, dst)
+			if err != nil {
+				return fmt.Errorf(
+					"Unable to unmarshal listedRepository.Owner: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+type __premarshallistedRepository struct {
+	Name string `json:"name"`
+
+	NameWithOwner string `json:"nameWithOwner"`
+
+	Owner json.RawMessage `json:"owner"`
+}
+
+func (v *listedRepository) MarshalJSON() ([]byte, error) {
+	premarshaled, err := v.__premarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(premarshaled)
+}
+
+func (v *listedRepository) __premarshalJSON() (*__premarshallistedRepository, error) {
+	var retval __premarshallistedRepository
+
+	retval.Name = v.Name
+	retval.NameWithOwner = v.NameWithOwner
+	{
+
+		dst := &retval.Owner
+		src := v.Owner
+		var err error
+		*dst, err = __marshallistedRepositoryOwner(
+			&src)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"Unable to marshal listedRepository.Owner: %w", err)
+		}
+	}
+	return &retval, nil
+}
+
+// listedRepositoryOwner includes the requested fields of the GraphQL interface RepositoryOwner.
+//
+// listedRepositoryOwner is implemented by the following types:
+// listedRepositoryOwnerOrganization
+// listedRepositoryOwnerUser
+// The GraphQL type's documentation follows.
+//
+// Represents an owner of a Repository.
+type listedRepositoryOwner interface {
+	implementsGraphQLInterfacelistedRepositoryOwner()
+	// GetTypename returns the receiver's concrete GraphQL type-name (see interface doc for possible values).
+	GetTypename() *string
+	// GetLogin returns the interface-field "login" from its implementation.
+	// The GraphQL interface field's documentation follows.
+	//
+	// The username used to login.
+	GetLogin() string
+}
+
+func (v *listedRepositoryOwnerOrganization) implementsGraphQLInterfacelistedRepositoryOwner() {}
+func (v *listedRepositoryOwnerUser) implementsGraphQLInterfacelistedRepositoryOwner()         {}
+
+func __unmarshallistedRepositoryOwner(b []byte, v *listedRepositoryOwner) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	var tn struct {
+		TypeName string `json:"__typename"`
+	}
+	err := json.Unmarshal(b, &tn)
+	if err != nil {
+		return err
+	}
+
+	switch tn.TypeName {
+	case "Organization":
+		*v = new(listedRepositoryOwnerOrganization)
+		return json.Unmarshal(b, *v)
+	case "User":
+		*v = new(listedRepositoryOwnerUser)
+		return json.Unmarshal(b, *v)
+	case "":
+		return fmt.Errorf(
+			"response was missing RepositoryOwner.__typename")
+	default:
+		return fmt.Errorf(
+			`unexpected concrete type for listedRepositoryOwner: "%v"`, tn.TypeName)
+	}
+}
+
+func __marshallistedRepositoryOwner(v *listedRepositoryOwner) ([]byte, error) {
+
+	var typename string
+	switch v := (*v).(type) {
+	case *listedRepositoryOwnerOrganization:
+		typename = "Organization"
+
+		result := struct {
+			TypeName string `json:"__typename"`
+			*listedRepositoryOwnerOrganization
+		}{typename, v}
+		return json.Marshal(result)
+	case *listedRepositoryOwnerUser:
+		typename = "User"
+
+		result := struct {
+			TypeName string `json:"__typename"`
+			*listedRepositoryOwnerUser
+		}{typename, v}
+		return json.Marshal(result)
+	case nil:
+		return []byte("null"), nil
+	default:
+		return nil, fmt.Errorf(
+			`unexpected concrete type for listedRepositoryOwner: "%T"`, v)
+	}
+}
+
+// listedRepositoryOwnerOrganization includes the requested fields of the GraphQL type Organization.
+// The GraphQL type's documentation follows.
+//
+// An account on GitHub, with one or more owners, that has repositories, members and teams.
+type listedRepositoryOwnerOrganization struct {
+	Typename *string `json:"__typename"`
+	// The username used to login.
+	Login string `json:"login"`
+}
+
+// GetTypename returns listedRepositoryOwnerOrganization.Typename, and is useful for accessing the field via an interface.
+func (v *listedRepositoryOwnerOrganization) GetTypename() *string { return v.Typename }
+
+// GetLogin returns listedRepositoryOwnerOrganization.Login, and is useful for accessing the field via an interface.
+func (v *listedRepositoryOwnerOrganization) GetLogin() string { return v.Login }
+
+// listedRepositoryOwnerUser includes the requested fields of the GraphQL type User.
+// The GraphQL type's documentation follows.
+//
+// A user is an individual's account on GitHub that owns repositories and can make new content.
+type listedRepositoryOwnerUser struct {
+	Typename *string `json:"__typename"`
+	// The username used to login.
+	Login string `json:"login"`
+}
+
+// GetTypename returns listedRepositoryOwnerUser.Typename, and is useful for accessing the field via an interface.
+func (v *listedRepositoryOwnerUser) GetTypename() *string { return v.Typename }
+
+// GetLogin returns listedRepositoryOwnerUser.Login, and is useful for accessing the field via an interface.
+func (v *listedRepositoryOwnerUser) GetLogin() string { return v.Login }
+
+// markFileAsViewedMarkFileAsViewedMarkFileAsViewedPayload includes the requested fields of the GraphQL type MarkFileAsViewedPayload.
+// The GraphQL type's documentation follows.
+//
+// Autogenerated return type of MarkFileAsViewed
+type markFileAsViewedMarkFileAsViewedMarkFileAsViewedPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationId *string `json:"clientMutationId"`
+}
+
+// GetClientMutationId returns markFileAsViewedMarkFileAsViewedMarkFileAsViewedPayload.ClientMutationId, and is useful for accessing the field via an interface.
+func (v *markFileAsViewedMarkFileAsViewedMarkFileAsViewedPayload) GetClientMutationId() *string {
+	return v.ClientMutationId
+}
+
+// markFileAsViewedResponse is returned by markFileAsViewed on success.
+type markFileAsViewedResponse struct {
+	// Mark a pull request file as viewed
+	MarkFileAsViewed *markFileAsViewedMarkFileAsViewedMarkFileAsViewedPayload `json:"markFileAsViewed"`
+}
+
+// GetMarkFileAsViewed returns markFileAsViewedResponse.MarkFileAsViewed, and is useful for accessing the field via an interface.
+func (v *markFileAsViewedResponse) GetMarkFileAsViewed() *markFileAsViewedMarkFileAsViewedMarkFileAsViewedPayload {
+	return v.MarkFileAsViewed
+}
+
+// markPullRequestReadyForReviewMarkPullRequestReadyForReviewMarkPullRequestReadyForReviewPayload includes the requested fields of the GraphQL type MarkPullRequestReadyForReviewPayload.
+// The GraphQL type's documentation follows.
+//
+// Autogenerated return type of MarkPullRequestReadyForReview
+type markPullRequestReadyForReviewMarkPullRequestReadyForReviewMarkPullRequestReadyForReviewPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationId *string `json:"clientMutationId"`
+}
+
+// GetClientMutationId returns markPullRequestReadyForReviewMarkPullRequestReadyForReviewMarkPullRequestReadyForReviewPayload.ClientMutationId, and is useful for accessing the field via an interface.
+func (v *markPullRequestReadyForReviewMarkPullRequestReadyForReviewMarkPullRequestReadyForReviewPayload) GetClientMutationId() *string {
+	return v.ClientMutationId
+}
+
+// markPullRequestReadyForReviewResponse is returned by markPullRequestReadyForReview on success.
+type markPullRequestReadyForReviewResponse struct {
+	// Marks a pull request ready for review.
+	MarkPullRequestReadyForReview *markPullRequestReadyForReviewMarkPullRequestReadyForReviewMarkPullRequestReadyForReviewPayload `json:"markPullRequestReadyForReview"`
+}
+
+// GetMarkPullRequestReadyForReview returns markPullRequestReadyForReviewResponse.MarkPullRequestReadyForReview, and is useful for accessing the field via an interface.
+func (v *markPullRequestReadyForReviewResponse) GetMarkPullRequestReadyForReview() *markPullRequestReadyForReviewMarkPullRequestReadyForReviewMarkPullRequestReadyForReviewPayload {
+	return v.MarkPullRequestReadyForReview
+}
+
+// mergePullRequestMergePullRequestMergePullRequestPayload includes the requested fields of the GraphQL type MergePullRequestPayload.
+// The GraphQL type's documentation follows.
+//
+// Autogenerated return type of MergePullRequest
+type mergePullRequestMergePullRequestMergePullRequestPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationId *string `json:"clientMutationId"`
+}
+
+// GetClientMutationId returns mergePullRequestMergePullRequestMergePullRequestPayload.ClientMutationId, and is useful for accessing the field via an interface.
+func (v *mergePullRequestMergePullRequestMergePullRequestPayload) GetClientMutationId() *string {
+	return v.ClientMutationId
+}
+
+// mergePullRequestResponse is returned by mergePullRequest on success.
+type mergePullRequestResponse struct {
+	// Merge a pull request.
+	MergePullRequest *mergePullRequestMergePullRequestMergePullRequestPayload `json:"mergePullRequest"`
+}
+
+// GetMergePullRequest returns mergePullRequestResponse.MergePullRequest, and is useful for accessing the field via an interface.
+func (v *mergePullRequestResponse) GetMergePullRequest() *mergePullRequestMergePullRequestMergePullRequestPayload {
+	return v.MergePullRequest
+}
+
+// myLoginResponse is returned by myLogin on success.
+type myLoginResponse struct {
+	// The currently authenticated user.
+	Viewer myLoginViewerUser `json:"viewer"`
+}
+
+// GetViewer returns myLoginResponse.Viewer, and is useful for accessing the field via an interface.
+func (v *myLoginResponse) GetViewer() myLoginViewerUser { return v.Viewer }
+
+// myLoginViewerUser includes the requested fields of the GraphQL type User.
+// The GraphQL type's documentation follows.
+//
+// A user is an individual's account on GitHub that owns repositories and can make new content.
+type myLoginViewerUser struct {
+	// The username used to login.
+	Login string `json:"login"`
+}
+
+// GetLogin returns myLoginViewerUser.Login, and is useful for accessing the field via an interface.
+func (v *myLoginViewerUser) GetLogin() string { return v.Login }
+
+// newReviewAddPullRequestReviewAddPullRequestReviewPayload includes the requested fields of the GraphQL type AddPullRequestReviewPayload.
+// The GraphQL type's documentation follows.
+//
+// Autogenerated return type of AddPullRequestReview
+type newReviewAddPullRequestReviewAddPullRequestReviewPayload struct {
+	// The newly created pull request review.
+	PullRequestReview *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview `json:"pullRequestReview"`
+}
+
+// GetPullRequestReview returns newReviewAddPullRequestReviewAddPullRequestReviewPayload.PullRequestReview, and is useful for accessing the field via an interface.
+func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayload) GetPullRequestReview() *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview {
+	return v.PullRequestReview
+}
+
+// newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview includes the requested fields of the GraphQL type PullRequestReview.
+// The GraphQL type's documentation follows.
+//
+// A review object for a given pull request.
+type newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview struct {
+	ReviewInfo `json:"-"`
+}
+
+// GetState returns newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.State, and is useful for accessing the field via an interface.
+func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) GetState() PullRequestReviewState {
+	return v.ReviewInfo.State
+}
+
+// GetId returns newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.Id, and is useful for accessing the field via an interface.
+func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) GetId() string {
+	return v.ReviewInfo.CommonCommentInfoPullRequestReview.Id
+}
+
+// GetAuthor returns newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.Author, and is useful for accessing the field via an interface.
+func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) GetAuthor() *CommonCommentInfoAuthorActor {
+	return v.ReviewInfo.CommonCommentInfoPullRequestReview.Author
+}
+
+// GetRaw returns newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.Raw, and is useful for accessing the field via an interface.
+func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) GetRaw() string {
+	return v.ReviewInfo.CommonCommentInfoPullRequestReview.Raw
+}
+
+// GetBodyText returns newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.BodyText, and is useful for accessing the field via an interface.
+func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) GetBodyText() string {
+	return v.ReviewInfo.CommonCommentInfoPullRequestReview.BodyText
+}
+
+// GetBodyHTML returns newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.BodyHTML, and is useful for accessing the field via an interface.
+func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) GetBodyHTML() string {
+	return v.ReviewInfo.CommonCommentInfoPullRequestReview.BodyHTML
+}
+
+// GetCreatedAt returns newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.CreatedAt, and is useful for accessing the field via an interface.
+func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) GetCreatedAt() time.Time {
+	return v.ReviewInfo.CommonCommentInfoPullRequestReview.CreatedAt
+}
+
+func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		return nil
+	}
+
+	var firstPass struct {
+		*newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview
+		graphql.NoUnmarshalJSON
+	}
+	firstPass.newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview = v
+
+	err := json.Unmarshal(b, &firstPass)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(
+		b, &v.ReviewInfo)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type __premarshalnewReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview struct {
+	State PullRequestReviewState `json:"state"`
+
+	Id string `json:"id"`
+
+	Author json.RawMessage `json:"author"`
+
+	Raw string `json:"raw"`
+
+	BodyText string `json:"bodyText"`
+
+	BodyHTML string `json:"bodyHTML"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) MarshalJSON() ([]byte, error) {
+	premarshaled, err := v.__premarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(premarshaled)
+}
+
+func (v *newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview) __premarshalJSON() (*__premarshalnewReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview, error) {
+	var retval __premarshalnewReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview
+
+	retval.State = v.ReviewInfo.State
+	retval.Id = v.ReviewInfo.CommonCommentInfoPullRequestReview.Id
+	{
+
+		dst := &retval.Author
+		src := v.ReviewInfo.CommonCommentInfoPullRequestReview.Author
+		if src != nil {
+			var err error
+			*dst, err = __marshalCommonCommentInfoAuthorActor(
+				src)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"Unable to marshal newReviewAddPullRequestReviewAddPullRequestReviewPayloadPullRequestReview.ReviewInfo.CommonCommentInfoPullRequestReview.Author: %w", err)
+			}
+		}
+	}
+	retval.Raw = v.ReviewInfo.CommonCommentInfoPullRequestReview.Raw
+	retval.BodyText = v.ReviewInfo.CommonCommentInfoPullRequestReview.BodyText
+	retval.BodyHTML = v.ReviewInfo.CommonCommentInfoPullRequestReview.BodyHTML
+	retval.CreatedAt = v.ReviewInfo.CommonCommentInfoPullRequestReview.CreatedAt
+	return &retval, nil
+}
+
+// newReviewResponse is returned by newReview on success.
+type newReviewResponse struct {
+	// Adds a review to a Pull Request.
+	AddPullRequestReview *newReviewAddPullRequestReviewAddPullRequestReviewPayload `json:"addPullRequestReview"`
+}
+
+// GetAddPullRequestReview returns newReviewResponse.AddPullRequestReview, and is useful for accessing the field via an interface.
+func (v *newReviewResponse) GetAddPullRequestReview() *newReviewAddPullRequestReviewAddPullRequestReviewPayload {
+	return v.AddPullRequestReview
+}
+
+// pullRequestCommentsRepository includes the requested fields of the GraphQL type Repository.
+// The GraphQL type's documentation follows.
+//
+// A repository contains the content for a project.
+type pullRequestCommentsRepository struct {
+	// Returns a single pull request from the current repository by number.
+	PullRequest *pullRequestCommentsRepositoryPullRequest `json:"pullRequest"`
+}
+
+// GetPullRequest returns pullRequestCommentsRepository.PullRequest, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsRepository) GetPullRequest() *pullRequestCommentsRepositoryPullRequest {
+	return v.PullRequest
+}
+
+// pullRequestCommentsRepositoryPullRequest includes the requested fields of the GraphQL type PullRequest.
+// The GraphQL type's documentation follows.
+//
+// A repository pull request.
+type pullRequestCommentsRepositoryPullRequest struct {
+	// A list of comments associated with the pull request.
+	Comments pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection `json:"comments"`
+}
+
+// GetComments returns pullRequestCommentsRepositoryPullRequest.Comments, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsRepositoryPullRequest) GetComments() pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection {
+	return v.Comments
+}
+
+// pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection includes the requested fields of the GraphQL type IssueCommentConnection.
+// The GraphQL type's documentation follows.
+//
+// The connection type for IssueComment.
+type pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection struct {
+	// Information to aid in pagination.
+	PageInfo pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo `json:"pageInfo"`
+	// Identifies the total count of items in the connection.
+	TotalCount int `json:"totalCount"`
+	// A list of nodes.
+	Nodes []*pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment `json:"nodes"`
+}
+
+// GetPageInfo returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection.PageInfo, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection) GetPageInfo() pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo {
+	return v.PageInfo
+}
+
+// GetTotalCount returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection.TotalCount, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection) GetTotalCount() int {
+	return v.TotalCount
+}
+
+// GetNodes returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection.Nodes, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnection) GetNodes() []*pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment {
+	return v.Nodes
+}
+
+// pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment includes the requested fields of the GraphQL type IssueComment.
+// The GraphQL type's documentation follows.
+//
+// Represents a comment on an Issue.
+type pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment struct {
+	CommentInfoIssueComment `json:"-"`
+}
+
+// GetId returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.Id, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) GetId() string {
+	return v.CommentInfoIssueComment.CommonCommentInfoIssueComment.Id
+}
+
+// GetAuthor returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.Author, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) GetAuthor() *CommonCommentInfoAuthorActor {
+	return v.CommentInfoIssueComment.CommonCommentInfoIssueComment.Author
+}
+
+// GetRaw returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.Raw, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) GetRaw() string {
+	return v.CommentInfoIssueComment.CommonCommentInfoIssueComment.Raw
+}
+
+// GetBodyText returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.BodyText, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) GetBodyText() string {
+	return v.CommentInfoIssueComment.CommonCommentInfoIssueComment.BodyText
+}
+
+// GetBodyHTML returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.BodyHTML, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) GetBodyHTML() string {
+	return v.CommentInfoIssueComment.CommonCommentInfoIssueComment.BodyHTML
+}
+
+// GetCreatedAt returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.CreatedAt, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) GetCreatedAt() time.Time {
+	return v.CommentInfoIssueComment.CommonCommentInfoIssueComment.CreatedAt
+}
+
+// GetReactions returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.Reactions, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) GetReactions() ReactionsInfoReactionsReactionConnection {
+	return v.CommentInfoIssueComment.ReactionsInfoIssueComment.Reactions
+}
+
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		return nil
+	}
+
+	var firstPass struct {
+		*pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment
+		graphql.NoUnmarshalJSON
+	}
+	firstPass.pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment = v
+
+	err := json.Unmarshal(b, &firstPass)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(
+		b, &v.CommentInfoIssueComment)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type __premarshalpullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment struct {
+	Id string `json:"id"`
+
+	Author json.RawMessage `json:"author"`
+
+	Raw string `json:"raw"`
+
+	BodyText string `json:"bodyText"`
+
+	BodyHTML string `json:"bodyHTML"`
+
+	CreatedAt time.Time `json:"createdAt"`
+
+	Reactions ReactionsInfoReactionsReactionConnection `json:"reactions"`
+}
+
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) MarshalJSON() ([]byte, error) {
+	premarshaled, err := v.__premarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(premarshaled)
+}
+
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) __premarshalJSON() (*__premarshalpullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment, error) {
+	var retval __premarshalpullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment
+
+	retval.Id = v.CommentInfoIssueComment.CommonCommentInfoIssueComment.Id
+	{
+
+		dst := &retval.Author
+		src := v.CommentInfoIssueComment.CommonCommentInfoIssueComment.Author
+		if src != nil {
+			var err error
+			*dst, err = __marshalCommonCommentInfoAuthorActor(
+				src)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"Unable to marshal pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.CommentInfoIssueComment.CommonCommentInfoIssueComment.Author: %w", err)
+			}
+		}
+	}
+	retval.Raw = v.CommentInfoIssueComment.CommonCommentInfoIssueComment.Raw
+	retval.BodyText = v.CommentInfoIssueComment.CommonCommentInfoIssueComment.BodyText
+	retval.BodyHTML = v.CommentInfoIssueComment.CommonCommentInfoIssueComment.BodyHTML
+	retval.CreatedAt = v.CommentInfoIssueComment.CommonCommentInfoIssueComment.CreatedAt
+	retval.Reactions = v.CommentInfoIssueComment.ReactionsInfoIssueComment.Reactions
+	return &retval, nil
+}
+
+// pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo includes the requested fields of the GraphQL type PageInfo.
+// The GraphQL type's documentation follows.
+//
+// Information about pagination in a connection.
+type pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo struct {
+	NextPageInfo `json:"-"`
+}
+
+// GetHasNextPage returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo.HasNextPage, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo) GetHasNextPage() bool {
+	return v.NextPageInfo.HasNextPage
+}
+
+// GetEndCursor returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo.EndCursor, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo) GetEndCursor() *string {
+	return v.NextPageInfo.EndCursor
+}
+
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		return nil
+	}
+
+	var firstPass struct {
+		*pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo
+		graphql.NoUnmarshalJSON
+	}
+	firstPass.pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo = v
+
+	err := json.Unmarshal(b, &firstPass)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(
+		b, &v.NextPageInfo)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type __premarshalpullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo struct {
+	HasNextPage bool `json:"hasNextPage"`
+
+	EndCursor *string `json:"endCursor"`
+}
+
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo) MarshalJSON() ([]byte, error) {
+	premarshaled, err := v.__premarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(premarshaled)
+}
+
+func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo) __premarshalJSON() (*__premarshalpullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo, error) {
+	var retval __premarshalpullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo
+
+	retval.HasNextPage = v.NextPageInfo.HasNextPage
+	retval.EndCursor = v.NextPageInfo.EndCursor
+	return &retval, nil
+}
+
+// pullRequestCommentsResponse is returned by pullRequestComments on success.
+type pullRequestCommentsResponse struct {
+	// Lookup a given repository by the owner and repository name.
+	Repository *pullRequestCommentsRepository `json:"repository"`
+}
+
+// GetRepository returns pullRequestCommentsResponse.Repository, and is useful for accessing the field via an interface.
+func (v *pullRequestCommentsResponse) GetRepository() *pullRequestCommentsRepository {
+	return v.Repository
+}
+
+// pullRequestSearchResponse is returned by pullRequestSearch on success.
+type pullRequestSearchResponse struct {
+	// Perform a search across resources, returning a maximum of 1,000 results.
+	Search pullRequestSearchSearchSearchResultItemConnection `json:"search"`
+}
+
+// GetSearch returns pullRequestSearchResponse.Search, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchResponse) GetSearch() pullRequestSearchSearchSearchResultItemConnection {
+	return v.Search
+}
+
+// pullRequestSearchSearchSearchResultItemConnection includes the requested fields of the GraphQL type SearchResultItemConnection.
+// The GraphQL type's documentation follows.
+//
+// A list of results that matched against a search query. Regardless of the number of matches, a maximum of 1,000 results will be available across all types, potentially split across many pages.
+type pullRequestSearchSearchSearchResultItemConnection struct {
+	// Information to aid in pagination.
+	PageInfo pullRequestSearchSearchSearchResultItemConnectionPageInfo `json:"pageInfo"`
+	// A list of nodes.
+	Nodes []*pullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem `json:"-"`
+}
+
+// GetPageInfo returns pullRequestSearchSearchSearchResultItemConnection.PageInfo, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnection) GetPageInfo() pullRequestSearchSearchSearchResultItemConnectionPageInfo {
+	return v.PageInfo
+}
+
+// GetNodes returns pullRequestSearchSearchSearchResultItemConnection.Nodes, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnection) GetNodes() []*pullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem {
+	return v.Nodes
+}
+
+func (v *pullRequestSearchSearchSearchResultItemConnection) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		return nil
+	}
+
+	var firstPass struct {
+		*pullRequestSearchSearchSearchResultItemConnection
+		Nodes []json.RawMessage `json:"nodes"`
+		graphql.NoUnmarshalJSON
+	}
+	firstPass.pullRequestSearchSearchSearchResultItemConnection = v
+
+	err := json.Unmarshal(b, &firstPass)
+	if err != nil {
+		return err
+	}
+
+	{
+		dst := &v.Nodes
+		src := firstPass.Nodes
+		*dst = make(
+			[]*pullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem,
+			len(src))
+		for i, src := range src {
+			dst := &(*dst)[i]
+			if len(src) != 0 && string(src) != "null" {
+				*dst = new(pullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem)
+				err = __unmarshalpullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem(
+					src, *dst)
+				if err != nil {
+					return fmt.Errorf(
+						"Unable to unmarshal pullRequestSearchSearchSearchResultItemConnection.Nodes: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+type __premarshalpullRequestSearchSearchSearchResultItemConnection struct {
+	PageInfo pullRequestSearchSearchSearchResultItemConnectionPageInfo `json:"pageInfo"`
+
+	Nodes []json.RawMessage `json:"nodes"`
+}
+
+func (v *pullRequestSearchSearchSearchResultItemConnection) MarshalJSON() ([]byte, error) {
+	premarshaled, err := v.__premarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(premarshaled)
+}
+
+func (v *pullRequestSearchSearchSearchResultItemConnection) __premarshalJSON() (*__premarshalpullRequestSearchSearchSearchResultItemConnection, error) {
+	var retval __premarshalpullRequestSearchSearchSearchResultItemConnection
+
+	retval.PageInfo = v.PageInfo
+	{
+
+		dst := &retval.Nodes
+		src := v.Nodes
+		*dst = make(
+			[]json.RawMessage,
+			len(src))
+		for i, src := range src {
+			dst := &(*dst)[i]
+			if src != nil {
+				var err error
+				*dst, err = __marshalpullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem(
+					src)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"Unable to marshal pullRequestSearchSearchSearchResultItemConnection.Nodes: %w", err)
+				}
+			}
+		}
+	}
+	return &retval, nil
+}
+
+// pullRequestSearchSearchSearchResultItemConnectionNodesApp includes the requested fields of the GraphQL type App.
+// The GraphQL type's documentation follows.
+//
+// A GitHub App.
+type pullRequestSearchSearchSearchResultItemConnectionNodesApp struct {
+	Typename *string `json:"__typename"`
+}
+
+// GetTypename returns pullRequestSearchSearchSearchResultItemConnectionNodesApp.Typename, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesApp) GetTypename() *string {
+	return v.Typename
+}
+
+// pullRequestSearchSearchSearchResultItemConnectionNodesDiscussion includes the requested fields of the GraphQL type Discussion.
+// The GraphQL type's documentation follows.
+//
+// A discussion in a repository.
+type pullRequestSearchSearchSearchResultItemConnectionNodesDiscussion struct {
+	Typename *string `json:"__typename"`
+}
+
+// GetTypename returns pullRequestSearchSearchSearchResultItemConnectionNodesDiscussion.Typename, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesDiscussion) GetTypename() *string {
+	return v.Typename
+}
+
+// pullRequestSearchSearchSearchResultItemConnectionNodesIssue includes the requested fields of the GraphQL type Issue.
+// The GraphQL type's documentation follows.
+//
+// An Issue is a place to discuss ideas, enhancements, tasks, and bugs for a project.
+type pullRequestSearchSearchSearchResultItemConnectionNodesIssue struct {
+	Typename *string `json:"__typename"`
+}
+
+// GetTypename returns pullRequestSearchSearchSearchResultItemConnectionNodesIssue.Typename, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesIssue) GetTypename() *string {
+	return v.Typename
+}
+
+// pullRequestSearchSearchSearchResultItemConnectionNodesMarketplaceListing includes the requested fields of the GraphQL type MarketplaceListing.
+// The GraphQL type's documentation follows.
+//
+// A listing in the GitHub integration marketplace.
+type pullRequestSearchSearchSearchResultItemConnectionNodesMarketplaceListing struct {
+	Typename *string `json:"__typename"`
+}
+
+// GetTypename returns pullRequestSearchSearchSearchResultItemConnectionNodesMarketplaceListing.Typename, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesMarketplaceListing) GetTypename() *string {
+	return v.Typename
+}
+
+// pullRequestSearchSearchSearchResultItemConnectionNodesOrganization includes the requested fields of the GraphQL type Organization.
+// The GraphQL type's documentation follows.
+//
+// An account on GitHub, with one or more owners, that has repositories, members and teams.
+type pullRequestSearchSearchSearchResultItemConnectionNodesOrganization struct {
+	Typename *string `json:"__typename"`
+}
+
+// GetTypename returns pullRequestSearchSearchSearchResultItemConnectionNodesOrganization.Typename, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesOrganization) GetTypename() *string {
+	return v.Typename
+}
+
+// pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest includes the requested fields of the GraphQL type PullRequest.
+// The GraphQL type's documentation follows.
+//
+// A repository pull request.
+type pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest struct {
+	Typename          *string `json:"__typename"`
+	listedPullRequest `json:"-"`
+}
+
+// GetTypename returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.Typename, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetTypename() *string {
+	return v.Typename
+}
+
+// GetId returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.Id, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetId() string {
+	return v.listedPullRequest.Id
+}
+
+// GetDatabaseId returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.DatabaseId, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetDatabaseId() *int {
+	return v.listedPullRequest.DatabaseId
+}
+
+// GetNumber returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.Number, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetNumber() int {
+	return v.listedPullRequest.Number
+}
+
+// GetTitle returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.Title, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetTitle() string {
+	return v.listedPullRequest.Title
+}
+
+// GetBody returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.Body, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetBody() string {
+	return v.listedPullRequest.Body
+}
+
+// GetState returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.State, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetState() PullRequestState {
+	return v.listedPullRequest.State
+}
+
+// GetIsDraft returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.IsDraft, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetIsDraft() bool {
+	return v.listedPullRequest.IsDraft
+}
+
+// GetUrl returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.Url, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetUrl() string {
+	return v.listedPullRequest.Url
+}
+
+// GetCreatedAt returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.CreatedAt, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetCreatedAt() time.Time {
+	return v.listedPullRequest.CreatedAt
+}
+
+// GetUpdatedAt returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.UpdatedAt, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetUpdatedAt() time.Time {
+	return v.listedPullRequest.UpdatedAt
+}
+
+// GetClosedAt returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.ClosedAt, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetClosedAt() *time.Time {
+	return v.listedPullRequest.ClosedAt
+}
+
+// GetMergedAt returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.MergedAt, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetMergedAt() *time.Time {
+	return v.listedPullRequest.MergedAt
+}
+
+// GetAuthor returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.Author, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetAuthor() *listedPullRequestAuthorActor {
+	return v.listedPullRequest.Author
+}
+
+// GetBaseRefName returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.BaseRefName, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetBaseRefName() string {
+	return v.listedPullRequest.BaseRefName
+}
+
+// GetBaseRefOid returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.BaseRefOid, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetBaseRefOid() string {
+	return v.listedPullRequest.BaseRefOid
+}
+
+// GetBaseRepository returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.BaseRepository, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetBaseRepository() *listedPullRequestBaseRepository {
+	return v.listedPullRequest.BaseRepository
+}
+
+// GetHeadRefName returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.HeadRefName, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetHeadRefName() string {
+	return v.listedPullRequest.HeadRefName
+}
+
+// GetHeadRefOid returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.HeadRefOid, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetHeadRefOid() string {
+	return v.listedPullRequest.HeadRefOid
+}
+
+// GetHeadRepository returns pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.HeadRepository, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) GetHeadRepository() *listedPullRequestHeadRepository {
+	return v.listedPullRequest.HeadRepository
+}
+
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		return nil
+	}
+
+	var firstPass struct {
+		*pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest
+		graphql.NoUnmarshalJSON
+	}
+	firstPass.pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest = v
+
+	err := json.Unmarshal(b, &firstPass)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(
+		b, &v.listedPullRequest)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type __premarshalpullRequestSearchSearchSearchResultItemConnectionNodesPullRequest struct {
+	Typename *string `json:"__typename"`
+
+	Id string `json:"id"`
+
+	DatabaseId *int `json:"databaseId"`
+
+	Number int `json:"number"`
+
+	Title string `json:"title"`
+
+	Body string `json:"body"`
+
+	State PullRequestState `json:"state"`
+
+	IsDraft bool `json:"isDraft"`
+
+	Url string `json:"url"`
+
+	CreatedAt time.Time `json:"createdAt"`
+
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	ClosedAt *time.Time `json:"closedAt"`
+
+	MergedAt *time.Time `json:"mergedAt"`
+
+	Author json.RawMessage `json:"author"`
+
+	BaseRefName string `json:"baseRefName"`
+
+	BaseRefOid string `json:"baseRefOid"`
+
+	BaseRepository *listedPullRequestBaseRepository `json:"baseRepository"`
+
+	HeadRefName string `json:"headRefName"`
+
+	HeadRefOid string `json:"headRefOid"`
+
+	HeadRepository *listedPullRequestHeadRepository `json:"headRepository"`
+}
+
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) MarshalJSON() ([]byte, error) {
+	premarshaled, err := v.__premarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(premarshaled)
+}
+
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) __premarshalJSON() (*__premarshalpullRequestSearchSearchSearchResultItemConnectionNodesPullRequest, error) {
+	var retval __premarshalpullRequestSearchSearchSearchResultItemConnectionNodesPullRequest
+
+	retval.Typename = v.Typename
+	retval.Id = v.listedPullRequest.Id
+	retval.DatabaseId = v.listedPullRequest.DatabaseId
+	retval.Number = v.listedPullRequest.Number
+	retval.Title = v.listedPullRequest.Title
+	retval.Body = v.listedPullRequest.Body
+	retval.State = v.listedPullRequest.State
+	retval.IsDraft = v.listedPullRequest.IsDraft
+	retval.Url = v.listedPullRequest.Url
+	retval.CreatedAt = v.listedPullRequest.CreatedAt
+	retval.UpdatedAt = v.listedPullRequest.UpdatedAt
+	retval.ClosedAt = v.listedPullRequest.ClosedAt
+	retval.MergedAt = v.listedPullRequest.MergedAt
+	{
+
+		dst := &retval.Author
+		src := v.listedPullRequest.Author
+		if src != nil {
+			var err error
+			*dst, err = __marshallistedPullRequestAuthorActor(
+				src)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"Unable to marshal pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest.listedPullRequest.Author: %w", err)
+			}
+		}
+	}
+	retval.BaseRefName = v.listedPullRequest.BaseRefName
+	retval.BaseRefOid = v.listedPullRequest.BaseRefOid
+	retval.BaseRepository = v.listedPullRequest.BaseRepository
+	retval.HeadRefName = v.listedPullRequest.HeadRefName
+	retval.HeadRefOid = v.listedPullRequest.HeadRefOid
+	retval.HeadRepository = v.listedPullRequest.HeadRepository
+	return &retval, nil
+}
+
+// pullRequestSearchSearchSearchResultItemConnectionNodesRepository includes the requested fields of the GraphQL type Repository.
+// The GraphQL type's documentation follows.
+//
+// A repository contains the content for a project.
+type pullRequestSearchSearchSearchResultItemConnectionNodesRepository struct {
+	Typename *string `json:"__typename"`
+}
+
+// GetTypename returns pullRequestSearchSearchSearchResultItemConnectionNodesRepository.Typename, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesRepository) GetTypename() *string {
+	return v.Typename
+}
+
+// pullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem includes the requested fields of the GraphQL interface SearchResultItem.
+//
+// pullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem is implemented by the following types:
+// pullRequestSearchSearchSearchResultItemConnectionNodesApp
+// pullRequestSearchSearchSearchResultItemConnectionNodesDiscussion
+// pullRequestSearchSearchSearchResultItemConnectionNodesIssue
+// pullRequestSearchSearchSearchResultItemConnectionNodesMarketplaceListing
+// pullRequestSearchSearchSearchResultItemConnectionNodesOrganization
+// pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest
+// pullRequestSearchSearchSearchResultItemConnectionNodesRepository
+// pullRequestSearchSearchSearchResultItemConnectionNodesUser
+// The GraphQL type's documentation follows.
+//
+// The results of a search.
+type pullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem interface {
+	implementsGraphQLInterfacepullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem()
+	// GetTypename returns the receiver's concrete GraphQL type-name (see interface doc for possible values).
+	GetTypename() *string
+}
+
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesApp) implementsGraphQLInterfacepullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem() {
+}
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesDiscussion) implementsGraphQLInterfacepullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem() {
+}
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesIssue) implementsGraphQLInterfacepullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem() {
+}
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesMarketplaceListing) implementsGraphQLInterfacepullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem() {
+}
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesOrganization) implementsGraphQLInterfacepullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem() {
+}
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest) implementsGraphQLInterfacepullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem() {
+}
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesRepository) implementsGraphQLInterfacepullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem() {
 }
-
-// GetReactions returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.Reactions, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) GetReactions() ReactionsInfoReactionsReactionConnection {
-	return v.CommentInfoIssueComment.ReactionsInfoIssueComment.Reactions
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesUser) implementsGraphQLInterfacepullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem() {
 }
 
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) UnmarshalJSON(b []byte) error {
-
+func __unmarshalpullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem(b []byte, v *pullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem) error {
 	if string(b) == "null" {
 		return nil
 	}
 
-	var firstPass struct {
-		*pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment
-		graphql.NoUnmarshalJSON
+	var tn struct {
+		TypeName string `json:"__typename"`
 	}
-	firstPass.pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment = v
-
-	err := json.Unmarshal(b, &firstPass)
+	err := json.Unmarshal(b, &tn)
 	if err != nil {
 		return err
 	}
 
-	err = json.Unmarshal(
-		b, &v.CommentInfoIssueComment)
-	if err != nil {
-		return err
+	switch tn.TypeName {
+	case "App":
+		*v = new(pullRequestSearchSearchSearchResultItemConnectionNodesApp)
+		return json.Unmarshal(b, *v)
+	case "Discussion":
+		*v = new(pullRequestSearchSearchSearchResultItemConnectionNodesDiscussion)
+		return json.Unmarshal(b, *v)
+	case "Issue":
+		*v = new(pullRequestSearchSearchSearchResultItemConnectionNodesIssue)
+		return json.Unmarshal(b, *v)
+	case "MarketplaceListing":
+		*v = new(pullRequestSearchSearchSearchResultItemConnectionNodesMarketplaceListing)
+		return json.Unmarshal(b, *v)
+	case "Organization":
+		*v = new(pullRequestSearchSearchSearchResultItemConnectionNodesOrganization)
+		return json.Unmarshal(b, *v)
+	case "PullRequest":
+		*v = new(pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest)
+		return json.Unmarshal(b, *v)
+	case "Repository":
+		*v = new(pullRequestSearchSearchSearchResultItemConnectionNodesRepository)
+		return json.Unmarshal(b, *v)
+	case "User":
+		*v = new(pullRequestSearchSearchSearchResultItemConnectionNodesUser)
+		return json.Unmarshal(b, *v)
+	case "":
+		return fmt.Errorf(
+			"response was missing SearchResultItem.__typename")
+	default:
+		return fmt.Errorf(
+			`unexpected concrete type for pullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem: "%v"`, tn.TypeName)
 	}
-	return nil
 }
 
-type __premarshalpullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment struct {
-	Id string `json:"id"`
+func __marshalpullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem(v *pullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem) ([]byte, error) {
 
-	Author json.RawMessage `json:"author"`
+	var typename string
+	switch v := (*v).(type) {
+	case *pullRequestSearchSearchSearchResultItemConnectionNodesApp:
+		typename = "App"
 
-	Raw string `json:"raw"`
+		result := struct {
+			TypeName string `json:"__typename"`
+			*pullRequestSearchSearchSearchResultItemConnectionNodesApp
+		}{typename, v}
+		return json.Marshal(result)
+	case *pullRequestSearchSearchSearchResultItemConnectionNodesDiscussion:
+		typename = "Discussion"
 
-	BodyText string `json:"bodyText"`
+		result := struct {
+			TypeName string `json:"__typename"`
+			*pullRequestSearchSearchSearchResultItemConnectionNodesDiscussion
+		}{typename, v}
+		return json.Marshal(result)
+	case *pullRequestSearchSearchSearchResultItemConnectionNodesIssue:
+		typename = "Issue"
 
-	BodyHTML string `json:"bodyHTML"`
+		result := struct {
+			TypeName string `json:"__typename"`
+			*pullRequestSearchSearchSearchResultItemConnectionNodesIssue
+		}{typename, v}
+		return json.Marshal(result)
+	case *pullRequestSearchSearchSearchResultItemConnectionNodesMarketplaceListing:
+		typename = "MarketplaceListing"
 
-	CreatedAt time.Time `json:"createdAt"`
+		result := struct {
+			TypeName string `json:"__typename"`
+			*pullRequestSearchSearchSearchResultItemConnectionNodesMarketplaceListing
+		}{typename, v}
+		return json.Marshal(result)
+	case *pullRequestSearchSearchSearchResultItemConnectionNodesOrganization:
+		typename = "Organization"
 
-	Reactions ReactionsInfoReactionsReactionConnection `json:"reactions"`
-}
+		result := struct {
+			TypeName string `json:"__typename"`
+			*pullRequestSearchSearchSearchResultItemConnectionNodesOrganization
+		}{typename, v}
+		return json.Marshal(result)
+	case *pullRequestSearchSearchSearchResultItemConnectionNodesPullRequest:
+		typename = "PullRequest"
 
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) MarshalJSON() ([]byte, error) {
-	premarshaled, err := v.__premarshalJSON()
-	if err != nil {
-		return nil, err
+		premarshaled, err := v.__premarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		result := struct {
+			TypeName string `json:"__typename"`
+			*__premarshalpullRequestSearchSearchSearchResultItemConnectionNodesPullRequest
+		}{typename, premarshaled}
+		return json.Marshal(result)
+	case *pullRequestSearchSearchSearchResultItemConnectionNodesRepository:
+		typename = "Repository"
+
+		result := struct {
+			TypeName string `json:"__typename"`
+			*pullRequestSearchSearchSearchResultItemConnectionNodesRepository
+		}{typename, v}
+		return json.Marshal(result)
+	case *pullRequestSearchSearchSearchResultItemConnectionNodesUser:
+		typename = "User"
+
+		result := struct {
+			TypeName string `json:"__typename"`
+			*pullRequestSearchSearchSearchResultItemConnectionNodesUser
+		}{typename, v}
+		return json.Marshal(result)
+	case nil:
+		return []byte("null"), nil
+	default:
+		return nil, fmt.Errorf(
+			`unexpected concrete type for pullRequestSearchSearchSearchResultItemConnectionNodesSearchResultItem: "%T"`, v)
 	}
-	return json.Marshal(premarshaled)
 }
 
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment) __premarshalJSON() (*__premarshalpullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment, error) {
-	var retval __premarshalpullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment
-
-	retval.Id = v.CommentInfoIssueComment.CommonCommentInfoIssueComment.Id
-	{
+// pullRequestSearchSearchSearchResultItemConnectionNodesUser includes the requested fields of the GraphQL type User.
+// The GraphQL type's documentation follows.
+//
+// A user is an individual's account on GitHub that owns repositories and can make new content.
+type pullRequestSearchSearchSearchResultItemConnectionNodesUser struct {
+	Typename *string `json:"__typename"`
+}
 
-		dst := &retval.Author
-		src := v.CommentInfoIssueComment.CommonCommentInfoIssueComment.Author
-		if src != nil {
-			var err error
-			*dst, err = __marshalCommonCommentInfoAuthorActor(
-				src)
-			if err != nil {
-				return nil, fmt.Errorf(
-					"Unable to marshal pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionNodesIssueComment.CommentInfoIssueComment.CommonCommentInfoIssueComment.Author: %w", err)
-			}
-		}
-	}
-	retval.Raw = v.CommentInfoIssueComment.CommonCommentInfoIssueComment.Raw
-	retval.BodyText = v.CommentInfoIssueComment.CommonCommentInfoIssueComment.BodyText
-	retval.BodyHTML = v.CommentInfoIssueComment.CommonCommentInfoIssueComment.BodyHTML
-	retval.CreatedAt = v.CommentInfoIssueComment.CommonCommentInfoIssueComment.CreatedAt
-	retval.Reactions = v.CommentInfoIssueComment.ReactionsInfoIssueComment.Reactions
-	return &retval, nil
+// GetTypename returns pullRequestSearchSearchSearchResultItemConnectionNodesUser.Typename, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionNodesUser) GetTypename() *string {
+	return v.Typename
 }
 
-// pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo includes the requested fields of the GraphQL type PageInfo.
+// pullRequestSearchSearchSearchResultItemConnectionPageInfo includes the requested fields of the GraphQL type PageInfo.
 // The GraphQL type's documentation follows.
 //
 // Information about pagination in a connection.
-type pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo struct {
+type pullRequestSearchSearchSearchResultItemConnectionPageInfo struct {
 	NextPageInfo `json:"-"`
 }
 
-// GetHasNextPage returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo.HasNextPage, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo) GetHasNextPage() bool {
+// GetHasNextPage returns pullRequestSearchSearchSearchResultItemConnectionPageInfo.HasNextPage, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionPageInfo) GetHasNextPage() bool {
 	return v.NextPageInfo.HasNextPage
 }
 
-// GetEndCursor returns pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo.EndCursor, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo) GetEndCursor() *string {
+// GetEndCursor returns pullRequestSearchSearchSearchResultItemConnectionPageInfo.EndCursor, and is useful for accessing the field via an interface.
+func (v *pullRequestSearchSearchSearchResultItemConnectionPageInfo) GetEndCursor() *string {
 	return v.NextPageInfo.EndCursor
 }
 
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo) UnmarshalJSON(b []byte) error {
+func (v *pullRequestSearchSearchSearchResultItemConnectionPageInfo) UnmarshalJSON(b []byte) error {
 
 	if string(b) == "null" {
 		return nil
 	}
 
 	var firstPass struct {
-		*pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo
+		*pullRequestSearchSearchSearchResultItemConnectionPageInfo
 		graphql.NoUnmarshalJSON
 	}
-	firstPass.pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo = v
+	firstPass.pullRequestSearchSearchSearchResultItemConnectionPageInfo = v
 
 	err := json.Unmarshal(b, &firstPass)
 	if err != nil {
@@ -13394,13 +15178,13 @@ func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionP
 	return nil
 }
 
-type __premarshalpullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo struct {
+type __premarshalpullRequestSearchSearchSearchResultItemConnectionPageInfo struct {
 	HasNextPage bool `json:"hasNextPage"`
 
 	EndCursor *string `json:"endCursor"`
 }
 
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo) MarshalJSON() ([]byte, error) {
+func (v *pullRequestSearchSearchSearchResultItemConnectionPageInfo) MarshalJSON() ([]byte, error) {
 	premarshaled, err := v.__premarshalJSON()
 	if err != nil {
 		return nil, err
@@ -13408,25 +15192,14 @@ func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionP
 	return json.Marshal(premarshaled)
 }
 
-func (v *pullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo) __premarshalJSON() (*__premarshalpullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo, error) {
-	var retval __premarshalpullRequestCommentsRepositoryPullRequestCommentsIssueCommentConnectionPageInfo
+func (v *pullRequestSearchSearchSearchResultItemConnectionPageInfo) __premarshalJSON() (*__premarshalpullRequestSearchSearchSearchResultItemConnectionPageInfo, error) {
+	var retval __premarshalpullRequestSearchSearchSearchResultItemConnectionPageInfo
 
 	retval.HasNextPage = v.NextPageInfo.HasNextPage
 	retval.EndCursor = v.NextPageInfo.EndCursor
 	return &retval, nil
 }
 
-// pullRequestCommentsResponse is returned by pullRequestComments on success.
-type pullRequestCommentsResponse struct {
-	// Lookup a given repository by the owner and repository name.
-	Repository *pullRequestCommentsRepository `json:"repository"`
-}
-
-// GetRepository returns pullRequestCommentsResponse.Repository, and is useful for accessing the field via an interface.
-func (v *pullRequestCommentsResponse) GetRepository() *pullRequestCommentsRepository {
-	return v.Repository
-}
-
 // pullRequestThreadsRepository includes the requested fields of the GraphQL type Repository.
 // The GraphQL type's documentation follows.
 //
@@ -13733,46 +15506,198 @@ type pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThread
 	HasNextPage bool `json:"hasNextPage"`
 }
 
-// GetEndCursor returns pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionNodesPullRequestReviewThreadCommentsPullRequestReviewCommentConnectionPageInfo.EndCursor, and is useful for accessing the field via an interface.
-func (v *pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionNodesPullRequestReviewThreadCommentsPullRequestReviewCommentConnectionPageInfo) GetEndCursor() *string {
-	return v.EndCursor
+// GetEndCursor returns pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionNodesPullRequestReviewThreadCommentsPullRequestReviewCommentConnectionPageInfo.EndCursor, and is useful for accessing the field via an interface.
+func (v *pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionNodesPullRequestReviewThreadCommentsPullRequestReviewCommentConnectionPageInfo) GetEndCursor() *string {
+	return v.EndCursor
+}
+
+// GetHasNextPage returns pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionNodesPullRequestReviewThreadCommentsPullRequestReviewCommentConnectionPageInfo.HasNextPage, and is useful for accessing the field via an interface.
+func (v *pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionNodesPullRequestReviewThreadCommentsPullRequestReviewCommentConnectionPageInfo) GetHasNextPage() bool {
+	return v.HasNextPage
+}
+
+// pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionPageInfo includes the requested fields of the GraphQL type PageInfo.
+// The GraphQL type's documentation follows.
+//
+// Information about pagination in a connection.
+type pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionPageInfo struct {
+	// When paginating forwards, the cursor to continue.
+	EndCursor *string `json:"endCursor"`
+	// When paginating forwards, are there more items?
+	HasNextPage bool `json:"hasNextPage"`
+}
+
+// GetEndCursor returns pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionPageInfo.EndCursor, and is useful for accessing the field via an interface.
+func (v *pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionPageInfo) GetEndCursor() *string {
+	return v.EndCursor
+}
+
+// GetHasNextPage returns pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionPageInfo.HasNextPage, and is useful for accessing the field via an interface.
+func (v *pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionPageInfo) GetHasNextPage() bool {
+	return v.HasNextPage
+}
+
+// pullRequestThreadsResponse is returned by pullRequestThreads on success.
+type pullRequestThreadsResponse struct {
+	// Lookup a given repository by the owner and repository name.
+	Repository *pullRequestThreadsRepository `json:"repository"`
+}
+
+// GetRepository returns pullRequestThreadsResponse.Repository, and is useful for accessing the field via an interface.
+func (v *pullRequestThreadsResponse) GetRepository() *pullRequestThreadsRepository {
+	return v.Repository
+}
+
+// pullRequestViewedFilesRepository includes the requested fields of the GraphQL type Repository.
+// The GraphQL type's documentation follows.
+//
+// A repository contains the content for a project.
+type pullRequestViewedFilesRepository struct {
+	// Returns a single pull request from the current repository by number.
+	PullRequest *pullRequestViewedFilesRepositoryPullRequest `json:"pullRequest"`
+}
+
+// GetPullRequest returns pullRequestViewedFilesRepository.PullRequest, and is useful for accessing the field via an interface.
+func (v *pullRequestViewedFilesRepository) GetPullRequest() *pullRequestViewedFilesRepositoryPullRequest {
+	return v.PullRequest
+}
+
+// pullRequestViewedFilesRepositoryPullRequest includes the requested fields of the GraphQL type PullRequest.
+// The GraphQL type's documentation follows.
+//
+// A repository pull request.
+type pullRequestViewedFilesRepositoryPullRequest struct {
+	// Lists the files changed within this pull request.
+	Files *pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnection `json:"files"`
+}
+
+// GetFiles returns pullRequestViewedFilesRepositoryPullRequest.Files, and is useful for accessing the field via an interface.
+func (v *pullRequestViewedFilesRepositoryPullRequest) GetFiles() *pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnection {
+	return v.Files
+}
+
+// pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnection includes the requested fields of the GraphQL type PullRequestChangedFileConnection.
+// The GraphQL type's documentation follows.
+//
+// The connection type for PullRequestChangedFile.
+type pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnection struct {
+	// A list of nodes.
+	Nodes []*pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionNodesPullRequestChangedFile `json:"nodes"`
+	// Information to aid in pagination.
+	PageInfo pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionPageInfo `json:"pageInfo"`
+}
+
+// GetNodes returns pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnection.Nodes, and is useful for accessing the field via an interface.
+func (v *pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnection) GetNodes() []*pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionNodesPullRequestChangedFile {
+	return v.Nodes
+}
+
+// GetPageInfo returns pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnection.PageInfo, and is useful for accessing the field via an interface.
+func (v *pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnection) GetPageInfo() pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionPageInfo {
+	return v.PageInfo
+}
+
+// pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionNodesPullRequestChangedFile includes the requested fields of the GraphQL type PullRequestChangedFile.
+// The GraphQL type's documentation follows.
+//
+// A file changed in a pull request.
+type pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionNodesPullRequestChangedFile struct {
+	// The path of the file.
+	Path string `json:"path"`
+	// The state of the file for the viewer.
+	ViewerViewedState FileViewedState `json:"viewerViewedState"`
+}
+
+// GetPath returns pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionNodesPullRequestChangedFile.Path, and is useful for accessing the field via an interface.
+func (v *pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionNodesPullRequestChangedFile) GetPath() string {
+	return v.Path
+}
+
+// GetViewerViewedState returns pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionNodesPullRequestChangedFile.ViewerViewedState, and is useful for accessing the field via an interface.
+func (v *pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionNodesPullRequestChangedFile) GetViewerViewedState() FileViewedState {
+	return v.ViewerViewedState
+}
+
+// pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionPageInfo includes the requested fields of the GraphQL type PageInfo.
+// The GraphQL type's documentation follows.
+//
+// Information about pagination in a connection.
+type pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionPageInfo struct {
+	// When paginating forwards, are there more items?
+	HasNextPage bool `json:"hasNextPage"`
+	// When paginating forwards, the cursor to continue.
+	EndCursor *string `json:"endCursor"`
+}
+
+// GetHasNextPage returns pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionPageInfo.HasNextPage, and is useful for accessing the field via an interface.
+func (v *pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionPageInfo) GetHasNextPage() bool {
+	return v.HasNextPage
+}
+
+// GetEndCursor returns pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionPageInfo.EndCursor, and is useful for accessing the field via an interface.
+func (v *pullRequestViewedFilesRepositoryPullRequestFilesPullRequestChangedFileConnectionPageInfo) GetEndCursor() *string {
+	return v.EndCursor
+}
+
+// pullRequestViewedFilesResponse is returned by pullRequestViewedFiles on success.
+type pullRequestViewedFilesResponse struct {
+	// Lookup a given repository by the owner and repository name.
+	Repository *pullRequestViewedFilesRepository `json:"repository"`
 }
 
-// GetHasNextPage returns pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionNodesPullRequestReviewThreadCommentsPullRequestReviewCommentConnectionPageInfo.HasNextPage, and is useful for accessing the field via an interface.
-func (v *pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionNodesPullRequestReviewThreadCommentsPullRequestReviewCommentConnectionPageInfo) GetHasNextPage() bool {
-	return v.HasNextPage
+// GetRepository returns pullRequestViewedFilesResponse.Repository, and is useful for accessing the field via an interface.
+func (v *pullRequestViewedFilesResponse) GetRepository() *pullRequestViewedFilesRepository {
+	return v.Repository
 }
 
-// pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionPageInfo includes the requested fields of the GraphQL type PageInfo.
+// removeLabelsFromPullRequestRemoveLabelsFromLabelableRemoveLabelsFromLabelablePayload includes the requested fields of the GraphQL type RemoveLabelsFromLabelablePayload.
 // The GraphQL type's documentation follows.
 //
-// Information about pagination in a connection.
-type pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionPageInfo struct {
-	// When paginating forwards, the cursor to continue.
-	EndCursor *string `json:"endCursor"`
-	// When paginating forwards, are there more items?
-	HasNextPage bool `json:"hasNextPage"`
+// Autogenerated return type of RemoveLabelsFromLabelable
+type removeLabelsFromPullRequestRemoveLabelsFromLabelableRemoveLabelsFromLabelablePayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationId *string `json:"clientMutationId"`
 }
 
-// GetEndCursor returns pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionPageInfo.EndCursor, and is useful for accessing the field via an interface.
-func (v *pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionPageInfo) GetEndCursor() *string {
-	return v.EndCursor
+// GetClientMutationId returns removeLabelsFromPullRequestRemoveLabelsFromLabelableRemoveLabelsFromLabelablePayload.ClientMutationId, and is useful for accessing the field via an interface.
+func (v *removeLabelsFromPullRequestRemoveLabelsFromLabelableRemoveLabelsFromLabelablePayload) GetClientMutationId() *string {
+	return v.ClientMutationId
 }
 
-// GetHasNextPage returns pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionPageInfo.HasNextPage, and is useful for accessing the field via an interface.
-func (v *pullRequestThreadsRepositoryPullRequestReviewThreadsPullRequestReviewThreadConnectionPageInfo) GetHasNextPage() bool {
-	return v.HasNextPage
+// removeLabelsFromPullRequestResponse is returned by removeLabelsFromPullRequest on success.
+type removeLabelsFromPullRequestResponse struct {
+	// Removes labels from a Labelable object.
+	RemoveLabelsFromLabelable *removeLabelsFromPullRequestRemoveLabelsFromLabelableRemoveLabelsFromLabelablePayload `json:"removeLabelsFromLabelable"`
 }
 
-// pullRequestThreadsResponse is returned by pullRequestThreads on success.
-type pullRequestThreadsResponse struct {
-	// Lookup a given repository by the owner and repository name.
-	Repository *pullRequestThreadsRepository `json:"repository"`
+// GetRemoveLabelsFromLabelable returns removeLabelsFromPullRequestResponse.RemoveLabelsFromLabelable, and is useful for accessing the field via an interface.
+func (v *removeLabelsFromPullRequestResponse) GetRemoveLabelsFromLabelable() *removeLabelsFromPullRequestRemoveLabelsFromLabelableRemoveLabelsFromLabelablePayload {
+	return v.RemoveLabelsFromLabelable
 }
 
-// GetRepository returns pullRequestThreadsResponse.Repository, and is useful for accessing the field via an interface.
-func (v *pullRequestThreadsResponse) GetRepository() *pullRequestThreadsRepository {
-	return v.Repository
+// reopenPullRequestReopenPullRequestReopenPullRequestPayload includes the requested fields of the GraphQL type ReopenPullRequestPayload.
+// The GraphQL type's documentation follows.
+//
+// Autogenerated return type of ReopenPullRequest
+type reopenPullRequestReopenPullRequestReopenPullRequestPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationId *string `json:"clientMutationId"`
+}
+
+// GetClientMutationId returns reopenPullRequestReopenPullRequestReopenPullRequestPayload.ClientMutationId, and is useful for accessing the field via an interface.
+func (v *reopenPullRequestReopenPullRequestReopenPullRequestPayload) GetClientMutationId() *string {
+	return v.ClientMutationId
+}
+
+// reopenPullRequestResponse is returned by reopenPullRequest on success.
+type reopenPullRequestResponse struct {
+	// Reopen a pull request.
+	ReopenPullRequest *reopenPullRequestReopenPullRequestReopenPullRequestPayload `json:"reopenPullRequest"`
+}
+
+// GetReopenPullRequest returns reopenPullRequestResponse.ReopenPullRequest, and is useful for accessing the field via an interface.
+func (v *reopenPullRequestResponse) GetReopenPullRequest() *reopenPullRequestReopenPullRequestReopenPullRequestPayload {
+	return v.ReopenPullRequest
 }
 
 // replyToAddPullRequestReviewCommentAddPullRequestReviewCommentPayload includes the requested fields of the GraphQL type AddPullRequestReviewCommentPayload.
@@ -21633,6 +23558,31 @@ func (v *singleStatusResponse) __premarshalJSON() (*__premarshalsingleStatusResp
 	return &retval, nil
 }
 
+// unmarkFileAsViewedResponse is returned by unmarkFileAsViewed on success.
+type unmarkFileAsViewedResponse struct {
+	// Unmark a pull request file as viewed
+	UnmarkFileAsViewed *unmarkFileAsViewedUnmarkFileAsViewedUnmarkFileAsViewedPayload `json:"unmarkFileAsViewed"`
+}
+
+// GetUnmarkFileAsViewed returns unmarkFileAsViewedResponse.UnmarkFileAsViewed, and is useful for accessing the field via an interface.
+func (v *unmarkFileAsViewedResponse) GetUnmarkFileAsViewed() *unmarkFileAsViewedUnmarkFileAsViewedUnmarkFileAsViewedPayload {
+	return v.UnmarkFileAsViewed
+}
+
+// unmarkFileAsViewedUnmarkFileAsViewedUnmarkFileAsViewedPayload includes the requested fields of the GraphQL type UnmarkFileAsViewedPayload.
+// The GraphQL type's documentation follows.
+//
+// Autogenerated return type of UnmarkFileAsViewed
+type unmarkFileAsViewedUnmarkFileAsViewedUnmarkFileAsViewedPayload struct {
+	// A unique identifier for the client performing the mutation.
+	ClientMutationId *string `json:"clientMutationId"`
+}
+
+// GetClientMutationId returns unmarkFileAsViewedUnmarkFileAsViewedUnmarkFileAsViewedPayload.ClientMutationId, and is useful for accessing the field via an interface.
+func (v *unmarkFileAsViewedUnmarkFileAsViewedUnmarkFileAsViewedPayload) GetClientMutationId() *string {
+	return v.ClientMutationId
+}
+
 func GetChecksAndStatus(
 	ctx context.Context,
 	name string,
@@ -21762,6 +23712,45 @@ query PullRequestsList ($name: String!, $owner: String!, $number: Int!) {
 	return &data, err
 }
 
+func addLabelsToPullRequest(
+	ctx context.Context,
+	id string,
+	labels []string,
+) (*addLabelsToPullRequestResponse, error) {
+	req := &graphql.Request{
+		OpName: "addLabelsToPullRequest",
+		Query: `
+mutation addLabelsToPullRequest ($id: ID!, $labels: [ID!]!) {
+	addLabelsToLabelable(input: {labelableId:$id,labelIds:$labels}) {
+		clientMutationId
+	}
+}
+`,
+		Variables: &__addLabelsToPullRequestInput{
+			Id:     id,
+			Labels: labels,
+		},
+	}
+	var err error
+	var client graphql.Client
+
+	client, err = gh_utils.GetGraphQLClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var data addLabelsToPullRequestResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
 func cancelReview(
 	ctx context.Context,
 	revId string,
@@ -21799,6 +23788,43 @@ mutation cancelReview ($revId: ID!) {
 	return &data, err
 }
 
+func closePullRequest(
+	ctx context.Context,
+	prId string,
+) (*closePullRequestResponse, error) {
+	req := &graphql.Request{
+		OpName: "closePullRequest",
+		Query: `
+mutation closePullRequest ($prId: ID!) {
+	closePullRequest(input: {pullRequestId:$prId}) {
+		clientMutationId
+	}
+}
+`,
+		Variables: &__closePullRequestInput{
+			PrId: prId,
+		},
+	}
+	var err error
+	var client graphql.Client
+
+	client, err = gh_utils.GetGraphQLClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var data closePullRequestResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
 func closeReview(
 	ctx context.Context,
 	revId string,
@@ -21877,6 +23903,43 @@ mutation closeReviewWithEvent ($revId: ID!, $event: PullRequestReviewEvent!, $co
 	return &data, err
 }
 
+func convertPullRequestToDraft(
+	ctx context.Context,
+	prId string,
+) (*convertPullRequestToDraftResponse, error) {
+	req := &graphql.Request{
+		OpName: "convertPullRequestToDraft",
+		Query: `
+mutation convertPullRequestToDraft ($prId: ID!) {
+	convertPullRequestToDraft(input: {pullRequestId:$prId}) {
+		clientMutationId
+	}
+}
+`,
+		Variables: &__convertPullRequestToDraftInput{
+			PrId: prId,
+		},
+	}
+	var err error
+	var client graphql.Client
+
+	client, err = gh_utils.GetGraphQLClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var data convertPullRequestToDraftResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
 func createLabel(
 	ctx context.Context,
 	name string,
@@ -21935,12 +23998,13 @@ func createPullRequest(
 	baseBranch string,
 	title string,
 	description *string,
+	draft *bool,
 ) (*createPullRequestResponse, error) {
 	req := &graphql.Request{
 		OpName: "createPullRequest",
 		Query: `
-mutation createPullRequest ($repoId: ID!, $branchName: String!, $baseBranch: String!, $title: String!, $description: String) {
-	createPullRequest(input: {headRefName:$branchName,baseRefName:$baseBranch,title:$title,body:$description,repositoryId:$repoId}) {
+mutation createPullRequest ($repoId: ID!, $branchName: String!, $baseBranch: String!, $title: String!, $description: String, $draft: Boolean) {
+	createPullRequest(input: {headRefName:$branchName,baseRefName:$baseBranch,title:$title,body:$description,draft:$draft,repositoryId:$repoId}) {
 		clientMutationId
 		pullRequest {
 			... singleStatusPullRequest
@@ -22023,6 +24087,7 @@ fragment CommonCommentInfo on Comment {
 			BaseBranch:  baseBranch,
 			Title:       title,
 			Description: description,
+			Draft:       draft,
 		},
 	}
 	var err error
@@ -22346,15 +24411,143 @@ fragment CommonCommentInfo on Comment {
 		__typename
 		... UserInfo
 	}
-	raw: body
-	bodyText
-	bodyHTML
-	createdAt
+	raw: body
+	bodyText
+	bodyHTML
+	createdAt
+}
+`,
+		Variables: &__editPullRequestReviewersInput{
+			Id:        id,
+			Reviewers: reviewers,
+		},
+	}
+	var err error
+	var client graphql.Client
+
+	client, err = gh_utils.GetGraphQLClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var data editPullRequestReviewersResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+func enablePullRequestAutoMerge(
+	ctx context.Context,
+	prId string,
+	method PullRequestMergeMethod,
+	headline *string,
+	body *string,
+) (*enablePullRequestAutoMergeResponse, error) {
+	req := &graphql.Request{
+		OpName: "enablePullRequestAutoMerge",
+		Query: `
+mutation enablePullRequestAutoMerge ($prId: ID!, $method: PullRequestMergeMethod!, $headline: String, $body: String) {
+	enablePullRequestAutoMerge(input: {pullRequestId:$prId,mergeMethod:$method,commitHeadline:$headline,commitBody:$body}) {
+		clientMutationId
+	}
+}
+`,
+		Variables: &__enablePullRequestAutoMergeInput{
+			PrId:     prId,
+			Method:   method,
+			Headline: headline,
+			Body:     body,
+		},
+	}
+	var err error
+	var client graphql.Client
+
+	client, err = gh_utils.GetGraphQLClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var data enablePullRequestAutoMergeResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+func getLabelByName(
+	ctx context.Context,
+	label string,
+	owner string,
+	repo string,
+) (*getLabelByNameResponse, error) {
+	req := &graphql.Request{
+		OpName: "getLabelByName",
+		Query: `
+query getLabelByName ($label: String!, $owner: String!, $repo: String!) {
+	repository(owner: $owner, name: $repo) {
+		label(name: $label) {
+			... LabelInfo
+		}
+	}
+}
+fragment LabelInfo on Label {
+	id
+	color
+	description
+}
+`,
+		Variables: &__getLabelByNameInput{
+			Label: label,
+			Owner: owner,
+			Repo:  repo,
+		},
+	}
+	var err error
+	var client graphql.Client
+
+	client, err = gh_utils.GetGraphQLClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var data getLabelByNameResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+func getUserIdByLogin(
+	ctx context.Context,
+	login string,
+) (*getUserIdByLoginResponse, error) {
+	req := &graphql.Request{
+		OpName: "getUserIdByLogin",
+		Query: `
+query getUserIdByLogin ($login: String!) {
+	user(login: $login) {
+		id
+	}
 }
 `,
-		Variables: &__editPullRequestReviewersInput{
-			Id:        id,
-			Reviewers: reviewers,
+		Variables: &__getUserIdByLoginInput{
+			Login: login,
 		},
 	}
 	var err error
@@ -22365,7 +24558,7 @@ fragment CommonCommentInfo on Comment {
 		return nil, err
 	}
 
-	var data editPullRequestReviewersResponse
+	var data getUserIdByLoginResponse
 	resp := &graphql.Response{Data: &data}
 
 	err = client.MakeRequest(
@@ -22377,32 +24570,23 @@ fragment CommonCommentInfo on Comment {
 	return &data, err
 }
 
-func getLabelByName(
+func markFileAsViewed(
 	ctx context.Context,
-	label string,
-	owner string,
-	repo string,
-) (*getLabelByNameResponse, error) {
+	prId string,
+	path string,
+) (*markFileAsViewedResponse, error) {
 	req := &graphql.Request{
-		OpName: "getLabelByName",
+		OpName: "markFileAsViewed",
 		Query: `
-query getLabelByName ($label: String!, $owner: String!, $repo: String!) {
-	repository(owner: $owner, name: $repo) {
-		label(name: $label) {
-			... LabelInfo
-		}
+mutation markFileAsViewed ($prId: ID!, $path: String!) {
+	markFileAsViewed(input: {pullRequestId:$prId,path:$path}) {
+		clientMutationId
 	}
 }
-fragment LabelInfo on Label {
-	id
-	color
-	description
-}
 `,
-		Variables: &__getLabelByNameInput{
-			Label: label,
-			Owner: owner,
-			Repo:  repo,
+		Variables: &__markFileAsViewedInput{
+			PrId: prId,
+			Path: path,
 		},
 	}
 	var err error
@@ -22413,7 +24597,7 @@ fragment LabelInfo on Label {
 		return nil, err
 	}
 
-	var data getLabelByNameResponse
+	var data markFileAsViewedResponse
 	resp := &graphql.Response{Data: &data}
 
 	err = client.MakeRequest(
@@ -22425,21 +24609,21 @@ fragment LabelInfo on Label {
 	return &data, err
 }
 
-func getUserIdByLogin(
+func markPullRequestReadyForReview(
 	ctx context.Context,
-	login string,
-) (*getUserIdByLoginResponse, error) {
+	prId string,
+) (*markPullRequestReadyForReviewResponse, error) {
 	req := &graphql.Request{
-		OpName: "getUserIdByLogin",
+		OpName: "markPullRequestReadyForReview",
 		Query: `
-query getUserIdByLogin ($login: String!) {
-	user(login: $login) {
-		id
+mutation markPullRequestReadyForReview ($prId: ID!) {
+	markPullRequestReadyForReview(input: {pullRequestId:$prId}) {
+		clientMutationId
 	}
 }
 `,
-		Variables: &__getUserIdByLoginInput{
-			Login: login,
+		Variables: &__markPullRequestReadyForReviewInput{
+			PrId: prId,
 		},
 	}
 	var err error
@@ -22450,7 +24634,7 @@ query getUserIdByLogin ($login: String!) {
 		return nil, err
 	}
 
-	var data getUserIdByLoginResponse
+	var data markPullRequestReadyForReviewResponse
 	resp := &graphql.Response{Data: &data}
 
 	err = client.MakeRequest(
@@ -22465,18 +24649,26 @@ query getUserIdByLogin ($login: String!) {
 func mergePullRequest(
 	ctx context.Context,
 	prId string,
+	method PullRequestMergeMethod,
+	headline *string,
+	body *string,
+	headOid *string,
 ) (*mergePullRequestResponse, error) {
 	req := &graphql.Request{
 		OpName: "mergePullRequest",
 		Query: `
-mutation mergePullRequest ($prId: ID!) {
-	mergePullRequest(input: {pullRequestId:$prId,mergeMethod:SQUASH}) {
+mutation mergePullRequest ($prId: ID!, $method: PullRequestMergeMethod!, $headline: String, $body: String, $headOid: GitObjectID) {
+	mergePullRequest(input: {pullRequestId:$prId,mergeMethod:$method,commitHeadline:$headline,commitBody:$body,expectedHeadOid:$headOid}) {
 		clientMutationId
 	}
 }
 `,
 		Variables: &__mergePullRequestInput{
-			PrId: prId,
+			PrId:     prId,
+			Method:   method,
+			Headline: headline,
+			Body:     body,
+			HeadOid:  headOid,
 		},
 	}
 	var err error
@@ -22676,6 +24868,93 @@ fragment UserInfo on Actor {
 	return &data, err
 }
 
+func pullRequestSearch(
+	ctx context.Context,
+	prQuery string,
+	after *string,
+) (*pullRequestSearchResponse, error) {
+	req := &graphql.Request{
+		OpName: "pullRequestSearch",
+		Query: `
+query pullRequestSearch ($prQuery: String!, $after: String) {
+	search(query: $prQuery, type: ISSUE, first: 100, after: $after) {
+		pageInfo {
+			... NextPageInfo
+		}
+		nodes {
+			__typename
+			... on PullRequest {
+				... listedPullRequest
+			}
+		}
+	}
+}
+fragment NextPageInfo on PageInfo {
+	hasNextPage
+	endCursor
+}
+fragment listedPullRequest on PullRequest {
+	id
+	databaseId
+	number
+	title
+	body
+	state
+	isDraft
+	url
+	createdAt
+	updatedAt
+	closedAt
+	mergedAt
+	author {
+		__typename
+		login
+	}
+	baseRefName
+	baseRefOid
+	baseRepository {
+		... listedRepository
+	}
+	headRefName
+	headRefOid
+	headRepository {
+		... listedRepository
+	}
+}
+fragment listedRepository on Repository {
+	name
+	nameWithOwner
+	owner {
+		__typename
+		login
+	}
+}
+`,
+		Variables: &__pullRequestSearchInput{
+			PrQuery: prQuery,
+			After:   after,
+		},
+	}
+	var err error
+	var client graphql.Client
+
+	client, err = gh_utils.GetGraphQLClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var data pullRequestSearchResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
 func pullRequestThreads(
 	ctx context.Context,
 	number int,
@@ -22780,6 +25059,136 @@ fragment UserInfo on Actor {
 	return &data, err
 }
 
+func pullRequestViewedFiles(
+	ctx context.Context,
+	name string,
+	owner string,
+	number int,
+	after *string,
+) (*pullRequestViewedFilesResponse, error) {
+	req := &graphql.Request{
+		OpName: "pullRequestViewedFiles",
+		Query: `
+query pullRequestViewedFiles ($name: String!, $owner: String!, $number: Int!, $after: String) {
+	repository(name: $name, owner: $owner) {
+		pullRequest(number: $number) {
+			files(first: 100, after: $after) {
+				nodes {
+					path
+					viewerViewedState
+				}
+				pageInfo {
+					hasNextPage
+					endCursor
+				}
+			}
+		}
+	}
+}
+`,
+		Variables: &__pullRequestViewedFilesInput{
+			Name:   name,
+			Owner:  owner,
+			Number: number,
+			After:  after,
+		},
+	}
+	var err error
+	var client graphql.Client
+
+	client, err = gh_utils.GetGraphQLClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var data pullRequestViewedFilesResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+func removeLabelsFromPullRequest(
+	ctx context.Context,
+	id string,
+	labels []string,
+) (*removeLabelsFromPullRequestResponse, error) {
+	req := &graphql.Request{
+		OpName: "removeLabelsFromPullRequest",
+		Query: `
+mutation removeLabelsFromPullRequest ($id: ID!, $labels: [ID!]!) {
+	removeLabelsFromLabelable(input: {labelableId:$id,labelIds:$labels}) {
+		clientMutationId
+	}
+}
+`,
+		Variables: &__removeLabelsFromPullRequestInput{
+			Id:     id,
+			Labels: labels,
+		},
+	}
+	var err error
+	var client graphql.Client
+
+	client, err = gh_utils.GetGraphQLClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var data removeLabelsFromPullRequestResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
+func reopenPullRequest(
+	ctx context.Context,
+	prId string,
+) (*reopenPullRequestResponse, error) {
+	req := &graphql.Request{
+		OpName: "reopenPullRequest",
+		Query: `
+mutation reopenPullRequest ($prId: ID!) {
+	reopenPullRequest(input: {pullRequestId:$prId}) {
+		clientMutationId
+	}
+}
+`,
+		Variables: &__reopenPullRequestInput{
+			PrId: prId,
+		},
+	}
+	var err error
+	var client graphql.Client
+
+	client, err = gh_utils.GetGraphQLClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var data reopenPullRequestResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
+
 func replyTo(
 	ctx context.Context,
 	revId string,
@@ -23079,3 +25488,42 @@ fragment CommonCommentInfo on Comment {
 
 	return &data, err
 }
+
+func unmarkFileAsViewed(
+	ctx context.Context,
+	prId string,
+	path string,
+) (*unmarkFileAsViewedResponse, error) {
+	req := &graphql.Request{
+		OpName: "unmarkFileAsViewed",
+		Query: `
+mutation unmarkFileAsViewed ($prId: ID!, $path: String!) {
+	unmarkFileAsViewed(input: {pullRequestId:$prId,path:$path}) {
+		clientMutationId
+	}
+}
+`,
+		Variables: &__unmarkFileAsViewedInput{
+			PrId: prId,
+			Path: path,
+		},
+	}
+	var err error
+	var client graphql.Client
+
+	client, err = gh_utils.GetGraphQLClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var data unmarkFileAsViewedResponse
+	resp := &graphql.Response{Data: &data}
+
+	err = client.MakeRequest(
+		ctx,
+		req,
+		resp,
+	)
+
+	return &data, err
+}
@@ -0,0 +1,88 @@
+package sv_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/vballestra/sv/sv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// githubSearchPage is a page of the pull request search, with the branches of the pull requests
+func githubSearchPage(numbers []int, next string) string {
+	nodes := make([]string, 0, len(numbers))
+	for _, n := range numbers {
+		nodes = append(nodes, fmt.Sprintf(`{"__typename": "PullRequest", "id": "PR_%d", "databaseId": %d, "number": %d,
+			"title": "PR %d", "body": "", "state": "OPEN", "isDraft": false, "url": "https://github.com/me/repo/pull/%d",
+			"createdAt": "2022-05-01T10:00:00Z", "updatedAt": "2022-05-01T10:00:00Z",
+			"author": {"__typename": "User", "login": "alice"},
+			"baseRefName": "main", "baseRefOid": "base-sha",
+			"baseRepository": {"name": "repo", "nameWithOwner": "me/repo", "owner": {"__typename": "User", "login": "me"}},
+			"headRefName": "feature-%d", "headRefOid": "head-sha", "headRepository": null}`, n, n, n, n, n, n))
+	}
+	return fmt.Sprintf(`{"data": {"search": {"pageInfo": {"hasNextPage": %t, "endCursor": %q}, "nodes": [%s]}}}`,
+		next != "", next, strings.Join(nodes, ","))
+}
+
+func TestGitHubSearchPullRequests(t *testing.T) {
+	queries := 0
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/api/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				PrQuery string  `json:"prQuery"`
+				After   *string `json:"after"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(req.Variables.PrQuery, "author:alice") {
+			t.Errorf("query = %s", req.Variables.PrQuery)
+		}
+		queries++
+		if req.Variables.After == nil {
+			fmt.Fprint(w, githubSearchPage([]int{1, 2}, "cursor"))
+		} else {
+			fmt.Fprint(w, githubSearchPage([]int{3}, ""))
+		}
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL.Path)
+		http.NotFound(w, r)
+	})
+
+	provider := sv.NewGitHubSv("token", srv.URL+"/api/v3/", srv.URL+"/api/graphql", t.TempDir(), ".*", "me", "repo")
+	ch, err := provider.ListPullRequests(sv.PullRequestFilter{Author: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed := make([]string, 0)
+	for pr := range ch {
+		listed = append(listed, fmt.Sprintf("%v %s %s->%s %s", pr.GetId(), pr.GetAuthor().GetDisplayName(),
+			pr.GetBranch().GetName(), pr.GetBase().GetName(), pr.GetState()))
+	}
+	want := []string{"1 alice feature-1->main open", "2 alice feature-2->main open", "3 alice feature-3->main open"}
+	if strings.Join(listed, "\n") != strings.Join(want, "\n") {
+		t.Errorf("listed %v, want %v", listed, want)
+	}
+	if queries != 2 {
+		t.Errorf("%d queries for 2 pages", queries)
+	}
+
+	ch, err = provider.ListPullRequests(sv.PullRequestFilter{Author: "alice", MaxPages: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for range ch {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("%d pull requests on the first page", count)
+	}
+}
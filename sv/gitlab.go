@@ -0,0 +1,1143 @@
+package sv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/antihax/optional"
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
+	"github.com/pterm/pterm"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitLabApiUrl returns the REST endpoint of a GitLab instance
+func GitLabApiUrl(host string) string {
+	return fmt.Sprintf("https://%s/api/v4", host)
+}
+
+type GitLabSv struct {
+	ctx            context.Context
+	client         *restClient
+	project        string
+	localRepo      string
+	sshKeySelector *regexp.Regexp
+
+	pendingReviews map[int]*GitLabPendingReview
+}
+
+// NewGitLabSv creates a GitLab provider for the project (the full path, subgroups included) using the
+// REST api at apiUrl. The http client can be replaced, for instance to talk with the fake server of the gitlabfake
+// package.
+func NewGitLabSv(token string, apiUrl string, httpClient *http.Client, repo string, sshKeyComment string, project string) Sv {
+	if re, err := regexp.Compile(sshKeyComment); err == nil {
+		return &GitLabSv{
+			ctx:            context.Background(),
+			client:         newRestClient(apiUrl, "PRIVATE-TOKEN", token, httpClient),
+			project:        project,
+			localRepo:      repo,
+			sshKeySelector: re,
+			pendingReviews: make(map[int]*GitLabPendingReview),
+		}
+	} else {
+		pterm.Fatal.Println("Error while compiling selector re '", sshKeyComment, "'", err)
+		panic(err)
+	}
+}
+
+// GitLabLogin returns the username of the token owner
+func GitLabLogin(token string, apiUrl string) (string, error) {
+	if user, err := NewGitLabSv(token, apiUrl, nil, "", ".*", "").(*GitLabSv).currentUser(); err != nil {
+		return "", err
+	} else {
+		return user.Username, nil
+	}
+}
+
+// gitLabPages iterates over all the pages of a list, following the X-Next-Page header
+func gitLabPages[T any](ctx context.Context, c *restClient, path string, query url.Values) <-chan itemOrError[T] {
+	return gitLabPagesMax[T](ctx, c, path, query, 0)
+}
+
+// gitLabPagesMax stops after maxPages pages, 0 for no limit
+func gitLabPagesMax[T any](ctx context.Context, c *restClient, path string, query url.Values, maxPages int) <-chan itemOrError[T] {
+	ch := make(chan itemOrError[T])
+
+	go func() {
+		q := url.Values{}
+		for k, v := range query {
+			q[k] = v
+		}
+		q.Set("per_page", "100")
+
+		for page, count := "1", 0; page != "" && (maxPages == 0 || count < maxPages); count++ {
+			q.Set("page", page)
+			items := make([]T, 0)
+			if resp, err := c.get(ctx, path, q, &items); err != nil {
+				ch <- itemOrError[T]{err: err}
+				break
+			} else {
+				for _, itm := range items {
+					ch <- itemOrError[T]{item: itm}
+				}
+				page = resp.Header.Get("X-Next-Page")
+			}
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+func gitLabList[T any](ctx context.Context, c *restClient, path string, query url.Values) ([]T, error) {
+	return collectItems(gitLabPages[T](ctx, c, path, query))
+}
+
+func (g *GitLabSv) projectPath(format string, args ...any) string {
+	return "/projects/" + url.PathEscape(g.project) + fmt.Sprintf(format, args...)
+}
+
+type gitLabUser struct {
+	Id       int    `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+func (u *gitLabUser) GetDisplayName() string {
+	if u == nil {
+		return ""
+	}
+	return u.Username
+}
+
+type gitLabLabel struct {
+	Name string `json:"name"`
+}
+
+type gitLabDiffRefs struct {
+	BaseSha  string `json:"base_sha"`
+	HeadSha  string `json:"head_sha"`
+	StartSha string `json:"start_sha"`
+}
+
+type gitLabPipeline struct {
+	Id     int    `json:"id"`
+	Status string `json:"status"`
+	WebUrl string `json:"web_url"`
+}
+
+type gitLabMergeRequest struct {
+	Id        int `json:"id"`
+	Iid       int `json:"iid"`
+	ProjectId int `json:"project_id"`
+	// SourceProjectId differs from ProjectId for the merge requests of a fork
+	SourceProjectId int             `json:"source_project_id"`
+	Title           string          `json:"title"`
+	Description     string          `json:"description"`
+	State           string          `json:"state"`
+	Draft           bool            `json:"draft"`
+	CreatedAt       time.Time       `json:"created_at"`
+	Author          *gitLabUser     `json:"author"`
+	SourceBranch    string          `json:"source_branch"`
+	TargetBranch    string          `json:"target_branch"`
+	Sha             string          `json:"sha"`
+	WebUrl          string          `json:"web_url"`
+	DiffRefs        *gitLabDiffRefs `json:"diff_refs"`
+	HeadPipeline    *gitLabPipeline `json:"head_pipeline"`
+	Labels          []string        `json:"labels"`
+	Reviewers       []*gitLabUser   `json:"reviewers"`
+	Assignees       []*gitLabUser   `json:"assignees"`
+	References      struct {
+		Full string `json:"full"`
+	} `json:"references"`
+}
+
+// projectFullName extracts the project path from the full reference (e.g. "group/project!12")
+func (m *gitLabMergeRequest) projectFullName() string {
+	if idx := strings.LastIndex(m.References.Full, "!"); idx > 0 {
+		return m.References.Full[:idx]
+	}
+	return m.References.Full
+}
+
+func (g *GitLabSv) currentUser() (*gitLabUser, error) {
+	user := &gitLabUser{}
+	if _, err := g.client.get(g.ctx, "/user", nil, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+var gitLabStates = map[string]string{
+	"":       "opened",
+	"open":   "opened",
+	"closed": "closed",
+	"merged": "merged",
+	"all":    "all",
+}
+
+// gitLabUsername resolves @me, the api has no alias for the current user
+func (g *GitLabSv) gitLabUsername(login string) (string, error) {
+	if login != CurrentUser {
+		return login, nil
+	}
+	if user, err := g.currentUser(); err != nil {
+		return "", err
+	} else {
+		return user.Username, nil
+	}
+}
+
+func (g *GitLabSv) ListPullRequests(filter PullRequestFilter) (<-chan PullRequest, error) {
+	state, ok := gitLabStates[filter.State]
+	if !ok {
+		return nil, fmt.Errorf("unknown state '%s'", filter.State)
+	}
+	q := url.Values{"state": {state}}
+	if len(filter.Query) > 0 {
+		q.Set("search", filter.Query)
+	}
+	if filter.Author != "" {
+		if author, err := g.gitLabUsername(filter.Author); err != nil {
+			return nil, err
+		} else {
+			q.Set("author_username", author)
+		}
+	}
+	if filter.Reviewer != "" {
+		if reviewer, err := g.gitLabUsername(filter.Reviewer); err != nil {
+			return nil, err
+		} else {
+			q.Set("reviewer_username", reviewer)
+		}
+	}
+	if filter.Label != "" {
+		q.Set("labels", filter.Label)
+	}
+	if filter.Base != "" {
+		q.Set("target_branch", filter.Base)
+	}
+	if filter.Head != "" {
+		q.Set("source_branch", filter.Head)
+	}
+	if filter.Draft.IsSet() {
+		if filter.Draft.Value() {
+			q.Set("wip", "yes")
+		} else {
+			q.Set("wip", "no")
+		}
+	}
+	if !filter.UpdatedSince.IsZero() {
+		q.Set("updated_after", filter.UpdatedSince.Format(time.RFC3339))
+	}
+
+	// Check the project is readable first, to report errors synchronously
+	if _, err := g.client.get(g.ctx, g.projectPath(""), nil, nil); err != nil {
+		return nil, err
+	}
+
+	res := make(chan PullRequest)
+	go func() {
+		for mr := range gitLabPagesMax[gitLabMergeRequest](g.ctx, g.client, g.projectPath("/merge_requests"), q, filter.MaxPages) {
+			if mr.err != nil {
+				pterm.Debug.Println(mr.err)
+				break
+			}
+			m := mr.item
+			res <- GitLabMergeRequest{&m, g}
+		}
+		close(res)
+	}()
+
+	return res, nil
+}
+
+func (g *GitLabSv) GetPullRequest(id string) (PullRequest, error) {
+	iid, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	mr := &gitLabMergeRequest{}
+	if _, err := g.client.get(g.ctx, g.projectPath("/merge_requests/%d", iid), nil, mr); err != nil {
+		return nil, err
+	}
+
+	return GitLabMergeRequest{mr, g}, nil
+}
+
+func (g *GitLabSv) PullRequestStatus() (<-chan PullRequestStatus, error) {
+	user, err := g.currentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan PullRequestStatus)
+	go func() {
+		// Get My PRs
+		for mr := range gitLabPages[gitLabMergeRequest](g.ctx, g.client, "/merge_requests", url.Values{"state": {"opened"}, "scope": {"created_by_me"}}) {
+			if mr.err != nil {
+				pterm.Debug.Printfln("cannot read authored merge requests: %v", mr.err)
+				break
+			}
+			ch <- g.toPullRequestStatus(mr.item, true)
+		}
+
+		// Now get reviews as well
+		for mr := range gitLabPages[gitLabMergeRequest](g.ctx, g.client, "/merge_requests", url.Values{"state": {"opened"}, "scope": {"all"}, "reviewer_username": {user.Username}}) {
+			if mr.err != nil {
+				pterm.Debug.Printfln("cannot read merge requests to review: %v", mr.err)
+				break
+			}
+			ch <- g.toPullRequestStatus(mr.item, false)
+		}
+
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+// toPullRequestStatus loads approvals and head pipeline jobs, which aren't returned by the lists
+func (g *GitLabSv) toPullRequestStatus(mr gitLabMergeRequest, isMine bool) PullRequestStatus {
+	base := fmt.Sprintf("/projects/%d/merge_requests/%d", mr.ProjectId, mr.Iid)
+
+	full := mr
+	if _, err := g.client.get(g.ctx, base, nil, &full); err != nil {
+		pterm.Debug.Printfln("cannot read merge request %d details: %v", mr.Iid, err)
+		full = mr
+	}
+
+	approvals := &gitLabApprovals{}
+	if _, err := g.client.get(g.ctx, base+"/approvals", nil, approvals); err != nil {
+		pterm.Debug.Printfln("cannot read merge request %d approvals: %v", mr.Iid, err)
+	}
+
+	jobs := make([]gitLabJob, 0)
+	if full.HeadPipeline != nil {
+		if res, err := gitLabList[gitLabJob](g.ctx, g.client, fmt.Sprintf("/projects/%d/pipelines/%d/jobs", mr.ProjectId, full.HeadPipeline.Id), nil); err == nil {
+			jobs = res
+		} else {
+			pterm.Debug.Printfln("cannot read merge request %d jobs: %v", mr.Iid, err)
+		}
+	}
+
+	return GitLabMergeRequestStatus{&full, approvals, jobs, isMine}
+}
+
+func (g *GitLabSv) wrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	g.client.wrapTransport(wrap)
+}
+
+func (g *GitLabSv) Fetch(remote string) error {
+	return fetchWithAgent(g.localRepo, remote, g.sshKeySelector)
+}
+
+func (g *GitLabSv) PushBranch(remote string, branch string, force bool) (bool, error) {
+	return pushBranch(g.localRepo, g.sshKeySelector, remote, branch, force)
+}
+
+// CheckoutPullRequest fetches refs/merge-requests/N/head, that works for the forks as well
+func (g *GitLabSv) CheckoutPullRequest(remote string, id string, force bool) (string, error) {
+	pr, err := g.GetPullRequest(id)
+	if err != nil {
+		return "", err
+	}
+	mr := pr.(GitLabMergeRequest)
+
+	head := pullRequestHead{id: id, remote: remote, ref: fmt.Sprintf("refs/heads/%s", mr.SourceBranch), branch: mr.SourceBranch}
+	if mr.SourceProjectId != mr.ProjectId {
+		head.ref, head.branch = fmt.Sprintf("refs/merge-requests/%d/head", mr.Iid), forkBranch(mr.Iid, mr.SourceBranch)
+	}
+	return head.branch, checkoutHead(g.localRepo, g.sshKeySelector, head, force)
+}
+
+func (g *GitLabSv) GetRepositoryFullName() string {
+	return g.project
+}
+
+func (g *GitLabSv) GetCurrentBranch() (string, error) {
+	return getCurrentBranch(g.localRepo)
+}
+
+func (g *GitLabSv) GetDefaultBranch() (string, error) {
+	project := struct {
+		DefaultBranch string `json:"default_branch"`
+	}{}
+	if _, err := g.client.get(g.ctx, g.projectPath(""), nil, &project); err != nil {
+		return "", err
+	}
+	return project.DefaultBranch, nil
+}
+
+func (g *GitLabSv) resolveHeadBranch(headBranch optional.String) (string, error) {
+	if headBranch.IsSet() {
+		return headBranch.Value(), nil
+	} else {
+		return g.GetCurrentBranch()
+	}
+}
+
+func (g *GitLabSv) resolveBaseBranch(baseBranch optional.String) (string, error) {
+	if baseBranch.IsSet() {
+		return baseBranch.Value(), nil
+	} else {
+		return g.GetDefaultBranch()
+	}
+}
+
+func (g *GitLabSv) toUserIds(usernames []string) ([]int, error) {
+	ids := make([]int, 0)
+	for _, username := range usernames {
+		users := make([]gitLabUser, 0)
+		if _, err := g.client.get(g.ctx, "/users", url.Values{"username": {username}}, &users); err != nil {
+			return nil, err
+		} else if len(users) != 1 {
+			return nil, fmt.Errorf("cannot find user '%s'", username)
+		} else {
+			ids = append(ids, users[0].Id)
+		}
+	}
+	return ids, nil
+}
+
+// editUserIds is the ids of the current users, with the added ones and without the removed ones
+func (g *GitLabSv) editUserIds(current []*gitLabUser, add []string, remove []string) ([]int, error) {
+	skip := make(map[string]bool)
+	for _, username := range remove {
+		skip[username] = true
+	}
+	ids := make([]int, 0)
+	for _, u := range current {
+		if !skip[u.Username] {
+			skip[u.Username] = true
+			ids = append(ids, u.Id)
+		}
+	}
+	missing := make([]string, 0)
+	for _, username := range add {
+		if !skip[username] {
+			skip[username] = true
+			missing = append(missing, username)
+		}
+	}
+	added, err := g.toUserIds(missing)
+	return append(ids, added...), err
+}
+
+func (g *GitLabSv) ResolveTitleAndDescription(remote string, titleOpt optional.String, descriptionOpt optional.String, headBranch string, baseBranch string) (string, string, error) {
+	return resolveTitleAndDescription(g.localRepo, gitLabTemplates, remote, titleOpt, descriptionOpt, headBranch, baseBranch)
+}
+
+// ListCollaborators returns the members of the project, the inherited ones included
+func (g *GitLabSv) ListCollaborators() ([]string, error) {
+	if members, err := gitLabList[gitLabUser](g.ctx, g.client, g.projectPath("/members/all"), nil); err != nil {
+		return nil, err
+	} else {
+		logins := make([]string, 0, len(members))
+		for _, m := range members {
+			logins = append(logins, m.Username)
+		}
+		return logins, nil
+	}
+}
+
+func (g *GitLabSv) ListLabels() ([]string, error) {
+	if labels, err := gitLabList[gitLabLabel](g.ctx, g.client, g.projectPath("/labels"), nil); err != nil {
+		return nil, err
+	} else {
+		names := make([]string, 0, len(labels))
+		for _, l := range labels {
+			names = append(names, l.Name)
+		}
+		return names, nil
+	}
+}
+
+func (g *GitLabSv) CreatePullRequest(args CreatePullRequestArgs) (PullRequestStatus, error) {
+	if reviewerIds, err := g.toUserIds(args.Reviewers); err != nil {
+		return nil, err
+	} else if headBranch, err := g.resolveHeadBranch(args.HeadBranch); err != nil {
+		return nil, err
+	} else if baseBranch, err := g.resolveBaseBranch(args.BaseBranch); err != nil {
+		return nil, err
+	} else if title, description, err := g.ResolveTitleAndDescription(args.Remote, args.Title, args.Description, headBranch, baseBranch); err != nil {
+		return nil, err
+	} else {
+		if args.Draft {
+			title = draftTitle(title, true, "Draft: ")
+		}
+		// GitLab creates missing labels on its own
+		mr := gitLabMergeRequest{}
+		if _, err := g.client.post(g.ctx, g.projectPath("/merge_requests"), map[string]interface{}{
+			"source_branch": headBranch,
+			"target_branch": baseBranch,
+			"title":         title,
+			"description":   description,
+			"labels":        strings.Join(args.Labels, ","),
+			"reviewer_ids":  reviewerIds,
+		}, &mr); err != nil {
+			return nil, err
+		}
+
+		return GitLabMergeRequestStatus{&mr, &gitLabApprovals{}, nil, true}, nil
+	}
+}
+
+type GitLabMergeRequest struct {
+	*gitLabMergeRequest
+	sv *GitLabSv
+}
+
+func (g GitLabMergeRequest) path(format string, args ...any) string {
+	return g.sv.projectPath("/merge_requests/%d", g.Iid) + fmt.Sprintf(format, args...)
+}
+
+func (g GitLabMergeRequest) GetBranch() Branch {
+	return GitLabBranch(g.SourceBranch)
+}
+
+func (g GitLabMergeRequest) GetBase() Branch {
+	return GitLabBranch(g.TargetBranch)
+}
+
+type GitLabBranch string
+
+func (g GitLabBranch) GetName() string {
+	return string(g)
+}
+
+func (g GitLabMergeRequest) GetId() interface{} {
+	return g.Iid
+}
+
+func (g GitLabMergeRequest) GetTitle() string {
+	return g.Title
+}
+
+func (g GitLabMergeRequest) GetDescription() string {
+	return g.Description
+}
+
+func (g GitLabMergeRequest) GetUrl() string {
+	return g.WebUrl
+}
+
+func (g GitLabMergeRequest) GetAuthor() Author {
+	return g.Author
+}
+
+func (g GitLabMergeRequest) GetState() string {
+	return g.State
+}
+
+func (g GitLabMergeRequest) GetCreatedOn() time.Time {
+	return g.CreatedAt
+}
+
+type gitLabPosition struct {
+	PositionType string `json:"position_type"`
+	BaseSha      string `json:"base_sha,omitempty"`
+	StartSha     string `json:"start_sha,omitempty"`
+	HeadSha      string `json:"head_sha,omitempty"`
+	OldPath      string `json:"old_path,omitempty"`
+	NewPath      string `json:"new_path,omitempty"`
+	OldLine      *int   `json:"old_line,omitempty"`
+	NewLine      *int   `json:"new_line,omitempty"`
+}
+
+type gitLabNote struct {
+	Id        int             `json:"id"`
+	Body      string          `json:"body"`
+	Author    *gitLabUser     `json:"author"`
+	CreatedAt time.Time       `json:"created_at"`
+	System    bool            `json:"system"`
+	Position  *gitLabPosition `json:"position"`
+}
+
+type gitLabDiscussion struct {
+	Id             string       `json:"id"`
+	IndividualNote bool         `json:"individual_note"`
+	Notes          []gitLabNote `json:"notes"`
+}
+
+func (g GitLabMergeRequest) GetCommentsByLine() ([]Comment, map[string]map[int64][]Comment, error) {
+	discussions, err := gitLabList[gitLabDiscussion](g.sv.ctx, g.sv.client, g.path("/discussions"), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prComments := make([]Comment, 0)
+	commentMap := make(map[string]map[int64][]Comment)
+
+	for _, d := range discussions {
+		var parent *gitLabNote
+		for n := range d.Notes {
+			note := d.Notes[n]
+			if note.System {
+				continue
+			}
+			cmt := GitLabComment{&note, d.Id, parent}
+			if parent == nil {
+				parent = &note
+			}
+
+			if pos := note.Position; pos != nil && pos.PositionType == "text" {
+				// Same convention as GitHub : lines on the new side are negative
+				var line int64
+				path := pos.NewPath
+				if pos.NewLine != nil {
+					line = -int64(*pos.NewLine)
+				} else if pos.OldLine != nil {
+					line = int64(*pos.OldLine)
+					path = pos.OldPath
+				} else {
+					continue
+				}
+
+				byLine, ok := commentMap[path]
+				if !ok {
+					byLine = make(map[int64][]Comment)
+					commentMap[path] = byLine
+				}
+				byLine[line] = append(byLine[line], cmt)
+			} else {
+				prComments = append(prComments, cmt)
+			}
+		}
+	}
+
+	return prComments, commentMap, nil
+}
+
+type GitLabComment struct {
+	*gitLabNote
+	discussionId string
+	parent       *gitLabNote
+}
+
+func (g GitLabComment) GetContent() CommentContent {
+	return g
+}
+
+func (g GitLabComment) GetRaw() string {
+	return g.Body
+}
+
+func (g GitLabComment) GetParentId() interface{} {
+	if g.parent != nil {
+		return g.parent.Id
+	}
+	return nil
+}
+
+func (g GitLabComment) GetId() interface{} {
+	return g.Id
+}
+
+func (g GitLabComment) GetUser() Author {
+	return g.Author
+}
+
+func (g GitLabComment) GetCreatedOn() time.Time {
+	return g.CreatedAt
+}
+
+func (g GitLabComment) GetReactions() Reactions {
+	return make(Reactions)
+}
+
+func (g GitLabMergeRequest) GetDiff() ([]*gitdiff.File, error) {
+	if g.DiffRefs == nil {
+		return nil, fmt.Errorf("merge request %d has no diff refs", g.Iid)
+	}
+	return diffFromLocalRepo(g.sv.localRepo, g.DiffRefs.BaseSha, g.DiffRefs.HeadSha)
+}
+
+func (g GitLabMergeRequest) GetFileContent(path string, isNew bool) (string, error) {
+	if g.DiffRefs == nil {
+		return "", fmt.Errorf("merge request %d has no diff refs", g.Iid)
+	}
+	return fileFromLocalRepo(g.sv.localRepo, g.DiffRefs.BaseSha, g.DiffRefs.HeadSha, path, isNew)
+}
+
+type gitLabJob struct {
+	Id         int        `json:"id"`
+	Name       string     `json:"name"`
+	Stage      string     `json:"stage"`
+	Status     string     `json:"status"`
+	WebUrl     string     `json:"web_url"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+type GitLabCheck struct {
+	gitLabJob
+}
+
+func (g GitLabCheck) GetName() string {
+	return fmt.Sprintf("%s/%s", g.Stage, g.Name)
+}
+
+func (g GitLabCheck) GetStatus() string {
+	return g.Status
+}
+
+func (g GitLabCheck) GetUrl() string {
+	return g.WebUrl
+}
+
+func (g GitLabMergeRequest) GetChecks() ([]Check, error) {
+	result := make([]Check, 0)
+	if g.HeadPipeline == nil {
+		return result, nil
+	}
+
+	jobs, err := gitLabList[gitLabJob](g.sv.ctx, g.sv.client, g.sv.projectPath("/pipelines/%d/jobs", g.HeadPipeline.Id), nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, j := range jobs {
+		result = append(result, GitLabCheck{j})
+	}
+
+	return result, nil
+}
+
+var gitLabJobConclusions = map[string]string{
+	"success":  "SUCCESS",
+	"failed":   "FAILURE",
+	"canceled": "CANCELLED",
+	"skipped":  "SKIPPED",
+}
+
+func (g GitLabCheck) GetConclusion() string {
+	return gitLabJobConclusions[g.Status]
+}
+
+func (g GitLabCheck) GetStartedAt() time.Time {
+	if g.StartedAt == nil {
+		return time.Time{}
+	}
+	return *g.StartedAt
+}
+
+func (g GitLabCheck) GetCompletedAt() time.Time {
+	if g.FinishedAt == nil {
+		return time.Time{}
+	}
+	return *g.FinishedAt
+}
+
+func (g GitLabCheck) CanRerun() bool {
+	return true
+}
+
+func (g GitLabCheck) HasLog() bool {
+	return true
+}
+
+func (g GitLabMergeRequest) GetCheckRuns() ([]CheckRun, error) {
+	checks, err := g.GetChecks()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]CheckRun, 0, len(checks))
+	for _, c := range checks {
+		result = append(result, c.(GitLabCheck))
+	}
+	return result, nil
+}
+
+// RerunChecks retries the jobs one by one, GitLab creates a new job for each
+func (g GitLabMergeRequest) RerunChecks(checks []CheckRun) error {
+	for _, c := range checks {
+		if job, ok := c.(GitLabCheck); !ok {
+			return fmt.Errorf("illegal argument: not a gitlab job")
+		} else if _, err := g.sv.client.post(g.sv.ctx, g.sv.projectPath("/jobs/%d/retry", job.Id), nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g GitLabMergeRequest) GetCheckLog(check CheckRun) ([]byte, error) {
+	if job, ok := check.(GitLabCheck); !ok {
+		return nil, fmt.Errorf("illegal argument: not a gitlab job")
+	} else {
+		return g.sv.client.getRaw(g.sv.ctx, g.sv.projectPath("/jobs/%d/trace", job.Id))
+	}
+}
+
+type gitLabApprovals struct {
+	ApprovedBy []struct {
+		User *gitLabUser `json:"user"`
+	} `json:"approved_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (a *gitLabApprovals) toReviews() []Review {
+	result := make([]Review, 0)
+	for _, ab := range a.ApprovedBy {
+		result = append(result, GitLabReview{ab.User, "APPROVED", a.UpdatedAt})
+	}
+	return result
+}
+
+func (g GitLabMergeRequest) GetReviews() ([]Review, error) {
+	approvals := &gitLabApprovals{}
+	if _, err := g.sv.client.get(g.sv.ctx, g.path("/approvals"), nil, approvals); err != nil {
+		return nil, err
+	}
+	return approvals.toReviews(), nil
+}
+
+func (g GitLabMergeRequest) ReplyToComment(comment Comment, replyText string) (Comment, error) {
+	if c, ok := comment.(GitLabComment); ok {
+		note := &gitLabNote{}
+		if _, err := g.sv.client.post(g.sv.ctx, g.path("/discussions/%s/notes", c.discussionId), map[string]string{"body": replyText}, note); err != nil {
+			return nil, err
+		}
+		return GitLabComment{note, c.discussionId, c.gitLabNote}, nil
+	} else {
+		return nil, fmt.Errorf("illegal argument: not a gitlab comment")
+	}
+}
+
+func (g GitLabMergeRequest) CreateComment(path string, commitId string, line int, isNew bool, body string) (Comment, error) {
+	pos := gitLabPosition{
+		PositionType: "text",
+		OldPath:      path,
+		NewPath:      path,
+		HeadSha:      commitId,
+	}
+	if g.DiffRefs != nil {
+		pos.BaseSha = g.DiffRefs.BaseSha
+		pos.StartSha = g.DiffRefs.StartSha
+	}
+	if isNew {
+		pos.NewLine = &line
+	} else {
+		pos.OldLine = &line
+	}
+
+	d := &gitLabDiscussion{}
+	if _, err := g.sv.client.post(g.sv.ctx, g.path("/discussions"), map[string]interface{}{
+		"body":     body,
+		"position": pos,
+	}, d); err != nil {
+		return nil, err
+	} else if len(d.Notes) == 0 {
+		return nil, errors.New("bad response, the new discussion has no notes")
+	}
+
+	return GitLabComment{&d.Notes[0], d.Id, nil}, nil
+}
+
+func (g GitLabMergeRequest) GetLastCommitId() string {
+	if g.DiffRefs != nil {
+		return g.DiffRefs.HeadSha
+	}
+	return g.Sha
+}
+
+// StartReview opens a local review, just like Bitbucket, to be approved or closed with a comment
+func (g GitLabMergeRequest) StartReview() (Review, error) {
+	if rev, err := g.GetPendingReview(); err != nil || rev != nil {
+		return rev, err
+	}
+
+	user, err := g.sv.currentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	rev := &GitLabPendingReview{mr: g, author: user, createdOn: time.Now()}
+	g.sv.pendingReviews[g.Iid] = rev
+
+	return rev, nil
+}
+
+func (g GitLabMergeRequest) GetPendingReview() (Review, error) {
+	if rev, ok := g.sv.pendingReviews[g.Iid]; ok {
+		return rev, nil
+	}
+	// No error but no reviews
+	return nil, nil
+}
+
+func (g GitLabMergeRequest) Merge(opts MergeOptions) error {
+	params := map[string]interface{}{"sha": g.Sha}
+	switch opts.Method {
+	case "":
+	case "merge", "squash":
+		params["squash"] = opts.Method == "squash"
+	case "rebase":
+		// Whether the merge requests get rebased is a setting of the project
+		return fmt.Errorf("cannot choose to rebase, it's the merge method of the project: %w", ErrNotSupported)
+	default:
+		return fmt.Errorf("unknown merge method %s", opts.Method)
+	}
+	if message := opts.message(); message != "" && opts.Method == "squash" {
+		params["squash_commit_message"] = message
+	} else if message != "" {
+		params["merge_commit_message"] = message
+	}
+	if opts.DeleteBranch {
+		params["should_remove_source_branch"] = true
+	}
+	if opts.Auto {
+		params["merge_when_pipeline_succeeds"] = true
+	}
+	_, err := g.sv.client.put(g.sv.ctx, g.path("/merge"), params, nil)
+	return err
+}
+
+func (g GitLabMergeRequest) Close() error {
+	_, err := g.sv.client.put(g.sv.ctx, g.path(""), map[string]interface{}{"state_event": "close"}, nil)
+	return err
+}
+
+func (g GitLabMergeRequest) Reopen() error {
+	_, err := g.sv.client.put(g.sv.ctx, g.path(""), map[string]interface{}{"state_event": "reopen"}, nil)
+	return err
+}
+
+// Edit updates the merge request, the reviewers and the assignees are rewritten as a whole
+func (g GitLabMergeRequest) Edit(args EditPullRequestArgs) error {
+	params := make(map[string]interface{})
+	if args.Title.IsSet() {
+		params["title"] = args.Title.Value()
+	}
+	if args.Description.IsSet() {
+		params["description"] = args.Description.Value()
+	}
+	if args.BaseBranch.IsSet() {
+		params["target_branch"] = args.BaseBranch.Value()
+	}
+	// GitLab creates missing labels on its own
+	if len(args.AddLabels) > 0 {
+		params["add_labels"] = strings.Join(args.AddLabels, ",")
+	}
+	if len(args.RemoveLabels) > 0 {
+		params["remove_labels"] = strings.Join(args.RemoveLabels, ",")
+	}
+	if len(args.AddReviewers) > 0 || len(args.RemoveReviewers) > 0 {
+		if ids, err := g.sv.editUserIds(g.Reviewers, args.AddReviewers, args.RemoveReviewers); err != nil {
+			return err
+		} else {
+			params["reviewer_ids"] = ids
+		}
+	}
+	if len(args.AddAssignees) > 0 || len(args.RemoveAssignees) > 0 {
+		if ids, err := g.sv.editUserIds(g.Assignees, args.AddAssignees, args.RemoveAssignees); err != nil {
+			return err
+		} else {
+			params["assignee_ids"] = ids
+		}
+	}
+	if len(params) == 0 {
+		return nil
+	}
+	_, err := g.sv.client.put(g.sv.ctx, g.path(""), params, nil)
+	return err
+}
+
+func (g GitLabMergeRequest) IsDraft() bool {
+	return g.Draft
+}
+
+// SetDraft changes the title, GitLab tells the drafts by their prefix
+func (g GitLabMergeRequest) SetDraft(draft bool) error {
+	_, err := g.sv.client.put(g.sv.ctx, g.path(""), map[string]interface{}{"title": draftTitle(g.Title, draft, "Draft: ")}, nil)
+	return err
+}
+
+type GitLabReview struct {
+	author      *gitLabUser
+	state       string
+	submittedAt time.Time
+}
+
+func (g GitLabReview) GetId() string {
+	return ""
+}
+
+func (g GitLabReview) GetState() string {
+	return g.state
+}
+
+func (g GitLabReview) GetAuthor() string {
+	return g.author.GetDisplayName()
+}
+
+func (g GitLabReview) GetSubmitedAt() time.Time {
+	return g.submittedAt
+}
+
+func (g GitLabReview) Dismiss() error {
+	return errors.New("cannot operate on other's reviews")
+}
+
+func (g GitLabReview) Close(comment *string) error {
+	return errors.New("cannot operate on other's reviews")
+}
+
+func (g GitLabReview) Approve(comment *string) error {
+	return errors.New("cannot operate on other's reviews")
+}
+
+func (g GitLabReview) RequestChanges(comment *string) error {
+	return errors.New("cannot operate on other's reviews")
+}
+
+func (g GitLabReview) Cancel() error {
+	return errors.New("cannot operate on other's reviews")
+}
+
+type GitLabPendingReview struct {
+	mr        GitLabMergeRequest
+	author    *gitLabUser
+	createdOn time.Time
+}
+
+func (g *GitLabPendingReview) GetId() string {
+	return fmt.Sprintf("%d", g.mr.Iid)
+}
+
+func (g *GitLabPendingReview) GetState() string {
+	return "PENDING"
+}
+
+func (g *GitLabPendingReview) GetAuthor() string {
+	return g.author.GetDisplayName()
+}
+
+func (g *GitLabPendingReview) GetSubmitedAt() time.Time {
+	return g.createdOn
+}
+
+func (g *GitLabPendingReview) addComment(comment *string) error {
+	if comment == nil || strings.TrimSpace(*comment) == "" {
+		return nil
+	}
+	_, err := g.mr.sv.client.post(g.mr.sv.ctx, g.mr.path("/notes"), map[string]string{"body": *comment}, nil)
+	return err
+}
+
+func (g *GitLabPendingReview) Dismiss() error {
+	if _, err := g.mr.sv.client.post(g.mr.sv.ctx, g.mr.path("/unapprove"), nil, nil); err != nil {
+		return err
+	}
+	return g.Cancel()
+}
+
+func (g *GitLabPendingReview) Close(comment *string) error {
+	if err := g.addComment(comment); err != nil {
+		return err
+	}
+	return g.Cancel()
+}
+
+func (g *GitLabPendingReview) Approve(comment *string) error {
+	if err := g.addComment(comment); err != nil {
+		return err
+	} else if _, err := g.mr.sv.client.post(g.mr.sv.ctx, g.mr.path("/approve"), nil, nil); err != nil {
+		return err
+	}
+	return g.Cancel()
+}
+
+// RequestChanges adds the comment and revokes any previous approval, GitLab has no such review state
+func (g *GitLabPendingReview) RequestChanges(comment *string) error {
+	if err := g.addComment(comment); err != nil {
+		return err
+	} else if _, err := g.mr.sv.client.post(g.mr.sv.ctx, g.mr.path("/unapprove"), nil, nil); err != nil {
+		if e, ok := err.(*ApiError); !ok || e.StatusCode != 404 {
+			return err
+		}
+	}
+	return g.Cancel()
+}
+
+func (g *GitLabPendingReview) Cancel() error {
+	delete(g.mr.sv.pendingReviews, g.mr.Iid)
+	return nil
+}
+
+type GitLabMergeRequestStatus struct {
+	*gitLabMergeRequest
+	approvals *gitLabApprovals
+	jobs      []gitLabJob
+	isMine    bool
+}
+
+func (g GitLabMergeRequestStatus) GetId() interface{} {
+	return g.Iid
+}
+
+func (g GitLabMergeRequestStatus) GetTitle() string {
+	return g.Title
+}
+
+func (g GitLabMergeRequestStatus) GetStatus() string {
+	return g.State
+}
+
+func (g GitLabMergeRequestStatus) GetBranchName() string {
+	return g.SourceBranch
+}
+
+func (g GitLabMergeRequestStatus) GetBaseName() string {
+	return g.TargetBranch
+}
+
+func (g GitLabMergeRequestStatus) GetReviews() []Review {
+	return g.approvals.toReviews()
+}
+
+var gitlabJobStates = map[string]string{
+	"success":              "SUCCESS",
+	"failed":               "FAILURE",
+	"running":              "IN_PROGRESS",
+	"pending":              "QUEUED",
+	"created":              "QUEUED",
+	"waiting_for_resource": "QUEUED",
+	"preparing":            "QUEUED",
+	"canceled":             "CANCELLED",
+	"skipped":              "SKIPPED",
+	"manual":               "ACTION_REQUIRED",
+}
+
+func (g GitLabMergeRequestStatus) GetChecksByStatus() map[string]int {
+	states := make(map[string]int)
+	for _, j := range g.jobs {
+		state, ok := gitlabJobStates[j.Status]
+		if !ok {
+			state = strings.ToUpper(j.Status)
+		}
+		states[state] += 1
+	}
+	return states
+}
+
+// GetContextByStatus is empty: in GitLab every status comes from pipelines
+func (g GitLabMergeRequestStatus) GetContextByStatus() map[string]int {
+	return make(map[string]int)
+}
+
+func (g GitLabMergeRequestStatus) GetAuthor() string {
+	return g.Author.GetDisplayName()
+}
+
+func (g GitLabMergeRequestStatus) GetRepository() string {
+	return g.projectFullName()
+}
+
+func (g GitLabMergeRequestStatus) IsMine() bool {
+	return g.isMine
+}
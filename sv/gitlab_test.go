@@ -0,0 +1,355 @@
+package sv_test
+
+import (
+	"errors"
+	"github.com/antihax/optional"
+	"github.com/vballestra/sv/sv"
+	"github.com/vballestra/sv/sv/gitlabfake"
+	"strings"
+	"testing"
+)
+
+const gitLabProject = "group/sub/project"
+
+func newGitLab(t *testing.T) (*gitlabfake.Server, sv.Sv) {
+	srv := gitlabfake.NewServer("token", gitlabfake.User{Id: 1, Username: "me", Name: "Me"})
+	t.Cleanup(srv.Close)
+	srv.AddUser("", gitlabfake.User{Id: 2, Username: "alice"})
+	srv.AddProject(gitLabProject, "main")
+	srv.AddMember(gitLabProject, "me")
+	srv.AddMember(gitLabProject, "alice")
+	return srv, sv.NewGitLabSv("token", srv.ApiUrl(), srv.Client(), t.TempDir(), ".*", gitLabProject)
+}
+
+func me() *gitlabfake.User {
+	return &gitlabfake.User{Id: 1, Username: "me"}
+}
+
+func alice() *gitlabfake.User {
+	return &gitlabfake.User{Id: 2, Username: "alice"}
+}
+
+func collectPullRequests(t *testing.T, provider sv.Sv, filter sv.PullRequestFilter) []string {
+	ch, err := provider.ListPullRequests(filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := make([]string, 0)
+	for pr := range ch {
+		titles = append(titles, pr.GetTitle())
+	}
+	return titles
+}
+
+func TestGitLabListPullRequests(t *testing.T) {
+	srv, provider := newGitLab(t)
+	srv.AddMergeRequest(gitLabProject, gitlabfake.MergeRequest{Title: "mine", Author: me(), SourceBranch: "a", TargetBranch: "main"})
+	srv.AddMergeRequest(gitLabProject, gitlabfake.MergeRequest{Title: "Draft: hers", Author: alice(), SourceBranch: "b", TargetBranch: "main",
+		Reviewers: []*gitlabfake.User{me()}})
+	srv.AddMergeRequest(gitLabProject, gitlabfake.MergeRequest{Title: "merged", Author: me(), State: "merged", SourceBranch: "c", TargetBranch: "main"})
+
+	for _, test := range []struct {
+		name   string
+		filter sv.PullRequestFilter
+		want   string
+	}{
+		{"open by default", sv.PullRequestFilter{}, "mine,Draft: hers"},
+		{"merged", sv.PullRequestFilter{State: "merged"}, "merged"},
+		{"all", sv.PullRequestFilter{State: "all"}, "mine,Draft: hers,merged"},
+		{"author", sv.PullRequestFilter{Author: sv.CurrentUser}, "mine"},
+		{"reviewer", sv.PullRequestFilter{Reviewer: sv.CurrentUser}, "Draft: hers"},
+		{"drafts", sv.PullRequestFilter{Draft: optional.NewBool(true)}, "Draft: hers"},
+		{"head", sv.PullRequestFilter{State: "all", Head: "c"}, "merged"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := strings.Join(collectPullRequests(t, provider, test.filter), ","); got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+
+	if _, err := provider.ListPullRequests(sv.PullRequestFilter{State: "unknown"}); err == nil {
+		t.Error("an unknown state should be refused")
+	}
+}
+
+func TestGitLabErrors(t *testing.T) {
+	srv, _ := newGitLab(t)
+
+	wrongToken := sv.NewGitLabSv("wrong", srv.ApiUrl(), srv.Client(), t.TempDir(), ".*", gitLabProject)
+	var apiErr *sv.ApiError
+	if _, err := wrongToken.ListPullRequests(sv.PullRequestFilter{}); !errors.As(err, &apiErr) || apiErr.StatusCode != 401 {
+		t.Errorf("expected an authentication error, got %v", err)
+	}
+
+	missing := sv.NewGitLabSv("token", srv.ApiUrl(), srv.Client(), t.TempDir(), ".*", "group/missing")
+	if _, err := missing.ListPullRequests(sv.PullRequestFilter{}); !errors.As(err, &apiErr) || apiErr.StatusCode != 404 {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if _, err := missing.GetPullRequest("1"); err == nil {
+		t.Error("reading a merge request of a missing project should fail")
+	}
+}
+
+func TestGitLabPullRequestStatus(t *testing.T) {
+	srv, provider := newGitLab(t)
+	srv.AddProject("other/project", "main")
+	mine := srv.AddMergeRequest(gitLabProject, gitlabfake.MergeRequest{Title: "mine", Author: me(), SourceBranch: "a", TargetBranch: "main"})
+	srv.AddPipeline(gitLabProject, mine, "",
+		gitlabfake.Job{Name: "build", Stage: "build", Status: "success"},
+		gitlabfake.Job{Name: "test", Stage: "test", Status: "failed"},
+		gitlabfake.Job{Name: "deploy", Stage: "deploy", Status: "manual"})
+	srv.AddMergeRequest("other/project", gitlabfake.MergeRequest{Title: "to review", Author: alice(), SourceBranch: "b",
+		TargetBranch: "main", Reviewers: []*gitlabfake.User{me()}})
+	srv.AddMergeRequest(gitLabProject, gitlabfake.MergeRequest{Title: "not for me", Author: alice(), SourceBranch: "c", TargetBranch: "main"})
+
+	ch, err := provider.PullRequestStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := make([]sv.PullRequestStatus, 0)
+	for st := range ch {
+		statuses = append(statuses, st)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 merge requests, got %d", len(statuses))
+	}
+
+	if st := statuses[0]; st.GetTitle() != "mine" || !st.IsMine() || st.GetRepository() != gitLabProject || st.GetBranchName() != "a" {
+		t.Errorf("unexpected status %s %v %s %s", st.GetTitle(), st.IsMine(), st.GetRepository(), st.GetBranchName())
+	} else if checks := st.GetChecksByStatus(); checks["SUCCESS"] != 1 || checks["FAILURE"] != 1 || checks["ACTION_REQUIRED"] != 1 {
+		t.Errorf("unexpected checks %v", checks)
+	}
+	if st := statuses[1]; st.GetTitle() != "to review" || st.IsMine() || st.GetRepository() != "other/project" || st.GetAuthor() != "alice" {
+		t.Errorf("unexpected status %s %v %s %s", st.GetTitle(), st.IsMine(), st.GetRepository(), st.GetAuthor())
+	}
+}
+
+func TestGitLabCreatePullRequest(t *testing.T) {
+	srv, provider := newGitLab(t)
+
+	st, err := provider.CreatePullRequest(sv.CreatePullRequestArgs{
+		HeadBranch:  optional.NewString("feature"),
+		Title:       optional.NewString("Add the feature"),
+		Description: optional.NewString("Details"),
+		Labels:      []string{"bug", "ui"},
+		Reviewers:   []string{"alice"},
+		Draft:       true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.IsMine() || st.GetBaseName() != "main" {
+		t.Errorf("the default branch should be the base, got %s", st.GetBaseName())
+	}
+
+	mr, ok := srv.MergeRequest(gitLabProject, st.GetId().(int))
+	if !ok {
+		t.Fatal("the merge request wasn't stored")
+	}
+	if mr.Title != "Draft: Add the feature" || !mr.Draft || mr.Description != "Details" || mr.SourceBranch != "feature" {
+		t.Errorf("unexpected merge request %+v", mr)
+	}
+	if strings.Join(mr.Labels, ",") != "bug,ui" || len(mr.Reviewers) != 1 || mr.Reviewers[0].Username != "alice" {
+		t.Errorf("unexpected labels %v or reviewers %v", mr.Labels, mr.Reviewers)
+	}
+	if labels, err := provider.ListLabels(); err != nil || strings.Join(labels, ",") != "bug,ui" {
+		t.Errorf("the labels should have been created, got %v %v", labels, err)
+	}
+
+	if _, err := provider.CreatePullRequest(sv.CreatePullRequestArgs{
+		HeadBranch:  optional.NewString("feature"),
+		Title:       optional.NewString("Again"),
+		Description: optional.NewString(""),
+	}); err == nil {
+		t.Error("a second merge request for the same branches should be refused")
+	}
+	if _, err := provider.CreatePullRequest(sv.CreatePullRequestArgs{
+		HeadBranch:  optional.NewString("other"),
+		Title:       optional.NewString("Unknown reviewer"),
+		Description: optional.NewString(""),
+		Reviewers:   []string{"nobody"},
+	}); err == nil || !strings.Contains(err.Error(), "nobody") {
+		t.Errorf("an unknown reviewer should be reported, got %v", err)
+	}
+}
+
+func TestGitLabComments(t *testing.T) {
+	srv, provider := newGitLab(t)
+	iid := srv.AddMergeRequest(gitLabProject, gitlabfake.MergeRequest{Title: "mr", Author: alice(), SourceBranch: "a",
+		TargetBranch: "main", Sha: "head", DiffRefs: &gitlabfake.DiffRefs{BaseSha: "base", StartSha: "start", HeadSha: "head"}})
+	pr, err := provider.GetPullRequest("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newSide, err := pr.CreateComment("main.go", pr.GetLastCommitId(), 12, true, "on the new side")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pr.CreateComment("main.go", pr.GetLastCommitId(), 3, false, "on the old side"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pr.ReplyToComment(newSide, "a reply"); err != nil {
+		t.Fatal(err)
+	}
+	rev, err := pr.StartReview()
+	if err != nil {
+		t.Fatal(err)
+	}
+	general := "looks good"
+	if err := rev.Close(&general); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := srv.Discussions(gitLabProject, iid); len(d) != 3 {
+		t.Fatalf("expected 3 discussions, got %d", len(d))
+	} else if pos := d[0].Notes[0].Position; pos == nil || pos.BaseSha != "base" || pos.StartSha != "start" || pos.HeadSha != "head" {
+		t.Errorf("the position should have the diff refs, got %+v", pos)
+	}
+
+	prComments, byLine, err := pr.GetCommentsByLine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prComments) != 1 || prComments[0].GetContent().GetRaw() != general {
+		t.Errorf("expected the general comment, got %v", prComments)
+	}
+	thread := byLine["main.go"][-12]
+	if len(thread) != 2 || thread[1].GetContent().GetRaw() != "a reply" || thread[1].GetParentId() != newSide.GetId() {
+		t.Errorf("expected the comment and its reply on the new line 12, got %v", thread)
+	}
+	if old := byLine["main.go"][3]; len(old) != 1 || old[0].GetUser().GetDisplayName() != "me" {
+		t.Errorf("expected the comment on the old line 3, got %v", old)
+	}
+}
+
+func TestGitLabApprovals(t *testing.T) {
+	srv, provider := newGitLab(t)
+	srv.AddMergeRequest(gitLabProject, gitlabfake.MergeRequest{Title: "mr", Author: alice(), SourceBranch: "a", TargetBranch: "main"})
+	pr, err := provider.GetPullRequest("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Requesting changes without a previous approval is fine
+	rev, err := pr.StartReview()
+	if err != nil {
+		t.Fatal(err)
+	} else if err := rev.RequestChanges(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if rev, err = pr.StartReview(); err != nil {
+		t.Fatal(err)
+	} else if pending, _ := pr.GetPendingReview(); pending != rev {
+		t.Error("the started review should be pending")
+	} else if err := rev.Approve(nil); err != nil {
+		t.Fatal(err)
+	} else if pending, _ := pr.GetPendingReview(); pending != nil {
+		t.Error("the approved review shouldn't be pending anymore")
+	}
+	if reviews, err := pr.GetReviews(); err != nil {
+		t.Fatal(err)
+	} else if len(reviews) != 1 || reviews[0].GetAuthor() != "me" || reviews[0].GetState() != "APPROVED" {
+		t.Errorf("expected the approval, got %v", reviews)
+	}
+
+	if rev, err = pr.StartReview(); err != nil {
+		t.Fatal(err)
+	} else if err := rev.RequestChanges(nil); err != nil {
+		t.Fatal(err)
+	}
+	if reviews, err := pr.GetReviews(); err != nil || len(reviews) != 0 {
+		t.Errorf("the approval should be revoked, got %v %v", reviews, err)
+	}
+}
+
+func TestGitLabMerge(t *testing.T) {
+	srv, provider := newGitLab(t)
+	first := srv.AddMergeRequest(gitLabProject, gitlabfake.MergeRequest{Title: "first", Author: me(), SourceBranch: "a", TargetBranch: "main", Sha: "1"})
+	second := srv.AddMergeRequest(gitLabProject, gitlabfake.MergeRequest{Title: "second", Author: me(), SourceBranch: "b", TargetBranch: "main", Sha: "2"})
+	srv.AddPipeline(gitLabProject, second, "", gitlabfake.Job{Name: "test", Stage: "test", Status: "running"})
+
+	pr, err := provider.GetPullRequest("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pr.Merge(sv.MergeOptions{Method: "rebase"}); !errors.Is(err, sv.ErrNotSupported) {
+		t.Errorf("rebasing should not be supported, got %v", err)
+	}
+	if err := pr.Merge(sv.MergeOptions{Method: "squash", Title: "Squashed"}); err != nil {
+		t.Fatal(err)
+	}
+	if mr, _ := srv.MergeRequest(gitLabProject, first); mr.State != "merged" || !mr.Squash {
+		t.Errorf("the merge request should be squashed, got %s %v", mr.State, mr.Squash)
+	}
+	if err := pr.Merge(sv.MergeOptions{}); err == nil {
+		t.Error("merging twice should fail")
+	}
+
+	pr, err = provider.GetPullRequest("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pr.Merge(sv.MergeOptions{Auto: true}); err != nil {
+		t.Fatal(err)
+	}
+	if mr, _ := srv.MergeRequest(gitLabProject, second); mr.State != "opened" || !mr.MergeWhenPipelineSucceeds {
+		t.Errorf("the merge request should wait for its pipeline, got %s %v", mr.State, mr.MergeWhenPipelineSucceeds)
+	}
+}
+
+func TestGitLabCloseReopenAndDraft(t *testing.T) {
+	srv, provider := newGitLab(t)
+	iid := srv.AddMergeRequest(gitLabProject, gitlabfake.MergeRequest{Title: "mr", Author: me(), SourceBranch: "a", TargetBranch: "main"})
+	pr, err := provider.GetPullRequest("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pr.Close(); err != nil {
+		t.Fatal(err)
+	} else if mr, _ := srv.MergeRequest(gitLabProject, iid); mr.State != "closed" {
+		t.Errorf("expected closed, got %s", mr.State)
+	}
+	if err := pr.Reopen(); err != nil {
+		t.Fatal(err)
+	} else if mr, _ := srv.MergeRequest(gitLabProject, iid); mr.State != "opened" {
+		t.Errorf("expected opened, got %s", mr.State)
+	}
+	if err := pr.SetDraft(true); err != nil {
+		t.Fatal(err)
+	} else if mr, _ := srv.MergeRequest(gitLabProject, iid); !mr.Draft || mr.Title != "Draft: mr" {
+		t.Errorf("expected a draft, got %s", mr.Title)
+	}
+}
+
+func TestGitLabChecks(t *testing.T) {
+	srv, provider := newGitLab(t)
+	iid := srv.AddMergeRequest(gitLabProject, gitlabfake.MergeRequest{Title: "mr", Author: me(), SourceBranch: "a", TargetBranch: "main"})
+	srv.AddPipeline(gitLabProject, iid, "the log",
+		gitlabfake.Job{Name: "build", Stage: "build", Status: "success"},
+		gitlabfake.Job{Name: "test", Stage: "test", Status: "failed"})
+	pr, err := provider.GetPullRequest("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err := pr.GetCheckRuns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].GetName() != "build/build" || runs[1].GetConclusion() != "FAILURE" || !sv.IsFailedCheck(runs[1]) {
+		t.Fatalf("unexpected check runs %v", runs)
+	}
+	if log, err := pr.GetCheckLog(runs[1]); err != nil || string(log) != "the log" {
+		t.Errorf("unexpected log %q %v", log, err)
+	}
+	if err := pr.RerunChecks(runs[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if jobs := srv.Jobs(gitLabProject, iid); len(jobs) != 3 || jobs[2].Name != "test" || jobs[2].Status != "pending" {
+		t.Errorf("expected the failed job to be retried, got %v", jobs)
+	}
+}
@@ -0,0 +1,808 @@
+// Package gitlabfake is an in-memory GitLab server, implementing the part of the REST api used by sv.GitLabSv.
+// It allows exercising the provider offline:
+//
+//	srv := gitlabfake.NewServer("token", gitlabfake.User{Id: 1, Username: "me"})
+//	defer srv.Close()
+//	srv.AddProject("group/project", "main")
+//	provider := sv.NewGitLabSv("token", srv.ApiUrl(), srv.Client(), ".", ".*", "group/project")
+package gitlabfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type User struct {
+	Id       int    `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+type Project struct {
+	Id                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	DefaultBranch     string `json:"default_branch"`
+}
+
+type DiffRefs struct {
+	BaseSha  string `json:"base_sha"`
+	HeadSha  string `json:"head_sha"`
+	StartSha string `json:"start_sha"`
+}
+
+type Pipeline struct {
+	Id     int    `json:"id"`
+	Status string `json:"status"`
+	WebUrl string `json:"web_url"`
+}
+
+type References struct {
+	Full string `json:"full"`
+}
+
+type MergeRequest struct {
+	Id              int        `json:"id"`
+	Iid             int        `json:"iid"`
+	ProjectId       int        `json:"project_id"`
+	SourceProjectId int        `json:"source_project_id"`
+	Title           string     `json:"title"`
+	Description     string     `json:"description"`
+	State           string     `json:"state"`
+	Draft           bool       `json:"draft"`
+	CreatedAt       time.Time  `json:"created_at"`
+	Author          *User      `json:"author"`
+	SourceBranch    string     `json:"source_branch"`
+	TargetBranch    string     `json:"target_branch"`
+	Sha             string     `json:"sha"`
+	WebUrl          string     `json:"web_url"`
+	DiffRefs        *DiffRefs  `json:"diff_refs"`
+	HeadPipeline    *Pipeline  `json:"head_pipeline"`
+	Labels          []string   `json:"labels"`
+	Reviewers       []*User    `json:"reviewers"`
+	Assignees       []*User    `json:"assignees"`
+	References      References `json:"references"`
+	// MergeWhenPipelineSucceeds is set by a merge waiting for the pipeline, the merge request stays open
+	MergeWhenPipelineSucceeds bool `json:"merge_when_pipeline_succeeds"`
+	// Squash tells how the merge request was merged
+	Squash bool `json:"squash"`
+}
+
+type Position struct {
+	PositionType string `json:"position_type"`
+	BaseSha      string `json:"base_sha,omitempty"`
+	StartSha     string `json:"start_sha,omitempty"`
+	HeadSha      string `json:"head_sha,omitempty"`
+	OldPath      string `json:"old_path,omitempty"`
+	NewPath      string `json:"new_path,omitempty"`
+	OldLine      *int   `json:"old_line,omitempty"`
+	NewLine      *int   `json:"new_line,omitempty"`
+}
+
+type Note struct {
+	Id        int       `json:"id"`
+	Body      string    `json:"body"`
+	Author    *User     `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+	System    bool      `json:"system"`
+	Position  *Position `json:"position"`
+}
+
+type Discussion struct {
+	Id             string  `json:"id"`
+	IndividualNote bool    `json:"individual_note"`
+	Notes          []*Note `json:"notes"`
+}
+
+type Job struct {
+	Id         int        `json:"id"`
+	Name       string     `json:"name"`
+	Stage      string     `json:"stage"`
+	Status     string     `json:"status"`
+	WebUrl     string     `json:"web_url"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+
+	trace string
+}
+
+type project struct {
+	*Project
+	labels  []string
+	members []*User
+	mrs     []*mergeRequest
+	// jobs are the jobs of each pipeline
+	jobs map[int][]*Job
+}
+
+type mergeRequest struct {
+	*MergeRequest
+	approvedBy  []*User
+	discussions []*Discussion
+}
+
+// Server is the fake GitLab instance, all its methods are safe for concurrent use
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	nextId   int
+	tokens   map[string]*User
+	users    map[string]*User
+	projects map[string]*project
+}
+
+// NewServer starts a server where token authenticates user
+func NewServer(token string, user User) *Server {
+	s := &Server{
+		tokens:   make(map[string]*User),
+		users:    make(map[string]*User),
+		projects: make(map[string]*project),
+	}
+	s.AddUser(token, user)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// ApiUrl is the base url to give to sv.NewGitLabSv
+func (s *Server) ApiUrl() string {
+	return s.URL + "/api/v4"
+}
+
+func (s *Server) newId() int {
+	s.nextId += 1
+	return s.nextId
+}
+
+// AddUser registers another user, authenticated by token if not empty
+func (s *Server) AddUser(token string, user User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := user
+	s.users[u.Username] = &u
+	if len(token) > 0 {
+		s.tokens[token] = &u
+	}
+}
+
+// AddProject creates the project, fullPath includes the groups
+func (s *Server) AddProject(fullPath string, defaultBranch string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.projects[fullPath] = &project{
+		Project: &Project{Id: s.newId(), PathWithNamespace: fullPath, DefaultBranch: defaultBranch},
+		jobs:    make(map[int][]*Job),
+	}
+}
+
+// AddMember gives access to the project to an existing user
+func (s *Server) AddMember(fullPath string, username string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.projects[fullPath]
+	p.members = append(p.members, s.users[username])
+}
+
+func (s *Server) AddLabel(fullPath string, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.projects[fullPath]
+	p.labels = append(p.labels, name)
+}
+
+// AddMergeRequest stores mr in the project, its iid is assigned when zero and returned
+func (s *Server) AddMergeRequest(fullPath string, mr MergeRequest) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addMergeRequest(s.projects[fullPath], mr).Iid
+}
+
+func (s *Server) addMergeRequest(p *project, mr MergeRequest) *mergeRequest {
+	m := mr
+	m.Id = s.newId()
+	if m.Iid == 0 {
+		m.Iid = len(p.mrs) + 1
+	}
+	m.ProjectId = p.Id
+	if m.SourceProjectId == 0 {
+		m.SourceProjectId = p.Id
+	}
+	if len(m.State) == 0 {
+		m.State = "opened"
+	}
+	if len(m.WebUrl) == 0 {
+		m.WebUrl = fmt.Sprintf("%s/%s/-/merge_requests/%d", s.URL, p.PathWithNamespace, m.Iid)
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+	if m.Labels == nil {
+		m.Labels = make([]string, 0)
+	}
+	m.Draft = isDraftTitle(m.Title)
+	m.References.Full = fmt.Sprintf("%s!%d", p.PathWithNamespace, m.Iid)
+	res := &mergeRequest{MergeRequest: &m}
+	p.mrs = append(p.mrs, res)
+	return res
+}
+
+// AddPipeline runs the jobs as the head pipeline of the merge request, trace being the log of every job
+func (s *Server) AddPipeline(fullPath string, iid int, trace string, jobs ...Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.projects[fullPath]
+	if mr := findMergeRequest(p, iid); mr != nil {
+		pipeline := &Pipeline{Id: s.newId(), Status: "running"}
+		pipeline.WebUrl = fmt.Sprintf("%s/%s/-/pipelines/%d", s.URL, p.PathWithNamespace, pipeline.Id)
+		for i := range jobs {
+			j := jobs[i]
+			j.Id = s.newId()
+			j.trace = trace
+			p.jobs[pipeline.Id] = append(p.jobs[pipeline.Id], &j)
+		}
+		mr.HeadPipeline = pipeline
+	}
+}
+
+// MergeRequest returns a copy of the merge request, as currently stored
+func (s *Server) MergeRequest(fullPath string, iid int) (MergeRequest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if mr := findMergeRequest(s.projects[fullPath], iid); mr != nil {
+		return *mr.MergeRequest, true
+	}
+	return MergeRequest{}, false
+}
+
+// Discussions returns copies of the discussions of the merge request
+func (s *Server) Discussions(fullPath string, iid int) []Discussion {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Discussion, 0)
+	if mr := findMergeRequest(s.projects[fullPath], iid); mr != nil {
+		for _, d := range mr.discussions {
+			res = append(res, *d)
+		}
+	}
+	return res
+}
+
+// Jobs returns copies of the jobs of the head pipeline of the merge request, the retried ones included
+func (s *Server) Jobs(fullPath string, iid int) []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Job, 0)
+	p := s.projects[fullPath]
+	if mr := findMergeRequest(p, iid); mr != nil && mr.HeadPipeline != nil {
+		for _, j := range p.jobs[mr.HeadPipeline.Id] {
+			res = append(res, *j)
+		}
+	}
+	return res
+}
+
+func findMergeRequest(p *project, iid int) *mergeRequest {
+	if p == nil {
+		return nil
+	}
+	for _, mr := range p.mrs {
+		if mr.Iid == iid {
+			return mr
+		}
+	}
+	return nil
+}
+
+// findProject accepts the numeric id and the full path of the project
+func (s *Server) findProject(id string) *project {
+	if p, ok := s.projects[id]; ok {
+		return p
+	}
+	for _, p := range s.projects {
+		if strconv.Itoa(p.Id) == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func writeJson(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if body != nil {
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJson(w, status, map[string]string{"message": fmt.Sprintf(format, args...)})
+}
+
+// writePage applies the page and per_page parameters, setting X-Next-Page when more items follow
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 20
+	}
+	start := (page - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + perPage
+	if end < len(items) {
+		w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+	} else {
+		end = len(items)
+	}
+	writeJson(w, http.StatusOK, items[start:end])
+}
+
+// isDraftTitle tells the drafts by the prefix of their title, as GitLab does
+func isDraftTitle(title string) bool {
+	t := strings.ToLower(title)
+	return strings.HasPrefix(t, "draft:") || strings.HasPrefix(t, "[draft]") || strings.HasPrefix(t, "(draft)")
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.tokens[r.Header.Get("PRIVATE-TOKEN")]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "401 Unauthorized")
+		return
+	}
+
+	// The project paths are escaped, the raw path must be split before unescaping
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4"), "/"), "/")
+	for i := range parts {
+		if part, err := url.PathUnescape(parts[i]); err == nil {
+			parts[i] = part
+		}
+	}
+
+	switch {
+	case len(parts) == 1 && parts[0] == "user":
+		writeJson(w, http.StatusOK, user)
+	case len(parts) == 1 && parts[0] == "users":
+		users := make([]*User, 0)
+		if u, ok := s.users[r.URL.Query().Get("username")]; ok {
+			users = append(users, u)
+		}
+		writeJson(w, http.StatusOK, users)
+	case len(parts) == 1 && parts[0] == "merge_requests":
+		s.searchMergeRequests(w, r, user)
+	case len(parts) >= 2 && parts[0] == "projects":
+		if p := s.findProject(parts[1]); p == nil {
+			writeError(w, http.StatusNotFound, "404 Project Not Found")
+		} else {
+			s.serveProject(w, r, user, p, parts[2:])
+		}
+	default:
+		writeError(w, http.StatusNotFound, "404 Not Found")
+	}
+}
+
+func (s *Server) serveProject(w http.ResponseWriter, r *http.Request, user *User, p *project, parts []string) {
+	switch {
+	case len(parts) == 0:
+		writeJson(w, http.StatusOK, p.Project)
+	case len(parts) == 2 && parts[0] == "members" && parts[1] == "all":
+		writePage(w, r, p.members)
+	case len(parts) == 1 && parts[0] == "labels":
+		labels := make([]map[string]string, 0)
+		for _, l := range p.labels {
+			labels = append(labels, map[string]string{"name": l})
+		}
+		writePage(w, r, labels)
+	case len(parts) == 3 && parts[0] == "pipelines" && parts[2] == "jobs":
+		id, _ := strconv.Atoi(parts[1])
+		if jobs, ok := p.jobs[id]; !ok {
+			writeError(w, http.StatusNotFound, "404 Pipeline Not Found")
+		} else {
+			writePage(w, r, jobs)
+		}
+	case len(parts) == 3 && parts[0] == "jobs":
+		s.serveJob(w, r, p, parts[1], parts[2])
+	case len(parts) == 1 && parts[0] == "merge_requests" && r.Method == http.MethodGet:
+		s.listMergeRequests(w, r, p)
+	case len(parts) == 1 && parts[0] == "merge_requests" && r.Method == http.MethodPost:
+		s.createMergeRequest(w, r, user, p)
+	case len(parts) >= 2 && parts[0] == "merge_requests":
+		iid, _ := strconv.Atoi(parts[1])
+		if mr := findMergeRequest(p, iid); mr == nil {
+			writeError(w, http.StatusNotFound, "404 Not found")
+		} else {
+			s.serveMergeRequest(w, r, user, mr, parts[2:])
+		}
+	default:
+		writeError(w, http.StatusNotFound, "404 Not Found")
+	}
+}
+
+func (s *Server) serveJob(w http.ResponseWriter, r *http.Request, p *project, id string, action string) {
+	for pipeline, jobs := range p.jobs {
+		for _, j := range jobs {
+			if strconv.Itoa(j.Id) != id {
+				continue
+			}
+			switch {
+			case action == "trace" && r.Method == http.MethodGet:
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = w.Write([]byte(j.trace))
+			case action == "retry" && r.Method == http.MethodPost:
+				// GitLab creates a new job, the old one stays in the pipeline
+				retried := *j
+				retried.Id, retried.Status, retried.StartedAt, retried.FinishedAt = s.newId(), "pending", nil, nil
+				p.jobs[pipeline] = append(p.jobs[pipeline], &retried)
+				writeJson(w, http.StatusCreated, &retried)
+			default:
+				writeError(w, http.StatusNotFound, "404 Not Found")
+			}
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "404 Job Not Found")
+}
+
+// matches applies the filters of the merge request lists
+func matches(mr *mergeRequest, q url.Values) bool {
+	if state := q.Get("state"); state != "" && state != "all" && mr.State != state {
+		return false
+	}
+	if v := q.Get("source_branch"); v != "" && mr.SourceBranch != v {
+		return false
+	}
+	if v := q.Get("target_branch"); v != "" && mr.TargetBranch != v {
+		return false
+	}
+	if v := q.Get("author_username"); v != "" && (mr.Author == nil || mr.Author.Username != v) {
+		return false
+	}
+	if v := q.Get("reviewer_username"); v != "" && !hasUser(mr.Reviewers, v) {
+		return false
+	}
+	if v := q.Get("search"); v != "" && !strings.Contains(mr.Title, v) && !strings.Contains(mr.Description, v) {
+		return false
+	}
+	if v := q.Get("wip"); v != "" && mr.Draft != (v == "yes") {
+		return false
+	}
+	if v := q.Get("labels"); v != "" {
+		for _, l := range strings.Split(v, ",") {
+			if !hasLabel(mr.Labels, l) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func hasUser(users []*User, username string) bool {
+	for _, u := range users {
+		if u.Username == username {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLabel(labels []string, name string) bool {
+	for _, l := range labels {
+		if l == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) listMergeRequests(w http.ResponseWriter, r *http.Request, p *project) {
+	mrs := make([]*MergeRequest, 0)
+	for _, mr := range p.mrs {
+		if matches(mr, r.URL.Query()) {
+			mrs = append(mrs, mr.MergeRequest)
+		}
+	}
+	writePage(w, r, mrs)
+}
+
+// searchMergeRequests lists the merge requests of every project, the ones of the user for the created_by_me scope
+func (s *Server) searchMergeRequests(w http.ResponseWriter, r *http.Request, user *User) {
+	q := r.URL.Query()
+
+	paths := make([]string, 0, len(s.projects))
+	for path := range s.projects {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	mrs := make([]*MergeRequest, 0)
+	for _, path := range paths {
+		for _, mr := range s.projects[path].mrs {
+			if q.Get("scope") != "all" && (mr.Author == nil || mr.Author.Id != user.Id) {
+				continue
+			}
+			if matches(mr, q) {
+				mrs = append(mrs, mr.MergeRequest)
+			}
+		}
+	}
+	writePage(w, r, mrs)
+}
+
+func (s *Server) toUsers(ids []int) ([]*User, bool) {
+	users := make([]*User, 0)
+	for _, id := range ids {
+		found := false
+		for _, u := range s.users {
+			if u.Id == id {
+				users = append(users, u)
+				found = true
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return users, true
+}
+
+// addLabels adds the labels to the project as well, GitLab creates the missing ones
+func addLabels(p *project, labels []string, names string) []string {
+	for _, l := range strings.Split(names, ",") {
+		if l = strings.TrimSpace(l); l == "" {
+			continue
+		}
+		if !hasLabel(p.labels, l) {
+			p.labels = append(p.labels, l)
+		}
+		if !hasLabel(labels, l) {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
+func (s *Server) createMergeRequest(w http.ResponseWriter, r *http.Request, user *User, p *project) {
+	opts := struct {
+		SourceBranch string `json:"source_branch"`
+		TargetBranch string `json:"target_branch"`
+		Title        string `json:"title"`
+		Description  string `json:"description"`
+		Labels       string `json:"labels"`
+		ReviewerIds  []int  `json:"reviewer_ids"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	} else if opts.SourceBranch == "" || opts.TargetBranch == "" || opts.Title == "" {
+		writeError(w, http.StatusBadRequest, "source_branch, target_branch and title are required")
+		return
+	}
+	for _, mr := range p.mrs {
+		if mr.State == "opened" && mr.SourceBranch == opts.SourceBranch && mr.TargetBranch == opts.TargetBranch {
+			writeError(w, http.StatusConflict, "Another open merge request already exists for this source branch: !%d", mr.Iid)
+			return
+		}
+	}
+	reviewers, ok := s.toUsers(opts.ReviewerIds)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "reviewer_ids is invalid")
+		return
+	}
+
+	mr := s.addMergeRequest(p, MergeRequest{
+		Title:        opts.Title,
+		Description:  opts.Description,
+		Author:       user,
+		SourceBranch: opts.SourceBranch,
+		TargetBranch: opts.TargetBranch,
+		Labels:       addLabels(p, make([]string, 0), opts.Labels),
+		Reviewers:    reviewers,
+	})
+	writeJson(w, http.StatusCreated, mr.MergeRequest)
+}
+
+func (s *Server) serveMergeRequest(w http.ResponseWriter, r *http.Request, user *User, mr *mergeRequest, parts []string) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		writeJson(w, http.StatusOK, mr.MergeRequest)
+	case len(parts) == 0 && r.Method == http.MethodPut:
+		s.updateMergeRequest(w, r, mr)
+	case len(parts) == 1 && parts[0] == "merge" && r.Method == http.MethodPut:
+		s.merge(w, r, mr)
+	case len(parts) == 1 && parts[0] == "approvals" && r.Method == http.MethodGet:
+		approvals := struct {
+			ApprovedBy []map[string]*User `json:"approved_by"`
+			UpdatedAt  time.Time          `json:"updated_at"`
+		}{make([]map[string]*User, 0), time.Now()}
+		for _, u := range mr.approvedBy {
+			approvals.ApprovedBy = append(approvals.ApprovedBy, map[string]*User{"user": u})
+		}
+		writeJson(w, http.StatusOK, approvals)
+	case len(parts) == 1 && parts[0] == "approve" && r.Method == http.MethodPost:
+		if hasUser(mr.approvedBy, user.Username) {
+			writeError(w, http.StatusUnauthorized, "401 Unauthorized")
+			return
+		}
+		mr.approvedBy = append(mr.approvedBy, user)
+		writeJson(w, http.StatusCreated, nil)
+	case len(parts) == 1 && parts[0] == "unapprove" && r.Method == http.MethodPost:
+		if !hasUser(mr.approvedBy, user.Username) {
+			writeError(w, http.StatusNotFound, "404 Not Found")
+			return
+		}
+		approvedBy := make([]*User, 0)
+		for _, u := range mr.approvedBy {
+			if u.Id != user.Id {
+				approvedBy = append(approvedBy, u)
+			}
+		}
+		mr.approvedBy = approvedBy
+		writeJson(w, http.StatusCreated, nil)
+	case len(parts) == 1 && parts[0] == "notes" && r.Method == http.MethodPost:
+		note, ok := decodeNote(w, r, user, s.newId())
+		if !ok {
+			return
+		}
+		mr.discussions = append(mr.discussions, &Discussion{Id: s.discussionId(), IndividualNote: true, Notes: []*Note{note}})
+		writeJson(w, http.StatusCreated, note)
+	case len(parts) == 1 && parts[0] == "discussions" && r.Method == http.MethodGet:
+		writePage(w, r, mr.discussions)
+	case len(parts) == 1 && parts[0] == "discussions" && r.Method == http.MethodPost:
+		note, ok := decodeNote(w, r, user, s.newId())
+		if !ok {
+			return
+		}
+		if note.Position != nil && mr.DiffRefs == nil {
+			writeError(w, http.StatusBadRequest, "400 Bad request - Note {:line_code=>[\"can't be blank\"]}")
+			return
+		}
+		d := &Discussion{Id: s.discussionId(), Notes: []*Note{note}}
+		mr.discussions = append(mr.discussions, d)
+		writeJson(w, http.StatusCreated, d)
+	case len(parts) == 3 && parts[0] == "discussions" && parts[2] == "notes" && r.Method == http.MethodPost:
+		for _, d := range mr.discussions {
+			if d.Id == parts[1] {
+				if note, ok := decodeNote(w, r, user, s.newId()); ok {
+					// The replies share the position of the discussion
+					note.Position = d.Notes[0].Position
+					d.Notes = append(d.Notes, note)
+					writeJson(w, http.StatusCreated, note)
+				}
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "404 Discussion Not Found")
+	default:
+		writeError(w, http.StatusNotFound, "404 Not Found")
+	}
+}
+
+func (s *Server) discussionId() string {
+	return fmt.Sprintf("%040x", s.newId())
+}
+
+func decodeNote(w http.ResponseWriter, r *http.Request, user *User, id int) (*Note, bool) {
+	note := &Note{}
+	if err := json.NewDecoder(r.Body).Decode(note); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return nil, false
+	} else if strings.TrimSpace(note.Body) == "" {
+		writeError(w, http.StatusBadRequest, "body is missing")
+		return nil, false
+	}
+	note.Id, note.Author, note.CreatedAt = id, user, time.Now()
+	return note, true
+}
+
+func (s *Server) updateMergeRequest(w http.ResponseWriter, r *http.Request, mr *mergeRequest) {
+	opts := struct {
+		Title        *string `json:"title"`
+		Description  *string `json:"description"`
+		TargetBranch *string `json:"target_branch"`
+		StateEvent   *string `json:"state_event"`
+		AddLabels    string  `json:"add_labels"`
+		RemoveLabels string  `json:"remove_labels"`
+		ReviewerIds  *[]int  `json:"reviewer_ids"`
+		AssigneeIds  *[]int  `json:"assignee_ids"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	p := s.findProject(strconv.Itoa(mr.ProjectId))
+
+	if opts.StateEvent != nil {
+		switch {
+		case mr.State == "merged":
+			writeError(w, http.StatusMethodNotAllowed, "405 Method Not Allowed")
+			return
+		case *opts.StateEvent == "close":
+			mr.State = "closed"
+		case *opts.StateEvent == "reopen":
+			mr.State = "opened"
+		default:
+			writeError(w, http.StatusBadRequest, "state_event does not have a valid value")
+			return
+		}
+	}
+	if opts.Title != nil {
+		mr.Title = *opts.Title
+		mr.Draft = isDraftTitle(mr.Title)
+	}
+	if opts.Description != nil {
+		mr.Description = *opts.Description
+	}
+	if opts.TargetBranch != nil {
+		mr.TargetBranch = *opts.TargetBranch
+	}
+	mr.Labels = addLabels(p, mr.Labels, opts.AddLabels)
+	if opts.RemoveLabels != "" {
+		labels := make([]string, 0)
+		for _, l := range mr.Labels {
+			if !hasLabel(strings.Split(opts.RemoveLabels, ","), l) {
+				labels = append(labels, l)
+			}
+		}
+		mr.Labels = labels
+	}
+	for _, ids := range []struct {
+		ids   *[]int
+		users *[]*User
+	}{{opts.ReviewerIds, &mr.Reviewers}, {opts.AssigneeIds, &mr.Assignees}} {
+		if ids.ids == nil {
+			continue
+		} else if users, ok := s.toUsers(*ids.ids); !ok {
+			writeError(w, http.StatusBadRequest, "user ids are invalid")
+			return
+		} else {
+			*ids.users = users
+		}
+	}
+	writeJson(w, http.StatusOK, mr.MergeRequest)
+}
+
+func (s *Server) merge(w http.ResponseWriter, r *http.Request, mr *mergeRequest) {
+	opts := struct {
+		Sha                       string `json:"sha"`
+		Squash                    bool   `json:"squash"`
+		MergeWhenPipelineSucceeds bool   `json:"merge_when_pipeline_succeeds"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	switch {
+	case mr.State != "opened" || mr.Draft:
+		writeError(w, http.StatusMethodNotAllowed, "405 Method Not Allowed")
+	case opts.Sha != "" && opts.Sha != mr.Sha:
+		writeError(w, http.StatusConflict, "SHA does not match HEAD of source branch: %s", mr.Sha)
+	case opts.MergeWhenPipelineSucceeds && mr.HeadPipeline != nil:
+		mr.MergeWhenPipelineSucceeds = true
+		writeJson(w, http.StatusOK, mr.MergeRequest)
+	default:
+		mr.State, mr.Squash = "merged", opts.Squash
+		writeJson(w, http.StatusOK, mr.MergeRequest)
+	}
+}
@@ -18,6 +18,11 @@ type Paginated[T any, C any] interface {
 }
 
 func Paginate[T any, C any](ctx context.Context, pager Paginated[T, C]) <-chan T {
+	return PaginateMax(ctx, pager, 0)
+}
+
+// PaginateMax stops after maxPages pages, 0 for no limit
+func PaginateMax[T any, C any](ctx context.Context, pager Paginated[T, C], maxPages int) <-chan T {
 	container := pager.GetContainer()
 
 	c := make(chan T)
@@ -27,8 +32,10 @@ func Paginate[T any, C any](ctx context.Context, pager Paginated[T, C]) <-chan T
 	spinner.Start()
 
 	go func() {
+		defer close(c)
 
 		count := int32(0)
+		pages := 0
 
 		for cont := true; cont && count < pager.GetPages(); {
 			for _, val := range pager.GetValues() {
@@ -36,20 +43,13 @@ func Paginate[T any, C any](ctx context.Context, pager Paginated[T, C]) <-chan T
 				count += 1
 			}
 			spinner.UpdateText(fmt.Sprintf("Loaded page %d/%d", count, pager.GetPages()))
+			pages += 1
 
-			if len(pager.GetNext()) > 0 {
-				req, _ := http.NewRequestWithContext(ctx, "GET", pager.GetNext(), nil)
-				if auth, ok := ctx.Value(bitbucket.ContextBasicAuth).(bitbucket.BasicAuth); ok {
-					req.SetBasicAuth(auth.UserName, auth.Password)
-				}
-
-				resp2, _ := http.DefaultClient.Do(req)
-				bb, _ := ioutil.ReadAll(resp2.Body)
-
-				err := json.Unmarshal(bb, container)
-				if err != nil {
-					spinner.Fail("Error!")
-					return // pterm.Fatal.Println(err)
+			if len(pager.GetNext()) > 0 && (maxPages == 0 || pages < maxPages) {
+				if err := fetchPage(ctx, pager.GetNext(), container); err != nil {
+					// The values read so far are kept, the channel is closed as when the pages are done
+					spinner.Fail(err)
+					return
 				}
 				cont = true
 			} else {
@@ -57,8 +57,32 @@ func Paginate[T any, C any](ctx context.Context, pager Paginated[T, C]) <-chan T
 			}
 		}
 		spinner.Success("Finished")
-		close(c)
 	}()
 
 	return c
 }
+
+// fetchPage reads the next page in the container, with the basic auth of the context
+func fetchPage(ctx context.Context, next string, container interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", next, nil)
+	if err != nil {
+		return err
+	}
+	if auth, ok := ctx.Value(bitbucket.ContextBasicAuth).(bitbucket.BasicAuth); ok {
+		req.SetBasicAuth(auth.UserName, auth.Password)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	bb, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("cannot read the page %s, status code = %d", next, resp.StatusCode)
+	}
+	return json.Unmarshal(bb, container)
+}
@@ -0,0 +1,75 @@
+package sv_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/vballestra/sv/sv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// page is a paginated answer holding numbers, it is its own container
+type page struct {
+	Values []int  `json:"values"`
+	Next   string `json:"next"`
+	Size   int32  `json:"size"`
+}
+
+func (p *page) GetContainer() *page {
+	return p
+}
+
+func (p *page) GetNext() string {
+	return p.Next
+}
+
+func (p *page) GetPages() int32 {
+	return p.Size
+}
+
+func (p *page) GetValues() []int {
+	return p.Values
+}
+
+// collectPage reads the channel, failing when it isn't closed
+func collectPage(t *testing.T, c <-chan int) []int {
+	result := make([]int, 0)
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case v, ok := <-c:
+			if !ok {
+				return result
+			}
+			result = append(result, v)
+		case <-timeout:
+			t.Fatalf("the channel isn't closed, read %v", result)
+		}
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"values": [3, 4], "size": 4}`)
+	}))
+	defer srv.Close()
+
+	values := collectPage(t, sv.Paginate[int, page](context.Background(), &page{Values: []int{1, 2}, Next: srv.URL, Size: 4}))
+	if fmt.Sprint(values) != "[1 2 3 4]" {
+		t.Errorf("values = %v", values)
+	}
+}
+
+func TestPaginateClosesOnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not a page", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	values := collectPage(t, sv.Paginate[int, page](context.Background(), &page{Values: []int{1, 2}, Next: srv.URL, Size: 4}))
+	if fmt.Sprint(values) != "[1 2]" {
+		t.Errorf("values = %v", values)
+	}
+}
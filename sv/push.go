@@ -0,0 +1,128 @@
+package sv
+
+import (
+	"errors"
+	"fmt"
+	"github.com/briandowns/spinner"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/pterm/pterm"
+	"os"
+	"os/exec"
+	"regexp"
+	"time"
+)
+
+// pushBranch pushes the local branch to the remote when the remote lacks its commits, or doesn't have it at all,
+// then makes the remote branch its upstream. It returns whether it pushed.
+func pushBranch(localRepo string, sshKeySelector *regexp.Regexp, remote string, branch string, force bool) (bool, error) {
+	rep, err := git.PlainOpen(localRepo)
+	if err != nil {
+		return false, err
+	}
+	local, err := rep.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if err != nil {
+		return false, fmt.Errorf("cannot find the local branch %s: %w", branch, err)
+	}
+
+	pushed := false
+	if unpushed, err := hasUnpushedCommits(rep, remote, local); err != nil {
+		return false, err
+	} else if unpushed {
+		if err := pushRef(rep, localRepo, sshKeySelector, remote, local.Name(), force); err != nil {
+			return false, err
+		}
+		pushed = true
+	}
+
+	return pushed, setUpstream(rep, remote, branch)
+}
+
+// hasUnpushedCommits tells whether the remote branch, as last fetched, misses commits of the local branch
+func hasUnpushedCommits(rep *git.Repository, remoteName string, local *plumbing.Reference) (bool, error) {
+	remote, err := rep.Reference(plumbing.NewRemoteReferenceName(remoteName, local.Name().Short()), true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	} else if remote.Hash() == local.Hash() {
+		return false, nil
+	}
+
+	localCommit, err := rep.CommitObject(local.Hash())
+	if err != nil {
+		return false, err
+	}
+	remoteCommit, err := rep.CommitObject(remote.Hash())
+	if err != nil {
+		return false, err
+	}
+	behind, err := localCommit.IsAncestor(remoteCommit)
+	return !behind, err
+}
+
+// pushRef pushes the branch with the ssh agent, then with git when it fails (https remotes, no agent...). With force
+// the remote branch is only overwritten when it's still on the commit last fetched, as with --force-with-lease.
+func pushRef(rep *git.Repository, localRepo string, sshKeySelector *regexp.Regexp, remote string, ref plumbing.ReferenceName, force bool) error {
+	spec := config.RefSpec(fmt.Sprintf("%s:%s", ref, ref))
+	var lease []config.RefSpec
+	args := []string{"push", remote, spec.String()}
+	if force {
+		// A branch missing from the remote is created without forcing, the push fails if someone else created it
+		if tracked, err := rep.Reference(plumbing.NewRemoteReferenceName(remote, ref.Short()), true); err == nil {
+			lease = []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", tracked.Hash(), ref))}
+			args = []string{"push", fmt.Sprintf("--force-with-lease=%s:%s", ref, tracked.Hash()), remote, spec.String()}
+			spec = "+" + spec
+		} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return err
+		}
+	}
+
+	err := func() error {
+		ag, err := agentAuth(sshKeySelector)
+		if err != nil {
+			return err
+		}
+		sp := spinner.New(spinner.CharSets[55], time.Millisecond*50, spinner.WithSuffix(fmt.Sprintf(" Pushing %s", ref.Short())),
+			spinner.WithWriter(os.Stderr))
+		sp.Start()
+		defer sp.Stop()
+		return rep.Push(&git.PushOptions{RemoteName: remote, RefSpecs: []config.RefSpec{spec}, RequireRemoteRefs: lease, Auth: ag})
+	}()
+
+	if err == nil || errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil
+	} else if errors.Is(err, git.ErrForceNeeded) {
+		return fmt.Errorf("cannot push %s, the %s branch has commits missing locally: %w", ref.Short(), remote, err)
+	}
+	pterm.Debug.Printfln("cannot push %s with the ssh agent, trying git: %v", ref.Short(), err)
+
+	path, err := exec.LookPath("git")
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(path, args...)
+	cmd.Dir = localRepo
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("cannot push %s: %w\n%s", ref.Short(), err, out)
+	}
+	return nil
+}
+
+// setUpstream makes the branch of the same name on the remote the upstream of the local branch
+func setUpstream(rep *git.Repository, remote string, branch string) error {
+	cfg, err := rep.Config()
+	if err != nil {
+		return err
+	}
+	merge := plumbing.NewBranchReferenceName(branch)
+	if b, ok := cfg.Branches[branch]; !ok {
+		cfg.Branches[branch] = &config.Branch{Name: branch, Remote: remote, Merge: merge}
+	} else if b.Remote == remote && b.Merge == merge {
+		return nil
+	} else {
+		b.Remote, b.Merge = remote, merge
+	}
+	return rep.SetConfig(cfg)
+}
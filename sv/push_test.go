@@ -0,0 +1,130 @@
+package sv_test
+
+import (
+	"github.com/antihax/optional"
+	"github.com/vballestra/sv/sv"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// git runs git in the directory, failing the test when it fails
+func git(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=me", "GIT_AUTHOR_EMAIL=me@example.com",
+		"GIT_COMMITTER_NAME=me", "GIT_COMMITTER_EMAIL=me@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestPushBranchToTheRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is missing")
+	}
+	// Without the ssh agent, sv pushes with git
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	dir := t.TempDir()
+	remote, local := filepath.Join(dir, "remote.git"), filepath.Join(dir, "local")
+	git(t, dir, "init", "--bare", remote)
+	git(t, dir, "init", "-b", "main", local)
+	git(t, local, "remote", "add", "upstream", remote)
+	git(t, local, "commit", "--allow-empty", "-m", "first")
+	git(t, local, "checkout", "-b", "feature")
+	git(t, local, "commit", "--allow-empty", "-m", "second")
+
+	provider := sv.NewGiteaSv("token", "http://localhost", nil, local, ".*", "me", "repo")
+	if pushed, err := provider.PushBranch("upstream", "feature", false); err != nil {
+		t.Fatal(err)
+	} else if !pushed {
+		t.Error("the branch wasn't pushed")
+	}
+
+	if head, pushed := git(t, local, "rev-parse", "feature"), git(t, remote, "rev-parse", "feature"); head != pushed {
+		t.Errorf("the remote is on %s, not on %s", pushed, head)
+	}
+	if upstream := git(t, local, "config", "branch.feature.remote"); upstream != "upstream" {
+		t.Errorf("the upstream remote is %s", upstream)
+	}
+
+	git(t, local, "fetch", "upstream")
+	if pushed, err := provider.PushBranch("upstream", "feature", false); err != nil {
+		t.Fatal(err)
+	} else if pushed {
+		t.Error("the branch was pushed again")
+	}
+}
+
+func TestForcePushWithLease(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is missing")
+	}
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	dir := t.TempDir()
+	remote, local, other := filepath.Join(dir, "remote.git"), filepath.Join(dir, "local"), filepath.Join(dir, "other")
+	git(t, dir, "init", "--bare", remote)
+	git(t, dir, "init", "-b", "main", local)
+	git(t, local, "remote", "add", "origin", remote)
+	git(t, local, "commit", "--allow-empty", "-m", "first")
+	git(t, local, "push", "origin", "main")
+	git(t, local, "checkout", "-b", "feature")
+	git(t, local, "commit", "--allow-empty", "-m", "second")
+	git(t, local, "push", "origin", "feature")
+
+	provider := sv.NewGiteaSv("token", "http://localhost", nil, local, ".*", "me", "repo")
+
+	// Rewritten locally, the remote branch being where it was fetched
+	git(t, local, "commit", "--amend", "--allow-empty", "-m", "second, amended")
+	if _, err := provider.PushBranch("origin", "feature", true); err != nil {
+		t.Fatal(err)
+	}
+	if head, pushed := git(t, local, "rev-parse", "feature"), git(t, remote, "rev-parse", "feature"); head != pushed {
+		t.Errorf("the remote is on %s, not on %s", pushed, head)
+	}
+
+	// Someone else pushes in between
+	git(t, dir, "clone", "-b", "feature", remote, other)
+	git(t, other, "commit", "--allow-empty", "-m", "theirs")
+	git(t, other, "push", "origin", "feature")
+	theirs := git(t, other, "rev-parse", "HEAD")
+
+	git(t, local, "commit", "--amend", "--allow-empty", "-m", "second, amended again")
+	if _, err := provider.PushBranch("origin", "feature", true); err == nil {
+		t.Error("the commit pushed by someone else was overwritten")
+	}
+	if pushed := git(t, remote, "rev-parse", "feature"); pushed != theirs {
+		t.Errorf("the remote is on %s, not on %s", pushed, theirs)
+	}
+}
+
+func TestResolveTitleFromTheRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is missing")
+	}
+
+	dir := t.TempDir()
+	remote, local := filepath.Join(dir, "remote.git"), filepath.Join(dir, "local")
+	git(t, dir, "init", "--bare", remote)
+	git(t, dir, "init", "-b", "main", local)
+	git(t, local, "remote", "add", "upstream", remote)
+	git(t, local, "commit", "--allow-empty", "-m", "first")
+	git(t, local, "checkout", "-b", "feature")
+	git(t, local, "commit", "--allow-empty", "-m", "the feature")
+	git(t, local, "push", "upstream", "main", "feature")
+	git(t, local, "checkout", "main")
+	git(t, local, "branch", "-D", "feature")
+
+	provider := sv.NewGiteaSv("token", "http://localhost", nil, local, ".*", "me", "repo")
+	if title, _, err := provider.ResolveTitleAndDescription("upstream", optional.EmptyString(), optional.EmptyString(), "feature", "main"); err != nil {
+		t.Fatal(err)
+	} else if title != "the feature" {
+		t.Errorf("title = %q", title)
+	}
+}
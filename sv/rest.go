@@ -0,0 +1,147 @@
+package sv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// restClient is a minimal json client for the REST apis that don't come with a generated one
+type restClient struct {
+	baseUrl    string
+	authHeader string
+	authValue  string
+	http       *http.Client
+}
+
+func newRestClient(baseUrl string, authHeader string, authValue string, httpClient *http.Client) *restClient {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	return &restClient{
+		baseUrl:    strings.TrimSuffix(baseUrl, "/"),
+		authHeader: authHeader,
+		authValue:  authValue,
+		http:       httpClient,
+	}
+}
+
+// wrapTransport replaces the http client by a copy whose transport is wrapped, the given client may be shared
+func (c *restClient) wrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	httpClient := *c.http
+	httpClient.Transport = wrap(httpClient.Transport)
+	c.http = &httpClient
+}
+
+type ApiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ApiError) Error() string {
+	return fmt.Sprintf("api error %d: %s", e.StatusCode, e.Message)
+}
+
+func (c *restClient) do(ctx context.Context, method string, path string, query url.Values, body interface{}, result interface{}) (*http.Response, error) {
+	u := c.baseUrl + path
+	if len(query) > 0 {
+		u = u + "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		if b, err := json.Marshal(body); err != nil {
+			return nil, err
+		} else {
+			reader = bytes.NewReader(b)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	raw, isRaw := result.(*[]byte)
+	req.Header.Set(c.authHeader, c.authValue)
+	if isRaw {
+		req.Header.Set("Accept", "*/*")
+	} else {
+		req.Header.Set("Accept", "application/json")
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode >= 300 {
+		return resp, &ApiError{resp.StatusCode, strings.TrimSpace(string(data))}
+	}
+
+	if isRaw {
+		*raw = data
+	} else if result != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, result); err != nil {
+			return resp, err
+		}
+	}
+
+	return resp, nil
+}
+
+// getRaw returns the body as is, for the text contents like the logs
+func (c *restClient) getRaw(ctx context.Context, path string) ([]byte, error) {
+	data := make([]byte, 0)
+	_, err := c.do(ctx, http.MethodGet, path, nil, nil, &data)
+	return data, err
+}
+
+func (c *restClient) get(ctx context.Context, path string, query url.Values, result interface{}) (*http.Response, error) {
+	return c.do(ctx, http.MethodGet, path, query, nil, result)
+}
+
+func (c *restClient) post(ctx context.Context, path string, body interface{}, result interface{}) (*http.Response, error) {
+	return c.do(ctx, http.MethodPost, path, nil, body, result)
+}
+
+func (c *restClient) delete(ctx context.Context, path string) (*http.Response, error) {
+	return c.do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+func (c *restClient) put(ctx context.Context, path string, body interface{}, result interface{}) (*http.Response, error) {
+	return c.do(ctx, http.MethodPut, path, nil, body, result)
+}
+
+func (c *restClient) patch(ctx context.Context, path string, body interface{}, result interface{}) (*http.Response, error) {
+	return c.do(ctx, http.MethodPatch, path, nil, body, result)
+}
+
+type itemOrError[T any] struct {
+	item T
+	err  error
+}
+
+func collectItems[T any](ch <-chan itemOrError[T]) ([]T, error) {
+	res := make([]T, 0)
+	for itm := range ch {
+		if itm.err != nil {
+			return nil, itm.err
+		}
+		res = append(res, itm.item)
+	}
+	return res, nil
+}
@@ -0,0 +1,220 @@
+package sv
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pterm/pterm"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Stack is a chain of dependent branches, each one based on the previous one and the first one on the trunk
+type Stack struct {
+	Trunk    string
+	Branches []string
+	// Heads are the commits of the branches when the stack was discovered
+	Heads map[string]string
+}
+
+// Parent is the branch the i-th branch of the stack is based on
+func (s Stack) Parent(i int) string {
+	if i == 0 {
+		return s.Trunk
+	}
+	return s.Branches[i-1]
+}
+
+// Index is the position of the branch in the stack, -1 when it isn't part of it
+func (s Stack) Index(branch string) int {
+	for i, b := range s.Branches {
+		if b == branch {
+			return i
+		}
+	}
+	return -1
+}
+
+// DiscoverStack finds the stack of the branch from the local history: the parent of a branch is the nearest local
+// branch whose head is one of its commits missing from the trunk. Below the branch the stack goes down to the trunk,
+// above it the stack goes up as long as a single branch is based on the previous one. The trunk is read from the
+// remote first.
+func DiscoverStack(localRepo string, remote string, trunk string, branch string) (Stack, error) {
+	stack := Stack{Trunk: trunk, Heads: make(map[string]string)}
+
+	rep, err := git.PlainOpen(localRepo)
+	if err != nil {
+		return stack, err
+	}
+	trunkHead, err := trunkCommit(rep, remote, trunk)
+	if err != nil {
+		return stack, err
+	}
+
+	// The heads and the own commits of the branches having commits missing from the trunk
+	heads := make(map[string]plumbing.Hash)
+	own := make(map[string]map[plumbing.Hash]bool)
+	refs, err := rep.Branches()
+	if err != nil {
+		return stack, err
+	}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if name == trunk {
+			return nil
+		}
+		if c, err := rep.CommitObject(ref.Hash()); err != nil {
+			return err
+		} else if commits, err := ownCommits(c, trunkHead); err != nil {
+			return err
+		} else if len(commits) > 0 {
+			heads[name], own[name] = ref.Hash(), commits
+		}
+		return nil
+	})
+	if err != nil {
+		return stack, err
+	}
+	if _, ok := heads[branch]; !ok {
+		return stack, fmt.Errorf("branch %s has no commits missing from %s", branch, trunk)
+	}
+
+	names := make([]string, 0, len(heads))
+	for name := range heads {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parents := make(map[string]string)
+	children := make(map[string][]string)
+	for _, name := range names {
+		parent := nearestParent(name, trunk, names, heads, own)
+		parents[name] = parent
+		children[parent] = append(children[parent], name)
+	}
+
+	for b := branch; b != trunk; b = parents[b] {
+		stack.Branches = append([]string{b}, stack.Branches...)
+	}
+	for b := branch; len(children[b]) > 0; {
+		if len(children[b]) > 1 {
+			pterm.Warning.Printfln("The stack forks after %s (%s), stopping there", b, strings.Join(children[b], ", "))
+			break
+		}
+		b = children[b][0]
+		stack.Branches = append(stack.Branches, b)
+	}
+	for _, b := range stack.Branches {
+		stack.Heads[b] = heads[b].String()
+	}
+	return stack, nil
+}
+
+// trunkCommit is the head of the trunk on the remote, the local one if unknown, since the local trunk is often late
+func trunkCommit(rep *git.Repository, remote string, trunk string) (*object.Commit, error) {
+	ref, err := rep.Reference(plumbing.NewRemoteReferenceName(remote, trunk), true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		ref, err = rep.Reference(plumbing.NewBranchReferenceName(trunk), true)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot find the trunk %s: %w", trunk, err)
+	}
+	return rep.CommitObject(ref.Hash())
+}
+
+// ownCommits are the commits of the branch missing from the trunk, the walk stops at their merge bases
+func ownCommits(head *object.Commit, trunk *object.Commit) (map[plumbing.Hash]bool, error) {
+	mbs, err := head.MergeBase(trunk)
+	if err != nil {
+		return nil, err
+	}
+	ignore := make([]plumbing.Hash, 0, len(mbs))
+	for _, mb := range mbs {
+		ignore = append(ignore, mb.Hash)
+	}
+
+	commits := make(map[plumbing.Hash]bool)
+	err = object.NewCommitPreorderIter(head, nil, ignore).ForEach(func(c *object.Commit) error {
+		commits[c.Hash] = true
+		return nil
+	})
+	return commits, err
+}
+
+// nearestParent is the branch whose head is the latest own commit of the branch, the trunk when there is none
+func nearestParent(branch string, trunk string, names []string, heads map[string]plumbing.Hash, own map[string]map[plumbing.Hash]bool) string {
+	candidates := make([]string, 0)
+	for _, name := range names {
+		if name != branch && heads[name] != heads[branch] && own[branch][heads[name]] {
+			candidates = append(candidates, name)
+		}
+	}
+
+	// The nearest one holds the heads of all the others
+	for _, c := range candidates {
+		nearest := true
+		for _, other := range candidates {
+			if other != c && !own[c][heads[other]] {
+				nearest = false
+				break
+			}
+		}
+		if nearest {
+			return c
+		}
+	}
+	if len(candidates) > 0 {
+		pterm.Warning.Printfln("Cannot tell which of %s is the parent of %s, taking %s", strings.Join(candidates, ", "), branch, candidates[0])
+		return candidates[0]
+	}
+	return trunk
+}
+
+// RebaseBranch moves the commits of the branch that aren't in upstream onto the onto commit, with git since go-git
+// can't rebase. A conflicting rebase is aborted, the current branch is checked out again in the end.
+func RebaseBranch(localRepo string, branch string, upstream string, onto string) error {
+	path, err := exec.LookPath("git")
+	if err != nil {
+		return err
+	}
+	current, err := getCurrentBranch(localRepo)
+	if err != nil {
+		return err
+	}
+	run := func(args ...string) ([]byte, error) {
+		cmd := exec.Command(path, args...)
+		cmd.Dir = localRepo
+		return cmd.CombinedOutput()
+	}
+
+	if out, err := run("rebase", "--onto", onto, upstream, branch); err != nil {
+		_, _ = run("rebase", "--abort")
+		return fmt.Errorf("cannot rebase %s onto %s, rebase it by hand: %w\n%s", branch, onto, err, out)
+	} else if current == branch {
+		return nil
+	} else if out, err := run("checkout", current); err != nil {
+		return fmt.Errorf("cannot check %s out again: %w\n%s", current, err, out)
+	}
+	return nil
+}
+
+const (
+	stackTableStart = "<!-- sv-stack -->"
+	stackTableEnd   = "<!-- /sv-stack -->"
+)
+
+var stackTableRegexp = regexp.MustCompile(`(?s)\n*` + regexp.QuoteMeta(stackTableStart) + `.*?` + regexp.QuoteMeta(stackTableEnd) + `\n*`)
+
+// WithStackTable puts the table at the end of the description, in place of the previous table if any
+func WithStackTable(description string, table string) string {
+	description = strings.TrimSpace(stackTableRegexp.ReplaceAllString(description, "\n\n"))
+	block := stackTableStart + "\n" + strings.TrimSpace(table) + "\n" + stackTableEnd
+	if description == "" {
+		return block
+	}
+	return description + "\n\n" + block
+}
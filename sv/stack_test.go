@@ -0,0 +1,36 @@
+package sv_test
+
+import (
+	"github.com/vballestra/sv/sv"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiscoverStackOnTheRemoteTrunk(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is missing")
+	}
+
+	dir := t.TempDir()
+	remote, local := filepath.Join(dir, "remote.git"), filepath.Join(dir, "local")
+	git(t, dir, "init", "--bare", remote)
+	git(t, dir, "init", "-b", "main", local)
+	git(t, local, "remote", "add", "upstream", remote)
+	git(t, local, "commit", "--allow-empty", "-m", "first")
+	git(t, local, "checkout", "-b", "merged")
+	git(t, local, "commit", "--allow-empty", "-m", "merged")
+	git(t, local, "checkout", "-b", "feature")
+	git(t, local, "commit", "--allow-empty", "-m", "feature")
+	// The first branch is merged upstream, the local trunk is late
+	git(t, local, "push", "upstream", "merged:main")
+
+	stack, err := sv.DiscoverStack(local, "upstream", "main", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"feature"}; !reflect.DeepEqual(stack.Branches, want) {
+		t.Errorf("stack = %v, want %v", stack.Branches, want)
+	}
+}
@@ -0,0 +1,124 @@
+package sv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/bluekeyes/go-gitdiff/gitdiff"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ViewedFilesTracker remembers the files of a pull request the user has marked as viewed, so that a review can be
+// done in several sessions
+type ViewedFilesTracker interface {
+	// GetViewedFiles returns the paths of the viewed files
+	GetViewedFiles() (map[string]bool, error)
+	SetFileViewed(path string, viewed bool) error
+}
+
+// ViewedFiles returns the tracker of the viewed files of the pull request, the pull request itself when its provider
+// keeps them, a file under the cache dir otherwise. The files are the diff of the pull request, a file marked as viewed
+// locally isn't anymore once it changed.
+func ViewedFiles(pr PullRequest, files []*gitdiff.File) (ViewedFilesTracker, error) {
+	if tracker, ok := pr.(ViewedFilesTracker); ok {
+		return tracker, nil
+	} else if dir, err := DefaultCacheDir(); err != nil {
+		return nil, err
+	} else {
+		h := sha256.Sum256([]byte(pr.GetUrl()))
+		return localViewedFiles{filepath.Join(dir, "viewed", hex.EncodeToString(h[:])+".json"),
+			fileVersions(files, pr.GetLastCommitId())}, nil
+	}
+}
+
+// fileVersions maps the old and new paths of the files to the blob they have in the pull request, or to the head
+// commit when the diff doesn't tell it
+func fileVersions(files []*gitdiff.File, head string) map[string]string {
+	versions := make(map[string]string)
+	for _, f := range files {
+		version := f.NewOIDPrefix
+		if f.IsDelete {
+			version = f.OldOIDPrefix
+		}
+		// The blob of a missing side is all zeros
+		if strings.Trim(version, "0") == "" {
+			version = head
+		}
+		for _, path := range []string{f.OldName, f.NewName} {
+			if path != "" {
+				versions[path] = version
+			}
+		}
+	}
+	return versions
+}
+
+// localViewedFiles keeps the viewed files of a pull request in a json file, with the version they were viewed at
+type localViewedFiles struct {
+	path     string
+	versions map[string]string
+}
+
+// marks reads the viewed files with their version, the marks without a version are dropped
+func (l localViewedFiles) marks() (map[string]string, error) {
+	marks := make(map[string]interface{})
+	if b, err := os.ReadFile(l.path); errors.Is(err, os.ErrNotExist) {
+		return make(map[string]string), nil
+	} else if err != nil {
+		return nil, err
+	} else if err := json.Unmarshal(b, &marks); err != nil {
+		return nil, err
+	}
+	versions := make(map[string]string)
+	for path, v := range marks {
+		if version, ok := v.(string); ok {
+			versions[path] = version
+		}
+	}
+	return versions, nil
+}
+
+// GetViewedFiles returns the files viewed at their current version
+func (l localViewedFiles) GetViewedFiles() (map[string]bool, error) {
+	marks, err := l.marks()
+	if err != nil {
+		return nil, err
+	}
+	viewed := make(map[string]bool)
+	for path, version := range marks {
+		if version == l.versions[path] {
+			viewed[path] = true
+		}
+	}
+	return viewed, nil
+}
+
+// SetFileViewed marks the current version of the file, the marks of the files changed since they were viewed are
+// dropped
+func (l localViewedFiles) SetFileViewed(path string, viewed bool) error {
+	marks, err := l.marks()
+	if err != nil {
+		return err
+	}
+	for p, version := range marks {
+		if version != l.versions[p] {
+			delete(marks, p)
+		}
+	}
+	if viewed {
+		marks[path] = l.versions[path]
+	} else {
+		delete(marks, path)
+	}
+
+	if b, err := json.Marshal(marks); err != nil {
+		return err
+	} else if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return err
+	} else {
+		return os.WriteFile(l.path, b, 0o600)
+	}
+}
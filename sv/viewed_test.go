@@ -0,0 +1,82 @@
+package sv_test
+
+import (
+	"fmt"
+	"github.com/vballestra/sv/sv"
+	"github.com/vballestra/sv/sv/giteafake"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// viewedPullRequest reads the tracker of a pull request of the fake, its commits being in the local repository
+func viewedPullRequest(t *testing.T, srv *giteafake.Server, provider sv.Sv, base string, head string) sv.ViewedFilesTracker {
+	number := srv.AddPullRequest(giteaRepo, giteafake.PullRequest{Title: "viewed", User: giteaAlice(), MergeBase: base,
+		HtmlUrl: "https://gitea.example.com/me/repo/pulls/1", Head: &giteafake.BranchRef{Label: "feature", Ref: "feature", Sha: head},
+		Base: &giteafake.BranchRef{Label: "main", Ref: "main", Sha: base}})
+	pr, err := provider.GetPullRequest(fmt.Sprint(number))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := pr.GetDiff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker, err := sv.ViewedFiles(pr, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tracker
+}
+
+func TestViewedFilesAreDroppedOnceChanged(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is missing")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	local := t.TempDir()
+	write := func(name string, content string) {
+		if err := os.WriteFile(filepath.Join(local, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git(t, local, "init", "-b", "main")
+	write("a.txt", "a\n")
+	write("b.txt", "b\n")
+	git(t, local, "add", ".")
+	git(t, local, "commit", "-m", "base")
+	base := git(t, local, "rev-parse", "HEAD")
+	git(t, local, "checkout", "-b", "feature")
+	write("a.txt", "a, changed\n")
+	write("b.txt", "b, changed\n")
+	git(t, local, "commit", "-am", "change both")
+	first := git(t, local, "rev-parse", "HEAD")
+
+	srv, _ := newGitea(t)
+	provider := sv.NewGiteaSv("token", srv.ApiUrl(), srv.Client(), local, ".*", "me", "repo")
+
+	tracker := viewedPullRequest(t, srv, provider, base, first)
+	for _, path := range []string{"a.txt", "b.txt"} {
+		if err := tracker.SetFileViewed(path, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if viewed, err := tracker.GetViewedFiles(); err != nil {
+		t.Fatal(err)
+	} else if !viewed["a.txt"] || !viewed["b.txt"] {
+		t.Errorf("viewed = %v", viewed)
+	}
+
+	write("a.txt", "a, changed again\n")
+	git(t, local, "commit", "-am", "change a")
+	second := git(t, local, "rev-parse", "HEAD")
+
+	tracker = viewedPullRequest(t, srv, provider, base, second)
+	if viewed, err := tracker.GetViewedFiles(); err != nil {
+		t.Fatal(err)
+	} else if viewed["a.txt"] || !viewed["b.txt"] {
+		t.Errorf("viewed = %v, want only b.txt", viewed)
+	}
+}